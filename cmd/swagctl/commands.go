@@ -0,0 +1,883 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/client"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/comment"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/dependency"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+func (this *cli) services(args []string) error {
+	ctx := context.Background()
+	if len(args) == 0 {
+		return &usageError{"services: subcommand is required"}
+	}
+	switch args[0] {
+	case "list":
+		flags := flag.NewFlagSet("services list", flag.ContinueOnError)
+		flags.SetOutput(this.stderr)
+		namespace := flags.String("namespace", "", "list the services of a namespace (organization/team) only")
+		if err := flags.Parse(args[1:]); err != nil {
+			return &usageError{err.Error()}
+		}
+		var services []servicedb.ServiceEntity
+		var err error
+		if *namespace != "" {
+			services, err = this.api.ListNamespaceServices(ctx, *namespace)
+		} else {
+			services, err = this.api.ListServices(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		return this.printServices(services)
+	case "create":
+		flags := flag.NewFlagSet("services create", flag.ContinueOnError)
+		flags.SetOutput(this.stderr)
+		namespace := flags.String("namespace", "", "namespace (organization/team) of the service")
+		if err := flags.Parse(args[1:]); err != nil {
+			return &usageError{err.Error()}
+		}
+		if flags.NArg() != 1 {
+			return &usageError{"services create: <name> is required"}
+		}
+		service, err := this.api.CreateNamespaceService(ctx, *namespace, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		return this.printServices([]servicedb.ServiceEntity{*service})
+	case "rename":
+		if len(args) != 3 {
+			return &usageError{"services rename: <serviceId> <name> are required"}
+		}
+		if err := this.api.RenameService(ctx, args[1], args[2]); err != nil {
+			return err
+		}
+		fmt.Fprintf(this.stderr, "service %s was renamed to %s\n", args[1], args[2])
+		return nil
+	case "delete":
+		if len(args) != 2 {
+			return &usageError{"services delete: <serviceId> is required"}
+		}
+		if err := this.api.DeleteService(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintf(this.stderr, "service %s was deleted\n", args[1])
+		return nil
+	case "lint-rules":
+		if len(args) != 3 {
+			return &usageError{"services lint-rules: <serviceId> <file> are required"}
+		}
+		rules, err := ioutil.ReadFile(args[2])
+		if err != nil {
+			return err
+		}
+		if _, err := this.api.UpdateLintRules(ctx, args[1], string(rules)); err != nil {
+			return err
+		}
+		fmt.Fprintf(this.stderr, "lint rules of service %s were updated\n", args[1])
+		return nil
+	case "depend":
+		if len(args) < 2 {
+			return &usageError{"services depend: <serviceId> is required"}
+		}
+		dependencies := []servicedb.Dependency{}
+		for _, arg := range args[2:] {
+			parts := strings.SplitN(arg, "@", 2)
+			d := servicedb.Dependency{ServiceId: parts[0], Range: "*"}
+			if len(parts) == 2 {
+				d.Range = parts[1]
+			}
+			dependencies = append(dependencies, d)
+		}
+		if _, err := this.api.UpdateDependencies(ctx, args[1], dependencies); err != nil {
+			return err
+		}
+		fmt.Fprintf(this.stderr, "dependencies of service %s were updated\n", args[1])
+		return nil
+	case "register":
+		return this.register(args[1:])
+	case "review-policy":
+		flags := flag.NewFlagSet("services review-policy", flag.ContinueOnError)
+		flags.SetOutput(this.stderr)
+		approvals := flags.Int("approvals", 1, "approvals required. 0 removes the policy")
+		self := flags.Bool("self-approval", false, "the uploader may approve the version")
+		if err := flags.Parse(args[1:]); err != nil {
+			return &usageError{err.Error()}
+		}
+		if flags.NArg() < 1 {
+			return &usageError{"services review-policy: <serviceId> is required"}
+		}
+		policy := servicedb.ReviewPolicy{Approvals: *approvals, Reviewers: flags.Args()[1:], SelfApproval: *self}
+		if _, err := this.api.UpdateReviewPolicy(ctx, flags.Arg(0), policy); err != nil {
+			return err
+		}
+		fmt.Fprintf(this.stderr, "review policy of service %s was updated\n", flags.Arg(0))
+		return nil
+	case "consumers":
+		if len(args) != 2 {
+			return &usageError{"services consumers: <serviceId> is required"}
+		}
+		edges, err := this.api.ListConsumers(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		return this.printEdges(edges)
+	}
+	return &usageError{fmt.Sprintf("services: unknown subcommand %q", args[0])}
+}
+
+func (this *cli) printServices(services []servicedb.ServiceEntity) error {
+	rows := [][]string{}
+	for _, s := range services {
+		rows = append(rows, []string{s.Id, s.Servicename, tenant.Of(s), s.Latestversion, formatMillis(s.Lastupdated)})
+	}
+	return printValue(this.stdout, this.output, services, []string{"ID", "NAME", "NAMESPACE", "LATEST", "UPDATED"}, rows)
+}
+
+func (this *cli) register(args []string) error {
+	flags := flag.NewFlagSet("services register", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	rng := flags.String("range", "*", "semantic version range of the called versions")
+	file := flags.String("f", "", "file listing the operations, one per line")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() < 2 {
+		return &usageError{"services register: <serviceId> <providerId> are required"}
+	}
+	operations := flags.Args()[2:]
+	if *file != "" {
+		contents, err := ioutil.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(string(contents), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				operations = append(operations, line)
+			}
+		}
+	}
+	serviceId, providerId := flags.Arg(0), flags.Arg(1)
+	if _, err := this.api.RegisterDependency(context.Background(), serviceId, providerId, *rng, operations); err != nil {
+		return err
+	}
+	fmt.Fprintf(this.stderr, "%d operations of service %s were registered for %s\n", len(operations), providerId, serviceId)
+	return nil
+}
+
+func (this *cli) printEdges(edges []dependency.Edge) error {
+	rows := [][]string{}
+	for _, e := range edges {
+		resolved := e.Resolved
+		if !e.Satisfied {
+			resolved = "unsatisfied"
+		}
+		rows = append(rows, []string{e.Consumer, e.Provider, e.Range, resolved})
+	}
+	return printValue(this.stdout, this.output, edges, []string{"CONSUMER", "PROVIDER", "RANGE", "RESOLVED"}, rows)
+}
+
+func (this *cli) versions(args []string) error {
+	if len(args) == 0 {
+		return &usageError{"versions: subcommand is required"}
+	}
+	switch args[0] {
+	case "list":
+		if len(args) != 2 {
+			return &usageError{"versions list: <serviceId> is required"}
+		}
+		versions, err := this.api.ListVersions(context.Background(), args[1])
+		if err != nil {
+			return err
+		}
+		return this.printVersions(versions)
+	case "upload":
+		return this.upload(args[1:])
+	case "enable", "disable":
+		if len(args) != 3 {
+			return &usageError{fmt.Sprintf("versions %s: <serviceId> <version> are required", args[0])}
+		}
+		enable := args[0] == "enable"
+		return this.updateVersion(args[1], args[2], func(v *versiondb.VersionEntity) { v.Enable = enable })
+	case "tag":
+		if len(args) != 4 {
+			return &usageError{"versions tag: <serviceId> <version> <tag> are required"}
+		}
+		return this.updateVersion(args[1], args[2], func(v *versiondb.VersionEntity) { v.Tag = args[3] })
+	case "download":
+		return this.download(args[1:])
+	case "export":
+		return this.export(args[1:])
+	case "markdown":
+		return this.markdown(args[1:])
+	case "codegen":
+		return this.codegen(args[1:])
+	case "operations":
+		if len(args) != 3 {
+			return &usageError{"versions operations: <serviceId> <version> are required"}
+		}
+		return this.operations(args[1], args[2])
+	case "approve", "reject":
+		return this.review(args[0], args[1:])
+	}
+	return &usageError{fmt.Sprintf("versions: unknown subcommand %q", args[0])}
+}
+
+func (this *cli) printVersions(versions []versiondb.VersionEntity) error {
+	rows := [][]string{}
+	for _, v := range versions {
+		status := ""
+		if v.Review != nil {
+			status = v.Review.Status
+		}
+		rows = append(rows, []string{v.Version, v.Tag, fmt.Sprintf("%t", v.Enable), status, formatMillis(v.Lastupdated), v.Path})
+	}
+	return printValue(this.stdout, this.output, versions, []string{"VERSION", "TAG", "ENABLE", "REVIEW", "UPDATED", "PATH"}, rows)
+}
+
+// review approves or rejects a version pending review
+func (this *cli) review(decision string, args []string) error {
+	flags := flag.NewFlagSet("versions "+decision, flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	comment := flags.String("m", "", "comment (required to reject)")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() != 2 {
+		return &usageError{fmt.Sprintf("versions %s: <serviceId> <version> are required", decision)}
+	}
+	if decision == "reject" && *comment == "" {
+		return &usageError{"versions reject: -m comment is required"}
+	}
+	v, err := this.api.ReviewVersion(context.Background(), flags.Arg(0), flags.Arg(1), decision, *comment)
+	if err != nil {
+		return err
+	}
+	return this.printVersions([]versiondb.VersionEntity{*v})
+}
+
+func (this *cli) upload(args []string) error {
+	flags := flag.NewFlagSet("versions upload", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	tag := flags.String("tag", "latest", "tag of the version")
+	enable := flags.String("enable", "true", "enable the version")
+	format := flags.String("format", "", "yaml or json (default: from the file extension)")
+	entry := flags.String("entry", "", "entry file of a directory or an archive (default: openapi/swagger.yaml etc.)")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() != 2 {
+		return &usageError{"versions upload: <serviceId> <file|directory|archive> are required"}
+	}
+	serviceId, file := flags.Arg(0), flags.Arg(1)
+	enabled, err := parseBool(*enable)
+	if err != nil {
+		return err
+	}
+
+	input := client.UploadVersionInput{
+		Enable: enabled,
+		Tag:    *tag,
+		Entry:  *entry,
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	switch {
+	case info.IsDir():
+		if input.Files, err = readTree(file); err != nil {
+			return err
+		}
+	case isArchive(file):
+		if input.Archive, err = ioutil.ReadFile(file); err != nil {
+			return err
+		}
+	default:
+		contents, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		input.Contents = string(contents)
+		input.Format = *format
+		if input.Format == "" {
+			input.Format = "yaml"
+			if swagger.FileFormat(file) == common.Json || swagger.DetectFormat(input.Contents) == common.Json {
+				input.Format = "json"
+			}
+		}
+	}
+
+	result, err := this.api.UploadVersion(context.Background(), serviceId, input)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(this.stderr, "%s was uploaded to %s\n", file, serviceId)
+	if result.Review != nil {
+		fmt.Fprintf(this.stderr, "version %s is %s review\n", result.Version, result.Review.Status)
+	}
+	if result.ImpactError != "" {
+		fmt.Fprintf(this.stderr, "warning: %s\n", result.ImpactError)
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintf(this.stderr, "warning: %s\n", w.Message)
+		for _, impact := range w.Impacts {
+			for _, c := range impact.Changes {
+				fmt.Fprintf(this.stderr, "  %s: %s\n", impact.Operation, c.Message)
+			}
+		}
+	}
+	return nil
+}
+
+func isArchive(file string) bool {
+	name := strings.ToLower(file)
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// readTree reads the yaml/json files under dir. Keys are slash separated paths relative to dir.
+func readTree(dir string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yml", ".yaml", ".json":
+		default:
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(contents)
+		return nil
+	})
+	return files, err
+}
+
+// updateVersion fetches the version, applies update and sends the whole record back
+func (this *cli) updateVersion(serviceId string, version string, update func(*versiondb.VersionEntity)) error {
+	ctx := context.Background()
+	v, err := this.api.GetVersion(ctx, serviceId, version)
+	if err != nil {
+		return err
+	}
+	update(v)
+	input := client.UpdateVersionInput{
+		Path:   v.Path,
+		Enable: v.Enable,
+		Tag:    v.Tag,
+	}
+	if _, err := this.api.UpdateVersion(ctx, serviceId, version, input); err != nil {
+		return err
+	}
+	return this.printVersions([]versiondb.VersionEntity{*v})
+}
+
+func (this *cli) download(args []string) error {
+	flags := flag.NewFlagSet("versions download", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	out := flags.String("out", "", "output file (default: stdout)")
+	convert := flags.String("convert", "", "convert the document (oas3)")
+	format := flags.String("format", "", "yaml or json (default: the uploaded format)")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() != 2 {
+		return &usageError{"versions download: <serviceId> <version> are required"}
+	}
+	contents, err := this.api.DownloadVersionWith(context.Background(), flags.Arg(0), flags.Arg(1), client.DownloadOptions{Convert: *convert, Format: *format})
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = io.WriteString(this.stdout, contents)
+		return err
+	}
+	return ioutil.WriteFile(*out, []byte(contents), 0644)
+}
+
+func (this *cli) export(args []string) error {
+	flags := flag.NewFlagSet("versions export", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	out := flags.String("out", "", "output file (default: stdout)")
+	format := flags.String("format", "postman", "postman or http")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() != 2 {
+		return &usageError{"versions export: <serviceId> <version> are required"}
+	}
+	if *format != "postman" && *format != "http" {
+		return &usageError{"versions export: -format must be postman or http"}
+	}
+	contents, err := this.api.ExportVersion(context.Background(), flags.Arg(0), flags.Arg(1), *format)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = io.WriteString(this.stdout, contents)
+		return err
+	}
+	return ioutil.WriteFile(*out, []byte(contents), 0644)
+}
+
+func (this *cli) markdown(args []string) error {
+	flags := flag.NewFlagSet("versions markdown", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	out := flags.String("out", "", "output file (default: <serviceId>-<version>-docs.zip)")
+	split := flags.String("split", "service", "service (one document) or tag (one document per tag)")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() != 2 {
+		return &usageError{"versions markdown: <serviceId> <version> are required"}
+	}
+	if *split != "service" && *split != "tag" {
+		return &usageError{"versions markdown: -split must be service or tag"}
+	}
+	archive, err := this.api.ExportMarkdown(context.Background(), flags.Arg(0), flags.Arg(1), *split)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = flags.Arg(0) + "-" + flags.Arg(1) + "-docs.zip"
+	}
+	return ioutil.WriteFile(*out, archive, 0644)
+}
+
+func (this *cli) codegen(args []string) error {
+	flags := flag.NewFlagSet("versions codegen", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	out := flags.String("out", "", "output file (default: <serviceId>-<version>-go-<kind>.zip, stdout for typescript)")
+	lang := flags.String("lang", "go", "go (zip archive of a package) or typescript (.d.ts file)")
+	kind := flags.String("kind", "client", "client (types and a typed client) or server (types and a server interface), for go")
+	pkg := flags.String("package", "", "name of the generated go package (default: from the title)")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() != 2 {
+		return &usageError{"versions codegen: <serviceId> <version> are required"}
+	}
+	if *lang == "typescript" {
+		contents, err := this.api.GenerateTypeScript(context.Background(), flags.Arg(0), flags.Arg(1))
+		if err != nil {
+			return err
+		}
+		if *out == "" {
+			_, err = io.WriteString(this.stdout, contents)
+			return err
+		}
+		return ioutil.WriteFile(*out, []byte(contents), 0644)
+	}
+	if *lang != "go" {
+		return &usageError{"versions codegen: -lang must be go or typescript"}
+	}
+	if *kind != "client" && *kind != "server" {
+		return &usageError{"versions codegen: -kind must be client or server"}
+	}
+	archive, err := this.api.GenerateGo(context.Background(), flags.Arg(0), flags.Arg(1), *kind, *pkg)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = flags.Arg(0) + "-" + flags.Arg(1) + "-go-" + *kind + ".zip"
+	}
+	return ioutil.WriteFile(*out, archive, 0644)
+}
+
+func (this *cli) diff(args []string) (int, error) {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	failOnBreaking := flags.Bool("fail-on-breaking", false, "exit with 4 if breaking changes are found")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage, &usageError{err.Error()}
+	}
+	if flags.NArg() != 3 {
+		return exitUsage, &usageError{"diff: <serviceId> <fromVersion> <toVersion> are required"}
+	}
+	serviceId := flags.Arg(0)
+
+	from, err := this.document(serviceId, flags.Arg(1))
+	if err != nil {
+		return exitError, err
+	}
+	to, err := this.document(serviceId, flags.Arg(2))
+	if err != nil {
+		return exitError, err
+	}
+	result := swagger.Diff(from, to)
+
+	rows := [][]string{}
+	for _, c := range result.Changes {
+		breaking := ""
+		if c.Breaking {
+			breaking = "BREAKING"
+		}
+		rows = append(rows, []string{c.Kind, c.Target, breaking, c.Message})
+	}
+	if err := printValue(this.stdout, this.output, result, []string{"KIND", "TARGET", "BREAKING", "MESSAGE"}, rows); err != nil {
+		return exitError, err
+	}
+	if *failOnBreaking && result.HasBreaking() {
+		fmt.Fprintf(this.stderr, "%d breaking changes found\n", len(result.Breaking()))
+		return exitBreaking, nil
+	}
+	return exitOK, nil
+}
+
+func (this *cli) lint(args []string) (int, error) {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	failOnError := flags.Bool("fail-on-error", false, "exit with 4 if error rules are violated")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage, &usageError{err.Error()}
+	}
+	if flags.NArg() != 2 {
+		return exitUsage, &usageError{"lint: <serviceId> <version> are required"}
+	}
+	result, err := this.api.LintVersion(context.Background(), flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return exitError, err
+	}
+
+	rows := [][]string{}
+	for _, f := range result.Items {
+		rows = append(rows, []string{string(f.Severity), f.Rule, f.Path, f.Message})
+	}
+	if err := printValue(this.stdout, this.output, result, []string{"SEVERITY", "RULE", "PATH", "MESSAGE"}, rows); err != nil {
+		return exitError, err
+	}
+	if *failOnError && result.HasErrors() {
+		fmt.Fprintf(this.stderr, "%d errors found\n", result.Errors)
+		return exitBreaking, nil
+	}
+	return exitOK, nil
+}
+
+func (this *cli) changelog(args []string) error {
+	flags := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	from := flags.String("from", "", "the version to compare from (default: the changelog across all versions)")
+	to := flags.String("to", "", "the version to compare to")
+	format := flags.String("format", "markdown", "markdown, html or json")
+	out := flags.String("out", "", "output file (default: stdout)")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() != 1 {
+		return &usageError{"changelog: <serviceId> is required"}
+	}
+	if (*from == "") != (*to == "") {
+		return &usageError{"changelog: -from and -to must be specified together"}
+	}
+	contents, err := this.api.GetChangelog(context.Background(), flags.Arg(0), *from, *to, *format)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = io.WriteString(this.stdout, contents)
+		return err
+	}
+	return ioutil.WriteFile(*out, []byte(contents), 0644)
+}
+
+func (this *cli) gateway(args []string) (int, error) {
+	flags := flag.NewFlagSet("gateway", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	title := flags.String("title", "", "title of the merged spec (default: Gateway)")
+	format := flags.String("format", "yaml", "yaml or json")
+	out := flags.String("out", "", "output file (default: stdout)")
+	failOnConflict := flags.Bool("fail-on-conflict", false, "exit with 4 if conflicts are not resolved")
+	if err := flags.Parse(args); err != nil {
+		return 0, &usageError{err.Error()}
+	}
+	if flags.NArg() == 0 {
+		return 0, &usageError{"gateway: <serviceId> is required"}
+	}
+	if *format != "yaml" && *format != "json" {
+		return 0, &usageError{"gateway: -format must be yaml or json"}
+	}
+	result, err := this.api.GetGateway(context.Background(), flags.Args(), *title)
+	if err != nil {
+		return 0, err
+	}
+	outputFormat := common.Yml
+	if *format == "json" {
+		outputFormat = common.Json
+	}
+	contents, err := result.Spec.Marshal(outputFormat)
+	if err != nil {
+		return 0, err
+	}
+	if *out == "" {
+		_, err = io.WriteString(this.stdout, contents)
+	} else {
+		err = ioutil.WriteFile(*out, []byte(contents), 0644)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// conflicts go to stderr, so that the spec can be piped
+	for _, c := range result.Conflicts {
+		status := "unresolved"
+		if c.Resolved {
+			status = "resolved"
+		}
+		fmt.Fprintf(this.stderr, "%s %s %s: %s (%s)\n", c.Kind, c.ServiceId, c.Name, c.Message, status)
+	}
+	if unresolved := result.Unresolved(); *failOnConflict && len(unresolved) > 0 {
+		fmt.Fprintf(this.stderr, "%d conflicts are not resolved\n", len(unresolved))
+		return exitBreaking, nil
+	}
+	return 0, nil
+}
+
+func (this *cli) dependencies(args []string) error {
+	flags := flag.NewFlagSet("dependencies", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	format := flags.String("format", "", "dot renders the graph in the Graphviz DOT language (default: the -o output)")
+	out := flags.String("out", "", "output file of the dot format (default: stdout)")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() != 0 {
+		return &usageError{"dependencies: no argument is expected"}
+	}
+	ctx := context.Background()
+	switch *format {
+	case "":
+		graph, err := this.api.GetDependencyGraph(ctx)
+		if err != nil {
+			return err
+		}
+		if this.output != outputTable {
+			return printValue(this.stdout, this.output, graph, nil, nil)
+		}
+		return this.printEdges(graph.Edges)
+	case "dot":
+		dot, err := this.api.GetDependencyGraphDOT(ctx)
+		if err != nil {
+			return err
+		}
+		if *out == "" {
+			_, err = io.WriteString(this.stdout, dot)
+			return err
+		}
+		return ioutil.WriteFile(*out, []byte(dot), 0644)
+	}
+	return &usageError{"dependencies: -format must be dot"}
+}
+
+func (this *cli) operations(serviceId string, version string) error {
+	items, err := this.api.ListOperations(context.Background(), serviceId, version)
+	if err != nil {
+		return err
+	}
+	rows := [][]string{}
+	for _, item := range items {
+		deprecated := ""
+		if item.Deprecated {
+			deprecated = "DEPRECATED"
+		}
+		schemes := []string{}
+		for _, requirement := range item.Security {
+			names := []string{}
+			for name := range requirement {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			schemes = append(schemes, strings.Join(names, "+"))
+		}
+		rows = append(rows, []string{item.Method, item.Path, item.OperationId, strings.Join(item.Tags, ","), deprecated, strings.Join(schemes, ",")})
+	}
+	return printValue(this.stdout, this.output, items, []string{"METHOD", "PATH", "OPERATIONID", "TAGS", "DEPRECATED", "SECURITY"}, rows)
+}
+
+func (this *cli) search(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	flags.SetOutput(this.stderr)
+	service := flags.String("service", "", "service id or name")
+	tag := flags.String("tag", "", "tag of versions")
+	method := flags.String("method", "", "http method of operations")
+	limit := flags.Int("limit", 0, "max number of matches (default: 50)")
+	if err := flags.Parse(args); err != nil {
+		return &usageError{err.Error()}
+	}
+	if flags.NArg() == 0 {
+		return &usageError{"search: <query> is required"}
+	}
+	result, err := this.api.Search(context.Background(), strings.Join(flags.Args(), " "), client.SearchOptions{
+		Service: *service,
+		Tag:     *tag,
+		Method:  *method,
+		Limit:   *limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	for _, hit := range result.Items {
+		location := strings.TrimSpace(hit.Method + " " + hit.Path)
+		if location == "" {
+			location = hit.Name
+		} else if hit.Name != "" {
+			location += " (" + hit.Name + ")"
+		}
+		rows = append(rows, []string{hit.Servicename, hit.Version, hit.Kind, location, hit.Text})
+	}
+	return printValue(this.stdout, this.output, result, []string{"SERVICE", "VERSION", "KIND", "MATCH", "TEXT"}, rows)
+}
+
+func (this *cli) comments(args []string) error {
+	if len(args) == 0 {
+		return &usageError{"comments: subcommand is required"}
+	}
+	ctx := context.Background()
+	switch args[0] {
+	case "list":
+		flags := flag.NewFlagSet("comments list", flag.ContinueOnError)
+		flags.SetOutput(this.stderr)
+		version := flags.String("version", "", "threads of the version, including the threads carried from earlier versions")
+		pointer := flags.String("pointer", "", "threads on the JSON pointer")
+		resolved := flags.String("resolved", "", "true or false")
+		if err := flags.Parse(args[1:]); err != nil {
+			return &usageError{err.Error()}
+		}
+		if flags.NArg() != 1 {
+			return &usageError{"comments list: <serviceId> is required"}
+		}
+		threads, err := this.api.ListComments(ctx, flags.Arg(0), *version, client.CommentOptions{Pointer: *pointer, Resolved: *resolved})
+		if err != nil {
+			return err
+		}
+		return this.printThreads(threads)
+	case "add", "reply":
+		flags := flag.NewFlagSet("comments "+args[0], flag.ContinueOnError)
+		flags.SetOutput(this.stderr)
+		body := flags.String("m", "", "comment")
+		if err := flags.Parse(args[1:]); err != nil {
+			return &usageError{err.Error()}
+		}
+		if flags.NArg() != 3 {
+			target := "<operation|schema|pointer>"
+			if args[0] == "reply" {
+				target = "<commentId>"
+			}
+			return &usageError{fmt.Sprintf("comments %s: <serviceId> <version> %s are required", args[0], target)}
+		}
+		if *body == "" {
+			return &usageError{fmt.Sprintf("comments %s: -m comment is required", args[0])}
+		}
+		input := client.CreateCommentInput{Body: *body}
+		if args[0] == "reply" {
+			input.ParentId = flags.Arg(2)
+		} else {
+			pointer, err := this.pointer(flags.Arg(0), flags.Arg(1), flags.Arg(2))
+			if err != nil {
+				return err
+			}
+			input.Pointer = pointer
+		}
+		created, err := this.api.CreateComment(ctx, flags.Arg(0), flags.Arg(1), input)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(this.stderr, "comment %s was posted on %s\n", created.Id, created.Pointer)
+		return nil
+	case "resolve", "unresolve":
+		if len(args) != 3 {
+			return &usageError{fmt.Sprintf("comments %s: <serviceId> <commentId> are required", args[0])}
+		}
+		updated, err := this.api.ResolveComment(ctx, args[1], args[2], args[0] == "resolve")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(this.stderr, "thread %s was %sd\n", updated.Id, args[0])
+		return nil
+	}
+	return &usageError{fmt.Sprintf("comments: unknown subcommand %q", args[0])}
+}
+
+// pointer returns the JSON pointer of target: "METHOD /path", a schema name or a JSON pointer
+func (this *cli) pointer(serviceId string, version string, target string) (string, error) {
+	if strings.HasPrefix(target, "/") {
+		return target, nil
+	}
+	if fields := strings.Fields(target); len(fields) == 2 && strings.HasPrefix(fields[1], "/") {
+		return comment.OperationPointer(fields[1], fields[0]), nil
+	}
+	doc, err := this.document(serviceId, version)
+	if err != nil {
+		return "", err
+	}
+	return comment.SchemaPointer(doc, target), nil
+}
+
+func (this *cli) printThreads(threads []comment.Thread) error {
+	rows := [][]string{}
+	for _, t := range threads {
+		status := "open"
+		if t.Resolved {
+			status = "resolved"
+		}
+		version := t.Version
+		if t.Carried {
+			version += " (carried)"
+		}
+		rows = append(rows, []string{t.Id, version, t.Pointer, t.Author, status, formatMillis(t.Createdat), firstLine(t.Body)})
+		for _, r := range t.Replies {
+			rows = append(rows, []string{"  " + r.Id, r.Version, "", r.Author, "", formatMillis(r.Createdat), firstLine(r.Body)})
+		}
+	}
+	return printValue(this.stdout, this.output, threads, []string{"ID", "VERSION", "TARGET", "AUTHOR", "STATUS", "CREATED", "BODY"}, rows)
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " ..."
+	}
+	return s
+}
+
+func (this *cli) document(serviceId string, version string) (swagger.Document, error) {
+	contents, err := this.api.DownloadVersion(context.Background(), serviceId, version)
+	if err != nil {
+		return nil, err
+	}
+	return swagger.Parse(swagger.DetectFormat(contents), contents)
+}
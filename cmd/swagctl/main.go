@@ -0,0 +1,223 @@
+// swagctl is a command line client of the swagger viewer api.
+//
+//	swagctl [global flags] services list|create|rename|delete ...
+//	swagctl [global flags] versions list|upload|enable|disable|tag|download ...
+//	swagctl [global flags] diff [-fail-on-breaking] <serviceId> <fromVersion> <toVersion>
+//
+// The endpoint and api key are read from flags, SWAGCTL_ENDPOINT/SWAGCTL_API_KEY,
+// or the config file (~/.swagctl.yml) in this order.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/client"
+	"gopkg.in/yaml.v2"
+)
+
+// Exit codes
+const (
+	exitOK       = 0
+	exitError    = 1 // api or network error
+	exitUsage    = 2 // invalid arguments
+	exitNotFound = 3 // service or version not found
+	exitBreaking = 4 // diff found breaking changes (-fail-on-breaking), lint found errors (-fail-on-error) or gateway found conflicts (-fail-on-conflict)
+)
+
+const usage = `usage: swagctl [-endpoint url] [-api-key key] [-o table|json|yaml] [-config file] <command>
+
+commands:
+  services list [-namespace organization/team]
+  services create [-namespace organization/team] <name>
+  services rename <serviceId> <name>
+  services delete <serviceId>
+  services lint-rules <serviceId> <file>
+  services depend <serviceId> [<serviceId>[@range]]...   (no dependency removes them)
+  services register [-range range] [-f file] <serviceId> <providerId> [operation]...
+  services consumers <serviceId>
+  services review-policy [-approvals n] [-self-approval] <serviceId> [reviewer]...   (-approvals 0 removes the policy)
+  versions list <serviceId>
+  versions upload [-tag tag] [-enable=true] [-format yaml|json] [-entry file] <serviceId> <file|directory|archive>
+  versions enable <serviceId> <version>
+  versions disable <serviceId> <version>
+  versions tag <serviceId> <version> <tag>
+  versions download [-out file] [-convert oas3] [-format yaml|json] <serviceId> <version>
+  versions operations <serviceId> <version>
+  versions approve [-m comment] <serviceId> <version>
+  versions reject -m comment <serviceId> <version>
+  versions export [-format postman|http] [-out file] <serviceId> <version>
+  versions markdown [-split service|tag] [-out file] <serviceId> <version>
+  versions codegen [-lang go|typescript] [-kind client|server] [-package name] [-out file] <serviceId> <version>
+  diff [-fail-on-breaking] <serviceId> <fromVersion> <toVersion>
+  lint [-fail-on-error] <serviceId> <version>
+  changelog [-from version -to version] [-format markdown|html|json] [-out file] <serviceId>
+  search [-service id|name] [-tag tag] [-method method] [-limit n] <query>
+  gateway [-title title] [-format yaml|json] [-out file] [-fail-on-conflict] <serviceId[:prefix]>...
+  dependencies [-format dot] [-out file]
+  comments list [-version version] [-pointer pointer] [-resolved true|false] <serviceId>
+  comments add -m comment <serviceId> <version> <"METHOD /path"|schema|pointer>
+  comments reply -m comment <serviceId> <version> <commentId>
+  comments resolve|unresolve <serviceId> <commentId>
+`
+
+// config is the content of the config file
+type config struct {
+	Endpoint string `yaml:"endpoint"`
+	APIKey   string `yaml:"apikey"`
+	Output   string `yaml:"output"`
+}
+
+type cli struct {
+	api    *client.Client
+	output string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// usageError is returned for invalid arguments
+type usageError struct {
+	message string
+}
+
+func (err *usageError) Error() string {
+	return err.message
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("swagctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	endpoint := flags.String("endpoint", "", "api endpoint (e.g. https://xxx.execute-api.ap-northeast-1.amazonaws.com/dev)")
+	apiKey := flags.String("api-key", "", "api key")
+	output := flags.String("o", "", "output format: table, json or yaml")
+	configPath := flags.String("config", defaultConfigPath(), "config file")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	conf, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitUsage
+	}
+	resolvedEndpoint := firstNonEmpty(*endpoint, os.Getenv("SWAGCTL_ENDPOINT"), conf.Endpoint)
+	resolvedOutput := firstNonEmpty(*output, conf.Output, outputTable)
+	if resolvedEndpoint == "" {
+		fmt.Fprintln(stderr, "error: endpoint is not configured")
+		return exitUsage
+	}
+	if !validOutput(resolvedOutput) {
+		fmt.Fprintf(stderr, "error: unknown output format %q\n", resolvedOutput)
+		return exitUsage
+	}
+
+	c := &cli{
+		api:    client.New(resolvedEndpoint, firstNonEmpty(*apiKey, os.Getenv("SWAGCTL_API_KEY"), conf.APIKey)),
+		output: resolvedOutput,
+		stdout: stdout,
+		stderr: stderr,
+	}
+	return c.exec(flags.Args())
+}
+
+func (this *cli) exec(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(this.stderr, usage)
+		return exitUsage
+	}
+
+	var code int
+	var err error
+	switch args[0] {
+	case "services":
+		err = this.services(args[1:])
+	case "versions":
+		err = this.versions(args[1:])
+	case "diff":
+		code, err = this.diff(args[1:])
+	case "lint":
+		code, err = this.lint(args[1:])
+	case "changelog":
+		err = this.changelog(args[1:])
+	case "search":
+		err = this.search(args[1:])
+	case "gateway":
+		code, err = this.gateway(args[1:])
+	case "dependencies":
+		err = this.dependencies(args[1:])
+	case "comments":
+		err = this.comments(args[1:])
+	default:
+		err = &usageError{fmt.Sprintf("unknown command %q", args[0])}
+	}
+
+	if err != nil {
+		fmt.Fprintf(this.stderr, "error: %v\n", err)
+		switch e := err.(type) {
+		case *usageError:
+			fmt.Fprint(this.stderr, usage)
+			return exitUsage
+		case *client.Error:
+			if e.StatusCode == 404 {
+				return exitNotFound
+			}
+		}
+		return exitError
+	}
+	return code
+}
+
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".swagctl.yml")
+}
+
+// loadConfig loads the config file. A missing file is not an error.
+func loadConfig(path string) (config, error) {
+	var conf config
+	if path == "" {
+		return conf, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return conf, nil
+	}
+	if err != nil {
+		return conf, err
+	}
+	if err := yaml.Unmarshal(b, &conf); err != nil {
+		return conf, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return conf, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func parseBool(s string) (bool, error) {
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		return false, &usageError{fmt.Sprintf("invalid boolean %q", s)}
+	}
+	return b, nil
+}
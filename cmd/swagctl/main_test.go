@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var v1 = `swagger: '2.0'
+info:
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: ok
+    delete:
+      responses:
+        '204':
+          description: deleted
+`
+
+var v2 = `{"swagger": "2.0", "info": {"version": "2.0.0"}, "paths": {"/pets": {"get": {"responses": {"200": {"description": "ok"}}}}}}`
+
+func newTestServer(t *testing.T, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*requests = append(*requests, r.Method+" "+r.URL.Path+" "+string(body))
+		if r.Header.Get("x-api-key") != "secret" {
+			t.Errorf("api key is not sent")
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /services":
+			if r.URL.Query().Get("namespace") == "pets/store" {
+				w.Write([]byte(`{"Items":[{"id":"s3","servicename":"petstore","namespace":"pets/store","latestversion":"0.0.0","lastupdated":0}]}`))
+				return
+			}
+			w.Write([]byte(`{"Items":[{"id":"s1","servicename":"petstore","latestversion":"1.0.0","lastupdated":0}]}`))
+		case "POST /services":
+			w.WriteHeader(201)
+			w.Write([]byte(`{"id":"s3","servicename":"petstore","namespace":"pets/store","latestversion":"0.0.0","lastupdated":0}`))
+		case "GET /versions/s1":
+			w.Write([]byte(`{"Items":[{"id":"s1","version":"1.0.0","path":"swagger/s1/1.yml","enable":true,"tag":"latest"}]}`))
+		case "PATCH /versions/s1/versions/1.0.0":
+			w.Write(body)
+		case "GET /versions/s1/versions/1.0.0/download":
+			w.Write([]byte(v1))
+		case "GET /versions/s1/versions/2.0.0/download":
+			w.Write([]byte(v2))
+		case "PUT /versions/s1":
+			w.Write([]byte(`{"id":"s1","version":"1.0.0","enable":true,"tag":"prod","warnings":[{"consumer":"s2","consumerName":"web","range":"^0.9.0","reason":"out-of-range","previous":"0.9.1","message":"1.0.0 is out of the range ^0.9.0 of web"},{"consumer":"s3","consumerName":"shop","range":"^1.0.0","reason":"breaking","previous":"1.0.0","message":"1.0.0 has 1 breaking changes from 1.0.0 in the range ^1.0.0 of shop, affecting DELETE /pets","impacts":[{"operation":"DELETE /pets","changes":[{"kind":"removed","target":"response","method":"delete","path":"/pets","name":"204","breaking":true,"message":"response 204 was removed from DELETE /pets"}]}]}]}`))
+		case "PUT /services/s1/dependencies", "PUT /services/s1/dependencies/s2":
+			w.Write([]byte(`{"id":"s1","servicename":"petstore","latestversion":"1.0.0","lastupdated":0}`))
+		case "GET /services/s1/consumers", "GET /dependencies":
+			if r.URL.Query().Get("format") == "dot" {
+				w.Write([]byte("digraph dependencies {\n}\n"))
+				return
+			}
+			edges := `[{"consumer":"s2","consumerName":"web","provider":"s1","providerName":"petstore","range":"^1.0.0","resolved":"1.0.0","satisfied":true}]`
+			if r.URL.Path == "/dependencies" {
+				w.Write([]byte(`{"nodes":[{"id":"s1","name":"petstore"},{"id":"s2","name":"web"}],"edges":` + edges + `}`))
+			} else {
+				w.Write([]byte(`{"Items":` + edges + `}`))
+			}
+		case "GET /versions/s1/versions/1.0.0/lint":
+			w.Write([]byte(`{"Items":[{"rule":"operation-operationid","severity":"error","message":"operationId is missing","path":"/paths/~1pets/get"}],"errors":1,"warnings":0}`))
+		case "PUT /services/s1/review":
+			w.Write([]byte(`{"id":"s1","servicename":"petstore","review":` + string(body) + `}`))
+		case "POST /versions/s1/versions/1.1.0/review":
+			w.Write([]byte(`{"id":"s1","version":"1.1.0","path":"swagger/s1/2.yml","enable":true,"tag":"prod","review":{"status":"approved","uploader":"carol","enable":true,"tag":"prod","decisions":[{"principal":"alice","decision":"approve","timestamp":0}]}}`))
+		case "POST /versions/s1/versions/1.0.0/comments":
+			var input map[string]string
+			json.Unmarshal(body, &input)
+			w.WriteHeader(201)
+			w.Write([]byte(`{"serviceid":"s1","id":"c2","version":"1.0.0","pointer":"` + input["pointer"] + `","parentid":"` + input["parentId"] + `","author":"alice","body":"` + input["body"] + `"}`))
+		case "GET /versions/s1/comments", "GET /versions/s1/versions/2.0.0/comments":
+			w.Write([]byte(`{"Items":[{"serviceid":"s1","id":"c1","version":"1.0.0","pointer":"/paths/~1pets/get","author":"alice","body":"paginate?","resolved":false,"createdat":0,"carried":true,"replies":[{"serviceid":"s1","id":"c3","version":"1.0.0","pointer":"/paths/~1pets/get","parentid":"c1","author":"bob","body":"next release\nwith a cursor","createdat":0}]}]}`))
+		case "PATCH /versions/s1/comments/c1":
+			w.Write([]byte(`{"serviceid":"s1","id":"c1","version":"1.0.0","pointer":"/paths/~1pets/get","resolved":true,"resolvedby":"alice"}`))
+		case "GET /gateway":
+			if r.URL.Query().Get("services") != "s1,s2:/stores" {
+				t.Errorf("invalid query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"spec":{"openapi":"3.0.3","info":{"title":"Gateway","version":"petstore@1.0.0"},"paths":{}},"conflicts":[{"kind":"missing","serviceId":"s2","name":"","message":"no enabled version is tagged prod","resolved":false}]}`))
+		default:
+			w.WriteHeader(404)
+			w.Write([]byte(`{"error":{"code":10001,"message":"ID and version do not exists"}}`))
+		}
+	}))
+}
+
+func runTest(server *httptest.Server, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	global := []string{"-endpoint", server.URL, "-api-key", "secret", "-config", ""}
+	code := run(append(global, args...), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestServicesList(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	code, stdout, _ := runTest(server, "services", "list")
+	if code != exitOK || !strings.Contains(stdout, "petstore") || !strings.HasPrefix(stdout, "ID") {
+		t.Fatalf("invalid output %d %s", code, stdout)
+	}
+
+	code, stdout, _ = runTest(server, "-o", "json", "services", "list")
+	var services []map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &services); code != exitOK || err != nil || services[0]["id"] != "s1" {
+		t.Fatalf("invalid json output %d %s", code, stdout)
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	code, stdout, stderr := runTest(server, "services", "create", "-namespace", "pets/store", "petstore")
+	if code != exitOK || !strings.Contains(stdout, "pets/store") {
+		t.Fatalf("invalid output %d %s %s", code, stdout, stderr)
+	}
+	if last := requests[len(requests)-1]; !strings.Contains(last, `"namespace":"pets/store"`) {
+		t.Fatalf("invalid request %s", last)
+	}
+
+	code, stdout, _ = runTest(server, "services", "list", "-namespace", "pets/store")
+	if code != exitOK || !strings.Contains(stdout, "s3") || strings.Contains(stdout, "s1") {
+		t.Fatalf("invalid output %d %s", code, stdout)
+	}
+	// the services created before namespaces are in the default namespace
+	code, stdout, _ = runTest(server, "services", "list")
+	if code != exitOK || !strings.Contains(stdout, "default/default") {
+		t.Fatalf("invalid output %d %s", code, stdout)
+	}
+}
+
+func TestReview(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	code, _, stderr := runTest(server, "services", "review-policy", "-approvals", "2", "s1", "alice", "bob")
+	if code != exitOK {
+		t.Fatalf("failed test %d %s", code, stderr)
+	}
+	if last := requests[len(requests)-1]; !strings.Contains(last, `"approvals":2`) || !strings.Contains(last, `"reviewers":["alice","bob"]`) {
+		t.Fatalf("invalid request %s", last)
+	}
+
+	code, stdout, stderr := runTest(server, "versions", "approve", "-m", "lgtm", "s1", "1.1.0")
+	if code != exitOK || !strings.Contains(stdout, "approved") {
+		t.Fatalf("invalid output %d %s %s", code, stdout, stderr)
+	}
+	if last := requests[len(requests)-1]; !strings.Contains(last, `"decision":"approve"`) || !strings.Contains(last, `"comment":"lgtm"`) {
+		t.Fatalf("invalid request %s", last)
+	}
+	if code, _, _ := runTest(server, "versions", "reject", "s1", "1.1.0"); code != exitUsage {
+		t.Fatalf("a rejection without comment must be a usage error %d", code)
+	}
+}
+
+func TestVersionsDisable(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	code, _, stderr := runTest(server, "versions", "disable", "s1", "1.0.0")
+	if code != exitOK {
+		t.Fatalf("failed test %d %s", code, stderr)
+	}
+	last := requests[len(requests)-1]
+	if !strings.HasPrefix(last, "PATCH /versions/s1/versions/1.0.0 ") || !strings.Contains(last, `"enable":false`) || !strings.Contains(last, `"tag":"latest"`) {
+		t.Fatalf("invalid request %s", last)
+	}
+
+	code, _, _ = runTest(server, "versions", "enable", "s1", "9.9.9")
+	if code != exitNotFound {
+		t.Fatalf("unknown version must exit with %d, got %d", exitNotFound, code)
+	}
+}
+
+func TestVersionsUpload(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "swagger.yaml")
+	ioutil.WriteFile(file, []byte(v1), 0644)
+	code, _, stderr := runTest(server, "versions", "upload", "-tag", "prod", file)
+	if code != exitUsage {
+		t.Fatalf("missing serviceId must be a usage error %d %s", code, stderr)
+	}
+	code, _, stderr = runTest(server, "versions", "upload", "-tag", "prod", "s1", file)
+	if code != exitOK {
+		t.Fatalf("failed test %d %s", code, stderr)
+	}
+	last := requests[len(requests)-1]
+	if !strings.Contains(last, `"format":"yaml"`) || !strings.Contains(last, `"tag":"prod"`) {
+		t.Fatalf("invalid request %s", last)
+	}
+	if !strings.Contains(stderr, "warning: 1.0.0 is out of the range ^0.9.0 of web\n") || !strings.Contains(stderr, "  DELETE /pets: response 204 was removed from DELETE /pets\n") {
+		t.Fatalf("warnings are missing %s", stderr)
+	}
+}
+
+func TestDiffFailOnBreaking(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	code, stdout, _ := runTest(server, "diff", "s1", "1.0.0", "2.0.0")
+	if code != exitOK || !strings.Contains(stdout, "DELETE /pets was removed") {
+		t.Fatalf("invalid output %d %s", code, stdout)
+	}
+	code, _, _ = runTest(server, "diff", "-fail-on-breaking", "s1", "1.0.0", "2.0.0")
+	if code != exitBreaking {
+		t.Fatalf("breaking changes must exit with %d, got %d", exitBreaking, code)
+	}
+	code, _, _ = runTest(server, "diff", "-fail-on-breaking", "s1", "1.0.0", "1.0.0")
+	if code != exitOK {
+		t.Fatalf("same versions must exit with %d, got %d", exitOK, code)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-config", "", "services", "list"}, &stdout, &stderr); code != exitUsage {
+		t.Fatalf("missing endpoint must be a usage error %d", code)
+	}
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+	if code, _, _ := runTest(server, "unknown"); code != exitUsage {
+		t.Fatalf("unknown command must be a usage error %d", code)
+	}
+}
+
+func TestLint(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	code, stdout, _ := runTest(server, "lint", "s1", "1.0.0")
+	if code != exitOK || !strings.Contains(stdout, "operation-operationid") {
+		t.Fatalf("invalid output %d %s", code, stdout)
+	}
+	code, _, stderr := runTest(server, "lint", "-fail-on-error", "s1", "1.0.0")
+	if code != exitBreaking || !strings.Contains(stderr, "1 errors found") {
+		t.Fatalf("errors must exit with %d, got %d %s", exitBreaking, code, stderr)
+	}
+}
+
+func TestGateway(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	code, stdout, stderr := runTest(server, "gateway", "-format", "json", "s1", "s2:/stores")
+	if code != exitOK || !strings.Contains(stdout, `"openapi": "3.0.3"`) || !strings.Contains(stderr, "missing s2 : no enabled version is tagged prod (unresolved)") {
+		t.Fatalf("invalid output %d %s %s", code, stdout, stderr)
+	}
+	code, _, _ = runTest(server, "gateway", "-fail-on-conflict", "s1", "s2:/stores")
+	if code != exitBreaking {
+		t.Fatalf("conflicts must exit with %d, got %d", exitBreaking, code)
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	code, _, stderr := runTest(server, "services", "depend", "s1", "s2@^1.2.0", "s3")
+	if code != exitOK {
+		t.Fatalf("failed test %d %s", code, stderr)
+	}
+	last := requests[len(requests)-1]
+	if !strings.Contains(last, `{"dependencies":[{"serviceId":"s2","range":"^1.2.0"},{"serviceId":"s3","range":"*"}]}`) {
+		t.Fatalf("invalid request %s", last)
+	}
+
+	code, _, stderr = runTest(server, "services", "register", "-range", "^2.0.0", "s1", "s2", "GET /pets", "listPets")
+	last = requests[len(requests)-1]
+	if code != exitOK || !strings.HasPrefix(last, "PUT /services/s1/dependencies/s2 ") || !strings.Contains(last, `{"operations":["GET /pets","listPets"],"range":"^2.0.0"}`) {
+		t.Fatalf("invalid request %d %s %s", code, last, stderr)
+	}
+
+	code, stdout, _ := runTest(server, "services", "consumers", "s1")
+	if code != exitOK || !strings.HasPrefix(stdout, "CONSUMER") || !strings.Contains(stdout, "^1.0.0") {
+		t.Fatalf("invalid output %d %s", code, stdout)
+	}
+	code, stdout, _ = runTest(server, "-o", "json", "dependencies")
+	if code != exitOK || !strings.Contains(stdout, `"nodes"`) {
+		t.Fatalf("invalid output %d %s", code, stdout)
+	}
+	code, stdout, _ = runTest(server, "dependencies", "-format", "dot")
+	if code != exitOK || !strings.HasPrefix(stdout, "digraph") {
+		t.Fatalf("invalid output %d %s", code, stdout)
+	}
+}
+
+func TestComments(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	code, _, stderr := runTest(server, "comments", "add", "-m", "why 204?", "s1", "1.0.0", "DELETE /pets")
+	if code != exitOK || !strings.Contains(stderr, "comment c2 was posted on /paths/~1pets/delete") {
+		t.Fatalf("failed test %d %s", code, stderr)
+	}
+	code, _, stderr = runTest(server, "comments", "add", "-m", "rename it", "s1", "1.0.0", "Pet")
+	if last := requests[len(requests)-1]; code != exitOK || !strings.Contains(last, `"pointer":"/definitions/Pet"`) {
+		t.Fatalf("invalid request %d %s %s", code, last, stderr)
+	}
+	code, _, stderr = runTest(server, "comments", "reply", "-m", "agreed", "s1", "1.0.0", "c1")
+	if last := requests[len(requests)-1]; code != exitOK || !strings.Contains(last, `"parentId":"c1"`) || strings.Contains(last, `"pointer"`) {
+		t.Fatalf("invalid request %d %s %s", code, last, stderr)
+	}
+	if code, _, _ := runTest(server, "comments", "add", "s1", "1.0.0", "Pet"); code != exitUsage {
+		t.Fatalf("a comment without body must be a usage error %d", code)
+	}
+
+	code, stdout, _ := runTest(server, "comments", "list", "-version", "2.0.0", "-resolved", "false", "s1")
+	if code != exitOK || !strings.Contains(stdout, "1.0.0 (carried)") || !strings.Contains(stdout, "  c3") || !strings.Contains(stdout, "next release ...") {
+		t.Fatalf("invalid output %d %s", code, stdout)
+	}
+	if last := requests[len(requests)-1]; !strings.HasPrefix(last, "GET /versions/s1/versions/2.0.0/comments ") {
+		t.Fatalf("invalid request %s", last)
+	}
+
+	code, _, stderr = runTest(server, "comments", "resolve", "s1", "c1")
+	if last := requests[len(requests)-1]; code != exitOK || !strings.Contains(last, `{"resolved":true}`) || !strings.Contains(stderr, "thread c1 was resolved") {
+		t.Fatalf("invalid request %d %s %s", code, last, stderr)
+	}
+}
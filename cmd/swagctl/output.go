@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+func validOutput(output string) bool {
+	return output == outputTable || output == outputJSON || output == outputYAML
+}
+
+// printValue prints value as json/yaml, or header and rows as a table
+func printValue(w io.Writer, output string, value interface{}, header []string, rows [][]string) error {
+	switch output {
+	case outputJSON:
+		b, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case outputYAML:
+		// round trip through json so that yaml keys follow json tags
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		var generic interface{}
+		if err := yaml.Unmarshal(b, &generic); err != nil {
+			return err
+		}
+		y, err := yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(y)
+		return err
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+func formatMillis(millis int64) string {
+	if millis == 0 {
+		return "-"
+	}
+	return time.Unix(millis/1000, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,134 @@
+// swagserver serves a stored version (or a local swagger file) over http.
+//
+//	swagserver -mode mock [-listen :8080] <spec>
+//	swagserver -mode proxy -target http://upstream [-reject] [-report /__violations] <spec>
+//
+// <spec> is a local file, or serviceId/version of the api (-endpoint and -api-key,
+// or SWAGCTL_ENDPOINT/SWAGCTL_API_KEY).
+//
+// The mock mode responds according to the spec (see lib/mock). The proxy mode forwards
+// requests to the target and validates requests and responses (see lib/proxy).
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/client"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/mock"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/proxy"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// Modes
+const (
+	modeMock  = "mock"
+	modeProxy = "proxy"
+)
+
+const usage = `usage: swagserver [-listen addr] [-endpoint url] [-api-key key] -mode mock <spec>
+       swagserver [-listen addr] [-endpoint url] [-api-key key] -mode proxy -target url [-reject] [-report path] <spec>
+
+<spec> is a local swagger file or <serviceId>/<version>
+`
+
+func main() {
+	logger := log.New(os.Stderr, "swagserver: ", log.LstdFlags)
+	addr, handler, err := newHandler(os.Args[1:], os.Stderr, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	logger.Printf("listening on %s", addr)
+	logger.Fatal(http.ListenAndServe(addr, handler))
+}
+
+// newHandler parses the arguments and returns the address and the handler to serve
+func newHandler(args []string, stderr io.Writer, logger *log.Logger) (string, http.Handler, error) {
+	flags := flag.NewFlagSet("swagserver", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	listen := flags.String("listen", ":8080", "address to listen on")
+	mode := flags.String("mode", modeMock, "mock or proxy")
+	endpoint := flags.String("endpoint", "", "api endpoint to download <serviceId>/<version>")
+	apiKey := flags.String("api-key", "", "api key")
+	target := flags.String("target", "", "upstream url (proxy mode)")
+	reject := flags.Bool("reject", false, "reject invalid requests (400) and responses (502) instead of logging them (proxy mode)")
+	report := flags.String("report", proxy.DefaultReportPath, "path of the violation report, or empty to disable it (proxy mode)")
+	if err := flags.Parse(args); err != nil {
+		return "", nil, err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return "", nil, fmt.Errorf("<spec> is required")
+	}
+
+	doc, err := loadSpec(flags.Arg(0), firstNonEmpty(*endpoint, os.Getenv("SWAGCTL_ENDPOINT")), firstNonEmpty(*apiKey, os.Getenv("SWAGCTL_API_KEY")))
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch *mode {
+	case modeMock:
+		return *listen, mockHandler(mock.New(doc)), nil
+	case modeProxy:
+		u, err := url.Parse(*target)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "", nil, fmt.Errorf("-target must be an absolute url")
+		}
+		p := proxy.New(doc, u)
+		p.Reject = *reject
+		p.ReportPath = *report
+		p.Logger = logger
+		return *listen, p, nil
+	}
+	return "", nil, fmt.Errorf("unknown mode %q", *mode)
+}
+
+// loadSpec reads a local file, or downloads serviceId/version if the file does not exist
+func loadSpec(spec string, endpoint string, apiKey string) (swagger.Document, error) {
+	contents, err := ioutil.ReadFile(spec)
+	if os.IsNotExist(err) && strings.Count(spec, "/") == 1 && endpoint != "" {
+		parts := strings.SplitN(spec, "/", 2)
+		var s string
+		s, err = client.New(endpoint, apiKey).DownloadVersion(context.Background(), parts[0], parts[1])
+		contents = []byte(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return swagger.Parse(swagger.DetectFormat(string(contents)), string(contents))
+}
+
+func mockHandler(m *mock.Mock) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(400)
+			return
+		}
+		response := m.Serve(mock.NewRequest(r, body))
+		for name, value := range response.Headers {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(response.StatusCode)
+		io.WriteString(w, response.Body)
+	})
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
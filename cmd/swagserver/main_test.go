@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var spec = `
+swagger: '2.0'
+info: {title: petstore, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        '200': {description: ok, schema: {type: array, items: {type: string, example: dog}}}
+`
+
+func writeSpec(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "swagserver")
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	path := filepath.Join(dir, "swagger.yml")
+	if err := ioutil.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	return path
+}
+
+func TestMockMode(t *testing.T) {
+	path := writeSpec(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	var stderr bytes.Buffer
+	addr, handler, err := newHandler([]string{"-listen", ":9090", path}, &stderr, log.New(&stderr, "", 0))
+	if err != nil || addr != ":9090" {
+		t.Fatalf("failed test %v %s", err, stderr.String())
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/pets", nil))
+	if w.Code != 200 || w.Body.String() != `["dog"]` {
+		t.Fatalf("invalid response %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	path := writeSpec(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	for _, args := range [][]string{
+		{},
+		{"-mode", "proxy", path},
+		{"-mode", "unknown", path},
+		{filepath.Join(filepath.Dir(path), "missing.yml")},
+	} {
+		var stderr bytes.Buffer
+		if _, _, err := newHandler(args, &stderr, log.New(&stderr, "", 0)); err == nil {
+			t.Errorf("%v must be invalid", args)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/semver"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// Groups of changes which are not grouped by a tag
+const (
+	GroupSchemas  = "Schemas"
+	GroupUntagged = "Other"
+)
+
+// Release is a version of a changelog and its changes from the previous version
+type Release struct {
+	Version     string           `json:"version"`
+	Previous    string           `json:"previous,omitempty"` // "" for the first version
+	Lastupdated int64            `json:"lastupdated,omitempty"`
+	Changes     []swagger.Change `json:"changes"`
+}
+
+// Breaking returns the number of breaking changes
+func (release Release) Breaking() int {
+	n := 0
+	for _, c := range release.Changes {
+		if c.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// Group is the changes of a tag
+type Group struct {
+	Name    string
+	Changes []swagger.Change
+}
+
+// Groups groups the changes by their first tag. Schema changes are in GroupSchemas and
+// endpoints without tags in GroupUntagged. Breaking changes come first in each group.
+func (release Release) Groups() []Group {
+	byName := map[string][]swagger.Change{}
+	for _, c := range release.Changes {
+		name := GroupUntagged
+		switch {
+		case c.Target == swagger.TargetSchema || c.Target == swagger.TargetProperty:
+			name = GroupSchemas
+		case len(c.Tags) > 0:
+			name = c.Tags[0]
+		}
+		byName[name] = append(byName[name], c)
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		if name != GroupSchemas && name != GroupUntagged {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range []string{GroupUntagged, GroupSchemas} {
+		if _, ok := byName[name]; ok {
+			names = append(names, name)
+		}
+	}
+
+	groups := make([]Group, 0, len(names))
+	for _, name := range names {
+		changes := byName[name]
+		sort.SliceStable(changes, func(i, j int) bool {
+			return changes[i].Breaking && !changes[j].Breaking
+		})
+		groups = append(groups, Group{Name: name, Changes: changes})
+	}
+	return groups
+}
+
+// Changelog is the changelog of a service. Releases are sorted from the newest.
+type Changelog struct {
+	Title    string    `json:"title"`
+	Releases []Release `json:"releases"`
+}
+
+// NewRelease makes a release from the diff of two documents. from is nil for the first version.
+func NewRelease(from swagger.Document, to swagger.Document, lastupdated int64) Release {
+	release := Release{Version: to.Version(), Lastupdated: lastupdated, Changes: []swagger.Change{}}
+	if from != nil {
+		release.Previous = from.Version()
+		release.Changes = swagger.Diff(from, to).Changes
+	}
+	return release
+}
+
+// Snapshot is a stored version of a service
+type Snapshot struct {
+	Document    swagger.Document
+	Lastupdated int64
+}
+
+// New makes the changelog across all snapshots in semver order of info.version
+func New(title string, snapshots []Snapshot) Changelog {
+	sorted := append([]Snapshot{}, snapshots...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return semver.Compare(sorted[i].Document.Version(), sorted[j].Document.Version()) < 0
+	})
+	changelog := Changelog{Title: title, Releases: []Release{}}
+	var previous swagger.Document
+	for _, snapshot := range sorted {
+		changelog.Releases = append([]Release{NewRelease(previous, snapshot.Document, snapshot.Lastupdated)}, changelog.Releases...)
+		previous = snapshot.Document
+	}
+	return changelog
+}
+
+// Markdown renders the changelog in Markdown
+func (changelog Changelog) Markdown() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", changelog.Title)
+	for _, release := range changelog.Releases {
+		fmt.Fprintf(&buf, "\n## %s\n\n", heading(release))
+		if release.Previous == "" {
+			buf.WriteString("Initial version.\n")
+			continue
+		}
+		if len(release.Changes) == 0 {
+			fmt.Fprintf(&buf, "No changes from %s.\n", release.Previous)
+			continue
+		}
+		fmt.Fprintf(&buf, "Changes from %s", release.Previous)
+		if n := release.Breaking(); n > 0 {
+			fmt.Fprintf(&buf, " (**%d breaking**)", n)
+		}
+		buf.WriteString(".\n")
+		for _, group := range release.Groups() {
+			fmt.Fprintf(&buf, "\n### %s\n\n", group.Name)
+			for _, c := range group.Changes {
+				buf.WriteString("- ")
+				if c.Breaking {
+					buf.WriteString("**BREAKING** ")
+				}
+				fmt.Fprintf(&buf, "%s: %s\n", c.Kind, escapeMarkdown(c.Message))
+			}
+		}
+	}
+	return buf.String()
+}
+
+func heading(release Release) string {
+	if release.Lastupdated == 0 {
+		return release.Version
+	}
+	return release.Version + " (" + time.Unix(release.Lastupdated/1000, 0).UTC().Format("2006-01-02") + ")"
+}
+
+var markdownEscaper = strings.NewReplacer("*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;", ">", "&gt;")
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+var htmlTemplate = template.Must(template.New("changelog").Funcs(template.FuncMap{
+	"heading": heading,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 960px; margin: 0 auto; padding: 16px; }
+.breaking { color: #fff; background: #d73a49; border-radius: 3px; padding: 0 4px; font-size: 80%; }
+.kind { color: #666; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- range .Releases}}
+<h2>{{heading .}}</h2>
+{{- if not .Previous}}
+<p>Initial version.</p>
+{{- else if not .Changes}}
+<p>No changes from {{.Previous}}.</p>
+{{- else}}
+<p>Changes from {{.Previous}}{{with .Breaking}} (<strong>{{.}} breaking</strong>){{end}}.</p>
+{{- range .Groups}}
+<h3>{{.Name}}</h3>
+<ul>
+{{- range .Changes}}
+<li>{{if .Breaking}}<span class="breaking">BREAKING</span> {{end}}<span class="kind">{{.Kind}}:</span> {{.Message}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML renders the changelog as an HTML page
+func (changelog Changelog) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, changelog); err != nil {
+		return "", common.NewError(22001, "changelog template error", err)
+	}
+	return buf.String(), nil
+}
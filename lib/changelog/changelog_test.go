@@ -0,0 +1,82 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+func parse(t *testing.T, contents string) swagger.Document {
+	doc, err := swagger.Parse(common.Yml, contents)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	return doc
+}
+
+var v1 = `
+swagger: '2.0'
+info: {title: petstore, version: 1.0.0}
+paths:
+  /pets:
+    get: {tags: [pets], responses: {'200': {description: ok}}}
+    delete: {tags: [pets], responses: {'204': {description: deleted}}}
+`
+
+var v1_10 = `
+swagger: '2.0'
+info: {title: petstore, version: 1.10.0}
+paths:
+  /pets:
+    get: {tags: [pets], responses: {'200': {description: ok}}}
+  /stores:
+    get: {tags: [stores], responses: {'200': {description: ok}}}
+definitions:
+  Store: {type: object}
+`
+
+var v1_2 = `
+swagger: '2.0'
+info: {title: petstore, version: 1.2.0}
+paths:
+  /pets:
+    get: {tags: [pets], responses: {'200': {description: ok}}}
+`
+
+func TestChangelog(t *testing.T) {
+	changelog := New("petstore", []Snapshot{
+		{Document: parse(t, v1_10), Lastupdated: 1600000000000},
+		{Document: parse(t, v1)},
+		{Document: parse(t, v1_2)},
+	})
+	if len(changelog.Releases) != 3 || changelog.Releases[0].Version != "1.10.0" || changelog.Releases[0].Previous != "1.2.0" || changelog.Releases[2].Previous != "" {
+		t.Fatalf("releases must be in semver order %+v", changelog.Releases)
+	}
+
+	groups := changelog.Releases[0].Groups()
+	if len(groups) != 2 || groups[0].Name != "stores" || groups[1].Name != GroupSchemas {
+		t.Fatalf("invalid groups %+v", groups)
+	}
+
+	markdown := changelog.Markdown()
+	for _, expected := range []string{
+		"# petstore\n",
+		"## 1.10.0 (2020-09-13)\n\nChanges from 1.2.0.\n\n### stores\n\n- added: GET /stores was added\n",
+		"## 1.2.0\n\nChanges from 1.0.0 (**1 breaking**).\n\n### pets\n\n- **BREAKING** removed: DELETE /pets was removed\n",
+		"## 1.0.0\n\nInitial version.\n",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Fatalf("%q is not found in\n%s", expected, markdown)
+		}
+	}
+
+	html, err := Changelog{Title: "<petstore>", Releases: changelog.Releases}.HTML()
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if !strings.Contains(html, "<h1>&lt;petstore&gt;</h1>") || !strings.Contains(html, `<span class="breaking">BREAKING</span>`) {
+		t.Fatalf("invalid html\n%s", html)
+	}
+}
@@ -0,0 +1,637 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/comment"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	commentdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/comment"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/dependency"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/gateway"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/lint"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/search"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// ServicePage is a page of GET /services
+type ServicePage struct {
+	Items []servicedb.ServiceEntity `json:"Items"`
+	Next  string                    `json:"Next"`
+}
+
+// VersionPage is a page of GET /versions/{id}
+type VersionPage struct {
+	Items []versiondb.VersionEntity `json:"Items"`
+	Next  string                    `json:"Next"`
+}
+
+// UploadVersionInput is the request body of PUT /versions/{id}.
+// Multi-file documents are uploaded with Files or Archive (and Entry) instead of Contents.
+type UploadVersionInput struct {
+	Enable   bool              `json:"enable"`
+	Tag      string            `json:"tag"`
+	Format   string            `json:"format,omitempty"` // yaml or json
+	Contents string            `json:"contents,omitempty"`
+	Files    map[string]string `json:"files,omitempty"`
+	Archive  []byte            `json:"archive,omitempty"` // zip, tar or tar.gz. encoded in base64
+	Entry    string            `json:"entry,omitempty"`
+}
+
+// UploadVersionResult is the response of PUT /versions/{id}. Warnings list the consumers
+// whose version range is broken by the uploaded version, unless ImpactError tells they could not be checked.
+type UploadVersionResult struct {
+	versiondb.VersionEntity
+	Warnings    []dependency.Warning `json:"warnings"`
+	ImpactError string               `json:"impactError,omitempty"`
+}
+
+// UpdateVersionInput is the request body of PATCH /versions/{id}/versions/{version}
+type UpdateVersionInput struct {
+	Path   string `json:"path,omitempty"`
+	Enable bool   `json:"enable"`
+	Tag    string `json:"tag"`
+}
+
+// DownloadOptions are the options of GET /versions/{id}/versions/{version}/download
+type DownloadOptions struct {
+	Convert string // "oas3" converts swagger 2.0 documents to OpenAPI 3.0
+	Format  string // yaml or json. the uploaded format if empty
+}
+
+// CreateWebhookInput is the request body of POST /services/{id}/webhooks
+type CreateWebhookInput struct {
+	Url    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"` // generated if empty
+	Events []string `json:"events,omitempty"` // all events if empty
+}
+
+// CreateCommentInput is the request body of POST /versions/{id}/versions/{version}/comments.
+// A reply gives ParentId instead of Pointer.
+type CreateCommentInput struct {
+	Pointer  string `json:"pointer,omitempty"` // JSON pointer of an operation or a schema
+	Body     string `json:"body"`
+	ParentId string `json:"parentId,omitempty"`
+}
+
+// CommentOptions are the filters of GET /versions/{id}/comments
+type CommentOptions struct {
+	Pointer  string
+	Resolved string // "true" or "false". all threads if empty
+}
+
+// SearchOptions are the filters of GET /search
+type SearchOptions struct {
+	Service string // service id or name
+	Tag     string
+	Method  string // only operations of the method match
+	Limit   int
+}
+
+// SearchHit is a match of GET /search
+type SearchHit struct {
+	Serviceid   string `json:"serviceid"`
+	Servicename string `json:"servicename"`
+	Version     string `json:"version"`
+	Tag         string `json:"tag"`
+	search.Hit
+}
+
+// SearchResult is the response of GET /search. Total counts the hits beyond the limit too.
+type SearchResult struct {
+	Items []SearchHit `json:"Items"`
+	Total int         `json:"Total"`
+}
+
+type operationList struct {
+	Items []swagger.InventoryItem `json:"Items"`
+}
+
+type auditList struct {
+	Items []auditdb.AuditEntity `json:"Items"`
+}
+
+type webhookList struct {
+	Items []webhookdb.WebhookEntity `json:"Items"`
+}
+
+type deliveryList struct {
+	Items []webhookdb.DeliveryEntity `json:"Items"`
+}
+
+type commentList struct {
+	Items []comment.Thread `json:"Items"`
+}
+
+type consumerList struct {
+	Items []dependency.Edge `json:"Items"`
+}
+
+// ListServices gets all services
+func (this *Client) ListServices(ctx context.Context) ([]servicedb.ServiceEntity, error) {
+	var page ServicePage
+	if err := this.do(ctx, "GET", "/services", nil, &page); err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// ListNamespaceServices gets the services of a namespace (organization/team)
+func (this *Client) ListNamespaceServices(ctx context.Context, namespace string) ([]servicedb.ServiceEntity, error) {
+	var page ServicePage
+	if err := this.do(ctx, "GET", "/services?namespace="+url.QueryEscape(namespace), nil, &page); err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// ListServicesPage gets a page of services. next is "" for the first page.
+func (this *Client) ListServicesPage(ctx context.Context, limit int, next string) (*ServicePage, error) {
+	var page ServicePage
+	if err := this.do(ctx, "GET", "/services"+pageQuery(limit, next), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// WalkServices calls fn for every page of services until the last page or an error
+func (this *Client) WalkServices(ctx context.Context, limit int, fn func(*ServicePage) error) error {
+	next := ""
+	for {
+		page, err := this.ListServicesPage(ctx, limit, next)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if page.Next == "" {
+			return nil
+		}
+		next = page.Next
+	}
+}
+
+// GetService gets a service
+func (this *Client) GetService(ctx context.Context, serviceId string) (*servicedb.ServiceEntity, error) {
+	var service servicedb.ServiceEntity
+	if err := this.do(ctx, "GET", "/services/"+url.PathEscape(serviceId), nil, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// CreateService creates a service in the only namespace granted, or the default one
+func (this *Client) CreateService(ctx context.Context, serviceName string) (*servicedb.ServiceEntity, error) {
+	return this.CreateNamespaceService(ctx, "", serviceName)
+}
+
+// CreateNamespaceService creates a service in a namespace (organization/team)
+func (this *Client) CreateNamespaceService(ctx context.Context, namespace string, serviceName string) (*servicedb.ServiceEntity, error) {
+	body := map[string]string{"servicename": serviceName}
+	if namespace != "" {
+		body["namespace"] = namespace
+	}
+	var service servicedb.ServiceEntity
+	if err := this.do(ctx, "POST", "/services", body, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// RenameService updates the name of a service
+func (this *Client) RenameService(ctx context.Context, serviceId string, serviceName string) error {
+	return this.do(ctx, "PATCH", "/services/"+url.PathEscape(serviceId), map[string]string{"servicename": serviceName}, nil)
+}
+
+// DeleteService deletes a service
+func (this *Client) DeleteService(ctx context.Context, serviceId string) error {
+	return this.do(ctx, "DELETE", "/services/"+url.PathEscape(serviceId), nil, nil)
+}
+
+// ListVersions gets all versions of a service
+func (this *Client) ListVersions(ctx context.Context, serviceId string) ([]versiondb.VersionEntity, error) {
+	var page VersionPage
+	if err := this.do(ctx, "GET", "/versions/"+url.PathEscape(serviceId), nil, &page); err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// ListVersionsPage gets a page of versions of a service. next is "" for the first page.
+func (this *Client) ListVersionsPage(ctx context.Context, serviceId string, limit int, next string) (*VersionPage, error) {
+	var page VersionPage
+	if err := this.do(ctx, "GET", "/versions/"+url.PathEscape(serviceId)+pageQuery(limit, next), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// WalkVersions calls fn for every page of versions until the last page or an error
+func (this *Client) WalkVersions(ctx context.Context, serviceId string, limit int, fn func(*VersionPage) error) error {
+	next := ""
+	for {
+		page, err := this.ListVersionsPage(ctx, serviceId, limit, next)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if page.Next == "" {
+			return nil
+		}
+		next = page.Next
+	}
+}
+
+// GetVersion finds a version in the versions of a service. It returns a 404 *Error if not found.
+func (this *Client) GetVersion(ctx context.Context, serviceId string, version string) (*versiondb.VersionEntity, error) {
+	versions, err := this.ListVersions(ctx, serviceId)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, &Error{StatusCode: 404}
+}
+
+// UploadVersion uploads a swagger file as a new version
+func (this *Client) UploadVersion(ctx context.Context, serviceId string, input UploadVersionInput) (*UploadVersionResult, error) {
+	var result UploadVersionResult
+	if err := this.do(ctx, "PUT", "/versions/"+url.PathEscape(serviceId), input, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateVersion updates the enable flag and tag of a version. Path must be empty or the path of the version.
+func (this *Client) UpdateVersion(ctx context.Context, serviceId string, version string, input UpdateVersionInput) (*versiondb.VersionEntity, error) {
+	var updated versiondb.VersionEntity
+	if err := this.do(ctx, "PATCH", versionPath(serviceId, version), input, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteVersion deletes a version and its swagger file
+func (this *Client) DeleteVersion(ctx context.Context, serviceId string, version string) (*versiondb.VersionEntity, error) {
+	var deleted versiondb.VersionEntity
+	if err := this.do(ctx, "DELETE", versionPath(serviceId, version), nil, &deleted); err != nil {
+		return nil, err
+	}
+	return &deleted, nil
+}
+
+// DownloadVersion gets the swagger file of a version
+func (this *Client) DownloadVersion(ctx context.Context, serviceId string, version string) (string, error) {
+	return this.DownloadVersionWith(ctx, serviceId, version, DownloadOptions{})
+}
+
+// DownloadVersionWith gets the swagger file of a version with options
+func (this *Client) DownloadVersionWith(ctx context.Context, serviceId string, version string, opts DownloadOptions) (string, error) {
+	query := url.Values{}
+	if opts.Convert != "" {
+		query.Set("convert", opts.Convert)
+	}
+	if opts.Format != "" {
+		query.Set("format", opts.Format)
+	}
+	path := versionPath(serviceId, version) + "/download"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	raw, err := this.Raw(ctx, "GET", path, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// DownloadSource gets the original tree of a multi-file version
+func (this *Client) DownloadSource(ctx context.Context, serviceId string, version string) (*swagger.SourceTree, error) {
+	var tree swagger.SourceTree
+	if err := this.do(ctx, "GET", versionPath(serviceId, version)+"/download?source=true", nil, &tree); err != nil {
+		return nil, err
+	}
+	return &tree, nil
+}
+
+// ExportVersion exports the requests of a version as a Postman collection (format "postman")
+// or a .http file (format "http")
+func (this *Client) ExportVersion(ctx context.Context, serviceId string, version string, format string) (string, error) {
+	raw, err := this.Raw(ctx, "GET", versionPath(serviceId, version)+"/export?format="+url.QueryEscape(format), nil)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// ExportMarkdown gets a zip archive of the markdown documentation of a version.
+// split is "service" (one document) or "tag" (one document per tag).
+func (this *Client) ExportMarkdown(ctx context.Context, serviceId string, version string, split string) ([]byte, error) {
+	path := "/docs/" + url.PathEscape(serviceId) + "/" + url.PathEscape(version) + "/markdown"
+	if split != "" {
+		path += "?split=" + url.QueryEscape(split)
+	}
+	return this.raw(ctx, "GET", path, nil, "application/zip")
+}
+
+// GenerateGo gets a zip archive of a Go package generated from a version.
+// kind is "client" or "server", pkg is the package name ("" derives it from the title).
+func (this *Client) GenerateGo(ctx context.Context, serviceId string, version string, kind string, pkg string) ([]byte, error) {
+	query := url.Values{}
+	if kind != "" {
+		query.Set("kind", kind)
+	}
+	if pkg != "" {
+		query.Set("package", pkg)
+	}
+	path := versionPath(serviceId, version) + "/codegen/go"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return this.raw(ctx, "GET", path, nil, "application/zip")
+}
+
+// GenerateTypeScript gets the TypeScript type definitions (.d.ts) of a version.
+func (this *Client) GenerateTypeScript(ctx context.Context, serviceId string, version string) (string, error) {
+	raw, err := this.Raw(ctx, "GET", versionPath(serviceId, version)+"/codegen/typescript", nil)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// GetChangelog renders the changelog of a service in markdown, html or json.
+// from and to are "" for the changelog across all versions.
+func (this *Client) GetChangelog(ctx context.Context, serviceId string, from string, to string, format string) (string, error) {
+	query := url.Values{}
+	if from != "" || to != "" {
+		query.Set("from", from)
+		query.Set("to", to)
+	}
+	if format != "" {
+		query.Set("format", format)
+	}
+	path := "/versions/" + url.PathEscape(serviceId) + "/changelog"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	raw, err := this.Raw(ctx, "GET", path, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// ListOperations gets the operation inventory of a version
+func (this *Client) ListOperations(ctx context.Context, serviceId string, version string) ([]swagger.InventoryItem, error) {
+	var list operationList
+	if err := this.do(ctx, "GET", versionPath(serviceId, version)+"/operations", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// Search searches all enabled versions
+func (this *Client) Search(ctx context.Context, query string, opts SearchOptions) (*SearchResult, error) {
+	values := url.Values{}
+	values.Set("q", query)
+	if opts.Service != "" {
+		values.Set("service", opts.Service)
+	}
+	if opts.Tag != "" {
+		values.Set("tag", opts.Tag)
+	}
+	if opts.Method != "" {
+		values.Set("method", opts.Method)
+	}
+	if opts.Limit > 0 {
+		values.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	var result SearchResult
+	if err := this.do(ctx, "GET", "/search?"+values.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetGateway merges the prod versions of services into one spec. services are "<id>" or "<id>:<prefix>".
+func (this *Client) GetGateway(ctx context.Context, services []string, title string) (*gateway.Result, error) {
+	values := url.Values{}
+	values.Set("services", strings.Join(services, ","))
+	if title != "" {
+		values.Set("title", title)
+	}
+	var result gateway.Result
+	if err := this.do(ctx, "GET", "/gateway?"+values.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// LintVersion lints a version with the ruleset of the service
+func (this *Client) LintVersion(ctx context.Context, serviceId string, version string) (*lint.Result, error) {
+	var result lint.Result
+	if err := this.do(ctx, "GET", versionPath(serviceId, version)+"/lint", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateLintRules sets the YAML lint ruleset of a service. "" restores the default rules.
+func (this *Client) UpdateLintRules(ctx context.Context, serviceId string, rules string) (*servicedb.ServiceEntity, error) {
+	var service servicedb.ServiceEntity
+	if err := this.do(ctx, "PUT", "/services/"+url.PathEscape(serviceId)+"/lint", map[string]string{"rules": rules}, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// UpdateReviewPolicy sets the review policy of a service. A policy without approvals removes the policy.
+func (this *Client) UpdateReviewPolicy(ctx context.Context, serviceId string, policy servicedb.ReviewPolicy) (*servicedb.ServiceEntity, error) {
+	var service servicedb.ServiceEntity
+	if err := this.do(ctx, "PUT", "/services/"+url.PathEscape(serviceId)+"/review", policy, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// ReviewVersion approves or rejects a version pending review. decision is "approve" or "reject".
+func (this *Client) ReviewVersion(ctx context.Context, serviceId string, version string, decision string, comment string) (*versiondb.VersionEntity, error) {
+	var reviewed versiondb.VersionEntity
+	body := map[string]string{"decision": decision, "comment": comment}
+	if err := this.do(ctx, "POST", versionPath(serviceId, version)+"/review", body, &reviewed); err != nil {
+		return nil, err
+	}
+	return &reviewed, nil
+}
+
+// CreateComment starts a thread on an operation or a schema of a version, or replies to a thread
+func (this *Client) CreateComment(ctx context.Context, serviceId string, version string, input CreateCommentInput) (*commentdb.CommentEntity, error) {
+	var created commentdb.CommentEntity
+	if err := this.do(ctx, "POST", versionPath(serviceId, version)+"/comments", input, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ListComments gets the comment threads of a service. If version is not empty, it gets the threads
+// of the version including the threads carried from earlier versions.
+func (this *Client) ListComments(ctx context.Context, serviceId string, version string, opts CommentOptions) ([]comment.Thread, error) {
+	path := "/versions/" + url.PathEscape(serviceId) + "/comments"
+	if version != "" {
+		path = versionPath(serviceId, version) + "/comments"
+	}
+	values := url.Values{}
+	if opts.Pointer != "" {
+		values.Set("pointer", opts.Pointer)
+	}
+	if opts.Resolved != "" {
+		values.Set("resolved", opts.Resolved)
+	}
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	var list commentList
+	if err := this.do(ctx, "GET", path, nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ResolveComment resolves or unresolves a thread
+func (this *Client) ResolveComment(ctx context.Context, serviceId string, commentId string, resolved bool) (*commentdb.CommentEntity, error) {
+	var updated commentdb.CommentEntity
+	body := map[string]bool{"resolved": resolved}
+	if err := this.do(ctx, "PATCH", "/versions/"+url.PathEscape(serviceId)+"/comments/"+url.PathEscape(commentId), body, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// UpdateDependencies sets the services consumed by a service. An empty list removes the dependencies.
+func (this *Client) UpdateDependencies(ctx context.Context, serviceId string, dependencies []servicedb.Dependency) (*servicedb.ServiceEntity, error) {
+	if dependencies == nil {
+		dependencies = []servicedb.Dependency{}
+	}
+	var service servicedb.ServiceEntity
+	body := map[string][]servicedb.Dependency{"dependencies": dependencies}
+	if err := this.do(ctx, "PUT", "/services/"+url.PathEscape(serviceId)+"/dependencies", body, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// RegisterDependency sets the range and the operations of a service called by a consumer,
+// keeping its other dependencies. Empty operations mean all operations.
+func (this *Client) RegisterDependency(ctx context.Context, serviceId string, providerId string, rng string, operations []string) (*servicedb.ServiceEntity, error) {
+	var service servicedb.ServiceEntity
+	body := map[string]interface{}{"range": rng, "operations": operations}
+	if err := this.do(ctx, "PUT", "/services/"+url.PathEscape(serviceId)+"/dependencies/"+url.PathEscape(providerId), body, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// ListConsumers gets the dependencies of the services consuming a service
+func (this *Client) ListConsumers(ctx context.Context, serviceId string) ([]dependency.Edge, error) {
+	var list consumerList
+	if err := this.do(ctx, "GET", "/services/"+url.PathEscape(serviceId)+"/consumers", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// GetDependencyGraph gets the dependency graph of all services
+func (this *Client) GetDependencyGraph(ctx context.Context) (*dependency.Graph, error) {
+	var graph dependency.Graph
+	if err := this.do(ctx, "GET", "/dependencies", nil, &graph); err != nil {
+		return nil, err
+	}
+	return &graph, nil
+}
+
+// GetDependencyGraphDOT gets the dependency graph of all services in the Graphviz DOT language
+func (this *Client) GetDependencyGraphDOT(ctx context.Context) (string, error) {
+	raw, err := this.Raw(ctx, "GET", "/dependencies?format=dot", nil)
+	return string(raw), err
+}
+
+// GetAudits gets audit records since the unix time in milliseconds. serviceId "" means all services.
+func (this *Client) GetAudits(ctx context.Context, serviceId string, since int64) ([]auditdb.AuditEntity, error) {
+	query := url.Values{}
+	if serviceId != "" {
+		query.Set("service", serviceId)
+	}
+	if since > 0 {
+		query.Set("since", strconv.FormatInt(since, 10))
+	}
+	path := "/audit"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var list auditList
+	if err := this.do(ctx, "GET", path, nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// CreateWebhook registers a webhook. The returned entity contains the secret.
+func (this *Client) CreateWebhook(ctx context.Context, serviceId string, input CreateWebhookInput) (*webhookdb.WebhookEntity, error) {
+	var webhook webhookdb.WebhookEntity
+	if err := this.do(ctx, "POST", webhooksPath(serviceId), input, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// ListWebhooks gets the webhooks of a service. Secrets are masked.
+func (this *Client) ListWebhooks(ctx context.Context, serviceId string) ([]webhookdb.WebhookEntity, error) {
+	var list webhookList
+	if err := this.do(ctx, "GET", webhooksPath(serviceId), nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// DeleteWebhook deletes a webhook
+func (this *Client) DeleteWebhook(ctx context.Context, serviceId string, webhookId string) error {
+	return this.do(ctx, "DELETE", webhooksPath(serviceId)+"/"+url.PathEscape(webhookId), nil, nil)
+}
+
+// ListWebhookDeliveries gets the delivery log of a webhook
+func (this *Client) ListWebhookDeliveries(ctx context.Context, serviceId string, webhookId string) ([]webhookdb.DeliveryEntity, error) {
+	var list deliveryList
+	if err := this.do(ctx, "GET", webhooksPath(serviceId)+"/"+url.PathEscape(webhookId)+"/deliveries", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func versionPath(serviceId string, version string) string {
+	return "/versions/" + url.PathEscape(serviceId) + "/versions/" + url.PathEscape(version)
+}
+
+func webhooksPath(serviceId string) string {
+	return "/services/" + url.PathEscape(serviceId) + "/webhooks"
+}
+
+func pageQuery(limit int, next string) string {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	if next != "" {
+		query.Set("next", next)
+	}
+	return "?" + query.Encode()
+}
@@ -0,0 +1,162 @@
+// Package client is a typed client of the swagger viewer api.
+//
+//	c := client.New("https://xxx.execute-api.ap-northeast-1.amazonaws.com/dev", apiKey)
+//	services, err := c.ListServices(ctx)
+//	if client.IsNotFound(err) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// Error is an error response of the api. Body is decoded from the response if possible.
+type Error struct {
+	StatusCode int
+	Body       common.ErrorBody
+}
+
+func (err *Error) Error() string {
+	if err.Body.Error.Message == "" {
+		return fmt.Sprintf("api error: status %d", err.StatusCode)
+	}
+	return fmt.Sprintf("api error: status %d: %d %s", err.StatusCode, err.Body.Error.Code, err.Body.Error.Message)
+}
+
+// IsNotFound returns true if err is a 404 response
+func IsNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == 404
+}
+
+// Client is a client of the swagger viewer api
+type Client struct {
+	Endpoint   string
+	APIKey     string
+	HTTPClient *http.Client
+	// MaxRetries is the number of retries of idempotent requests (GET, PUT, PATCH, DELETE)
+	// on 5xx responses and network errors. POST is never retried.
+	MaxRetries int
+	// InitialBackoff is doubled on every retry
+	InitialBackoff time.Duration
+}
+
+// New returns a client with default settings
+func New(endpoint string, apiKey string) *Client {
+	return &Client{
+		Endpoint:       strings.TrimRight(endpoint, "/"),
+		APIKey:         apiKey,
+		HTTPClient:     &http.Client{Timeout: 60 * time.Second},
+		MaxRetries:     2,
+		InitialBackoff: 200 * time.Millisecond,
+	}
+}
+
+// do sends a json request and decodes the json response into out (if out is not nil)
+func (this *Client) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	raw, err := this.Raw(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("invalid response: %v", err)
+	}
+	return nil
+}
+
+// Raw sends a request with a json body (if body is not nil) and returns the response body.
+// Responses with status >= 400 are returned as *Error.
+func (this *Client) Raw(ctx context.Context, method string, path string, body interface{}) ([]byte, error) {
+	return this.raw(ctx, method, path, body, "")
+}
+
+// raw sends a request with an Accept header, which API Gateway requires to return binary bodies
+func (this *Client) raw(ctx context.Context, method string, path string, body interface{}, accept string) ([]byte, error) {
+	var payload []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = b
+	}
+
+	retries := 0
+	if method != http.MethodPost {
+		retries = this.MaxRetries
+	}
+	backoff := this.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		raw, err := this.send(ctx, method, path, payload, accept)
+		if err == nil || attempt >= retries || !retryable(err) {
+			return raw, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (this *Client) send(ctx context.Context, method string, path string, payload []byte, accept string) ([]byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, this.Endpoint+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if this.APIKey != "" {
+		req.Header.Set("x-api-key", this.APIKey)
+	}
+
+	httpClient := this.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		json.Unmarshal(raw, &apiErr.Body)
+		return nil, apiErr
+	}
+	return raw, nil
+}
+
+// retryable returns true for 5xx responses and network errors (not for context errors)
+func retryable(err error) bool {
+	if e, ok := err.(*Error); ok {
+		return e.StatusCode >= 500
+	}
+	return err != context.Canceled && err != context.DeadlineExceeded
+}
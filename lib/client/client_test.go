@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/server"
+)
+
+func newTestClient(routes ...server.Route) (*Client, func()) {
+	ts := httptest.NewServer(server.New(routes...))
+	c := New(ts.URL, "key")
+	c.InitialBackoff = time.Millisecond
+	return c, ts.Close
+}
+
+func TestApiKey(t *testing.T) {
+	// the handlers of the services are tested with the client in src/
+	c, closer := newTestClient(server.Route{Method: "POST", Path: "services", Handler: func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		if request.RequestContext.Identity.APIKey != "key" {
+			return common.CreateErrorResponse(403, common.ErrorBody{})
+		}
+		var body map[string]string
+		json.Unmarshal([]byte(request.Body), &body)
+		return common.CreateResponse(201, servicedb.ServiceEntity{Id: "s4", Servicename: body["servicename"]})
+	}})
+	defer closer()
+
+	created, err := c.CreateService(context.Background(), "newservice")
+	if err != nil || created.Id != "s4" || created.Servicename != "newservice" {
+		t.Fatalf("failed test %#v %#v", created, err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	calls := 0
+	flaky := func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		calls++
+		if calls < 3 {
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "DB Error",
+				},
+			})
+		}
+		return common.CreateResponse(200, map[string]interface{}{"Items": []servicedb.ServiceEntity{}})
+	}
+	c, closer := newTestClient(
+		server.Route{Method: "GET", Path: "services", Handler: flaky},
+		server.Route{Method: "POST", Path: "services", Handler: flaky},
+	)
+	defer closer()
+
+	if _, err := c.ListServices(context.Background()); err != nil || calls != 3 {
+		t.Fatalf("GET must be retried %d %#v", calls, err)
+	}
+
+	calls = 0
+	_, err := c.CreateService(context.Background(), "newservice")
+	if e, ok := err.(*Error); !ok || e.StatusCode != 500 || calls != 1 {
+		t.Fatalf("POST must not be retried %d %#v", calls, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.ListServices(ctx); err == nil {
+		t.Fatalf("canceled context must fail")
+	}
+}
@@ -0,0 +1,933 @@
+// Package codegen generates code from documents: Go types with a typed client or an
+// interface based server stub.
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// Kinds of generated Go code
+const (
+	KindClient = "client"
+	KindServer = "server"
+)
+
+// Generated files
+const (
+	TypesFile  = "types.go"
+	ClientFile = "client.go"
+	ServerFile = "server.go"
+)
+
+const schemaPrefix = "#/components/schemas/"
+
+var pathParam = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// packages which may be used by generated code, by name
+var packages = map[string]string{
+	"bytes": "bytes", "context": "context", "errors": "errors", "fmt": "fmt", "io": "io",
+	"json": "encoding/json", "http": "net/http", "url": "net/url", "reflect": "reflect",
+	"strconv": "strconv", "strings": "strings", "time": "time",
+}
+
+// names of the generated code which must not be used by types
+var reservedNames = []string{"Client", "NewClient", "DefaultBaseURL", "Server", "NewHandler", "BasePath", "Error"}
+
+// names of the generated functions which must not be used by arguments
+var reservedArgs = []string{"c", "s", "w", "r", "ctx", "params", "body", "payload", "result", "err", "path", "query", "header", "mux"}
+
+type generator struct {
+	doc swagger.Document
+	// names of the types and constants
+	names namer
+	// go names of named schemas
+	schemas map[string]string
+	// declarations of types.go
+	decls []string
+	// named schemas which are declared
+	declared map[string]bool
+	// types being declared. Fields of these types are pointers, to break cycles.
+	building   map[string]bool
+	operations []operation
+}
+
+type operation struct {
+	swagger.Operation
+	Name       string
+	PathParams []param
+	// query and header parameters, fields of ParamsType
+	Params     []param
+	ParamsType string
+	// the go type of the body, "" without body
+	Body         string
+	BodyJSON     bool
+	BodyRequired bool
+	ContentType  string
+	// the go type of the successful response, "" without content
+	Result     string
+	ResultJSON bool
+	Accept     string
+	Status     int
+}
+
+type param struct {
+	Name     string // in the document
+	In       string
+	GoName   string // the argument or the field
+	Type     string
+	Required bool
+}
+
+// Go generates a package named pkg with the types of the schemas (TypesFile) and either a typed client
+// (ClientFile) or an interface based server stub using net/http routing (ServerFile).
+// Files are named "<pkg>/<file>". Swagger 2.0 documents are converted to OpenAPI 3 first.
+// If pkg is "", it is derived from the title.
+func Go(doc swagger.Document, kind string, pkg string) (map[string]string, error) {
+	if kind != KindClient && kind != KindServer {
+		return nil, common.NewError(24001, "unknown kind "+kind, nil)
+	}
+	if pkg == "" {
+		pkg = packageName(doc.Title())
+	}
+	doc = swagger.ConvertToOAS3(doc)
+	g := &generator{
+		doc:      doc,
+		names:    namer{},
+		schemas:  map[string]string{},
+		declared: map[string]bool{},
+		building: map[string]bool{},
+	}
+	for _, name := range reservedNames {
+		g.names[name] = true
+	}
+
+	// names of schemas are reserved first, so that inline types get the numbered names
+	schemas := doc.Schemas()
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		goName := exportedName(name)
+		if goName == "" {
+			goName = "Schema"
+		}
+		g.schemas[name] = g.names.unique(goName)
+	}
+	for _, name := range names {
+		g.declareSchema(name)
+	}
+	methods := namer{}
+	for _, op := range doc.Operations() {
+		g.operations = append(g.operations, g.operation(op, methods))
+	}
+
+	types := append([]string{errorType}, g.decls...)
+	files := map[string]string{}
+	var err error
+	if files[pkg+"/"+TypesFile], err = g.file(pkg, strings.Join(types, "\n")); err != nil {
+		return nil, err
+	}
+	if kind == KindClient {
+		files[pkg+"/"+ClientFile], err = g.file(pkg, g.client())
+	} else {
+		files[pkg+"/"+ServerFile], err = g.file(pkg, g.server())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// file adds the header and the imports used by body, and formats the file
+func (this *generator) file(pkg string, body string) (string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", "package "+pkg+"\n"+body, 0)
+	if err != nil {
+		return "", common.NewError(24001, "codegen error", err)
+	}
+	used := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && packages[ident.Name] != "" {
+				used[packages[ident.Name]] = true
+			}
+		}
+		return true
+	})
+	imports := make([]string, 0, len(used))
+	for path := range used {
+		imports = append(imports, strconv.Quote(path))
+	}
+	sort.Strings(imports)
+
+	var b strings.Builder
+	title := strings.Join(strings.Fields(this.doc.Title()+" "+this.doc.Version()), " ")
+	fmt.Fprintf(&b, "// Code generated by swagger-viewer from %q. DO NOT EDIT.\n\npackage %s\n", title, pkg)
+	if len(imports) > 0 {
+		fmt.Fprintf(&b, "\nimport (\n\t%s\n)\n", strings.Join(imports, "\n\t"))
+	}
+	b.WriteString(body)
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", common.NewError(24001, "codegen error", err)
+	}
+	return string(src), nil
+}
+
+// comment writes text as a comment, line by line
+func comment(b *strings.Builder, indent string, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimRightFunc(line, func(r rune) bool { return r == ' ' || r == '\t' || r == '\r' }))
+	}
+}
+
+// reserve adds an empty declaration and returns its index, so that dependencies follow their users
+func (this *generator) reserve() int {
+	this.decls = append(this.decls, "")
+	return len(this.decls) - 1
+}
+
+func (this *generator) declareSchema(name string) string {
+	goName := this.schemas[name]
+	if this.declared[name] {
+		return goName
+	}
+	this.declared[name] = true
+	schema, _ := this.doc.Schemas()[name].(map[string]interface{})
+	doc := fmt.Sprintf("%s is the schema %s.", goName, name)
+	if description := swagger.String(schema, "description"); description != "" {
+		doc += "\n\n" + description
+	}
+
+	switch kindOf(schema) {
+	case "struct":
+		this.declareStruct(schema, goName, doc)
+	case "enum":
+		this.declareEnum(schema, goName, doc)
+	default:
+		index := this.reserve()
+		this.building[goName] = true
+		typ := this.typeOf(schema, goName, "of "+goName)
+		delete(this.building, goName)
+		var b strings.Builder
+		comment(&b, "", doc)
+		// aliases keep the methods of imported types (e.g. MarshalJSON)
+		if strings.Contains(typ, ".") {
+			fmt.Fprintf(&b, "type %s = %s\n", goName, typ)
+		} else {
+			fmt.Fprintf(&b, "type %s %s\n", goName, typ)
+		}
+		this.decls[index] = b.String()
+	}
+	return goName
+}
+
+// kindOf returns how a schema is generated
+func kindOf(schema map[string]interface{}) string {
+	switch {
+	case schema == nil:
+		return "any"
+	case swagger.String(schema, "$ref") != "":
+		return "ref"
+	case swagger.Slice(schema, "oneOf") != nil || swagger.Slice(schema, "anyOf") != nil:
+		return "union"
+	case swagger.Slice(schema, "allOf") != nil || swagger.Map(schema, "properties") != nil:
+		return "struct"
+	}
+	switch typ := swagger.String(schema, "type"); typ {
+	case "string":
+		if len(swagger.Slice(schema, "enum")) > 0 {
+			return "enum"
+		}
+		return typ
+	case "integer", "number", "boolean", "array":
+		return typ
+	case "object":
+		return "map"
+	}
+	if schema["additionalProperties"] != nil {
+		return "map"
+	}
+	return "any"
+}
+
+// typeOf returns the go type of a schema. Inline objects and enums are declared as types named name.
+func (this *generator) typeOf(schema map[string]interface{}, name string, origin string) string {
+	format := swagger.String(schema, "format")
+	switch kindOf(schema) {
+	case "ref":
+		ref := swagger.String(schema, "$ref")
+		if _, ok := this.schemas[strings.TrimPrefix(ref, schemaPrefix)]; ok && strings.HasPrefix(ref, schemaPrefix) {
+			return this.declareSchema(strings.TrimPrefix(ref, schemaPrefix))
+		}
+		resolved, _ := this.doc.Resolve(ref).(map[string]interface{})
+		if swagger.String(resolved, "$ref") == ref {
+			return "interface{}"
+		}
+		return this.typeOf(resolved, name, origin)
+	case "union":
+		return "json.RawMessage"
+	case "struct":
+		goName := this.names.unique(name)
+		return this.declareStruct(schema, goName, fmt.Sprintf("%s is the inline schema %s.", goName, origin))
+	case "enum":
+		goName := this.names.unique(name)
+		return this.declareEnum(schema, goName, fmt.Sprintf("%s is the inline schema %s.", goName, origin))
+	case "string":
+		if format == "date-time" {
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if format == "int32" || format == "int64" {
+			return format
+		}
+		return "int"
+	case "number":
+		if format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + this.typeOf(swagger.Map(schema, "items"), name+"Item", "of the items "+origin)
+	case "map":
+		if additional := swagger.Map(schema, "additionalProperties"); additional != nil {
+			return "map[string]" + this.typeOf(additional, name+"Value", "of the values "+origin)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// nillable returns true for types which are not pointers of optional fields
+func nillable(typ string) bool {
+	for _, prefix := range []string{"[]", "map[", "*"} {
+		if strings.HasPrefix(typ, prefix) {
+			return true
+		}
+	}
+	return typ == "interface{}" || typ == "json.RawMessage"
+}
+
+// declareStruct declares an object. allOf members which are named schemas are embedded,
+// the properties of the others are merged.
+func (this *generator) declareStruct(schema map[string]interface{}, goName string, doc string) string {
+	index := this.reserve()
+	this.building[goName] = true
+	defer delete(this.building, goName)
+
+	fields := namer{}
+	var b strings.Builder
+	comment(&b, "", doc)
+	fmt.Fprintf(&b, "type %s struct {\n", goName)
+
+	properties := map[string]interface{}{}
+	required := map[string]bool{}
+	members := append([]interface{}{schema}, swagger.Slice(schema, "allOf")...)
+	for i, m := range members {
+		member, _ := m.(map[string]interface{})
+		if ref := swagger.String(member, "$ref"); i > 0 && ref != "" {
+			if _, ok := this.schemas[strings.TrimPrefix(ref, schemaPrefix)]; ok && strings.HasPrefix(ref, schemaPrefix) {
+				typ := this.declareSchema(strings.TrimPrefix(ref, schemaPrefix))
+				fields[typ] = true
+				if this.building[typ] {
+					typ = "*" + typ
+				}
+				fmt.Fprintf(&b, "\t%s\n", typ)
+				continue
+			}
+			member, _ = this.doc.Resolve(ref).(map[string]interface{})
+		}
+		for name, p := range swagger.Map(member, "properties") {
+			properties[name] = p
+		}
+		for _, name := range swagger.Strings(member, "required") {
+			required[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.ContainsAny(name, "`\",") {
+			fmt.Fprintf(&b, "\t// the property %q is not generated\n", name)
+			continue
+		}
+		property, _ := properties[name].(map[string]interface{})
+		fieldName := exportedName(name)
+		if fieldName == "" {
+			fieldName = "Field"
+		}
+		fieldName = fields.unique(fieldName)
+		typ := this.typeOf(property, goName+exportedName(name), fmt.Sprintf("of the property %s of %s", name, goName))
+		tag := name
+		if !required[name] {
+			tag += ",omitempty"
+			if !nillable(typ) {
+				typ = "*" + typ
+			}
+		} else if this.building[typ] {
+			typ = "*" + typ
+		}
+		if description := swagger.String(property, "description"); description != "" {
+			comment(&b, "\t", description)
+		}
+		if swagger.Bool(property, "deprecated") {
+			comment(&b, "\t", "Deprecated: the property is deprecated.")
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", fieldName, typ, tag)
+	}
+	b.WriteString("}\n")
+	this.decls[index] = b.String()
+	return goName
+}
+
+// declareEnum declares a string type with a constant per value
+func (this *generator) declareEnum(schema map[string]interface{}, goName string, doc string) string {
+	var b strings.Builder
+	comment(&b, "", doc)
+	fmt.Fprintf(&b, "type %s string\n\n// Values of %s\nconst (\n", goName, goName)
+	for i, value := range swagger.Slice(schema, "enum") {
+		s := fmt.Sprintf("%v", value)
+		suffix := exportedName(s)
+		if suffix == "" {
+			suffix = "Value" + strconv.Itoa(i)
+		}
+		fmt.Fprintf(&b, "\t%s %s = %s\n", this.names.unique(goName+suffix), goName, strconv.Quote(s))
+	}
+	b.WriteString(")\n")
+	this.decls = append(this.decls, b.String())
+	return goName
+}
+
+func (this *generator) operation(op swagger.Operation, methods namer) operation {
+	name := exportedName(op.OperationId)
+	if name == "" {
+		name = exportedName(op.Method + " " + pathParam.ReplaceAllString(op.Path, "$1"))
+	}
+	result := operation{Operation: op, Name: methods.unique(name), Status: 200}
+
+	args := namer{}
+	for _, arg := range reservedArgs {
+		args[arg] = true
+	}
+	for name := range packages {
+		args[name] = true
+	}
+	declared := map[string]swagger.Parameter{}
+	for _, p := range op.Parameters {
+		if p.In == "path" {
+			declared[p.Name] = p
+		}
+	}
+	for _, match := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		p := param{Name: match[1], In: "path", Required: true, Type: "string"}
+		if _, ok := declared[p.Name]; ok {
+			p.Type = this.typeOf(swagger.Map(declared[p.Name].Raw, "schema"), result.Name+exportedName(p.Name), "of the parameter "+p.Name+" of "+result.Name)
+		}
+		argName := unexportedName(p.Name)
+		if argName == "" {
+			argName = "param"
+		}
+		p.GoName = args.unique(argName)
+		result.PathParams = append(result.PathParams, p)
+	}
+
+	fields := namer{}
+	for _, p := range op.Parameters {
+		if p.In != "query" && p.In != "header" {
+			continue
+		}
+		fieldName := exportedName(p.Name)
+		if fieldName == "" {
+			fieldName = "Param"
+		}
+		typ := this.typeOf(swagger.Map(p.Raw, "schema"), result.Name+exportedName(p.Name), "of the parameter "+p.Name+" of "+result.Name)
+		if !p.Required && !nillable(typ) {
+			typ = "*" + typ
+		}
+		result.Params = append(result.Params, param{Name: p.Name, In: p.In, GoName: fields.unique(fieldName), Type: typ, Required: p.Required})
+	}
+	if len(result.Params) > 0 {
+		result.ParamsType = this.names.unique(result.Name + "Params")
+		var b strings.Builder
+		comment(&b, "", fmt.Sprintf("%s are the query and header parameters of %s.", result.ParamsType, result.Name))
+		fmt.Fprintf(&b, "type %s struct {\n", result.ParamsType)
+		for _, p := range result.Params {
+			required := ""
+			if p.Required {
+				required = " (required)"
+			}
+			comment(&b, "\t", fmt.Sprintf("%s is the %s parameter %q%s", p.GoName, p.In, p.Name, required))
+			fmt.Fprintf(&b, "\t%s %s\n", p.GoName, p.Type)
+		}
+		b.WriteString("}\n")
+		this.decls = append(this.decls, b.String())
+	}
+
+	if content := swagger.Map(op.RequestBody, "content"); content != nil {
+		mediaType, json := pickMediaType(content)
+		result.ContentType = mediaType
+		result.BodyRequired = swagger.Bool(op.RequestBody, "required")
+		result.BodyJSON = json
+		result.Body = "io.Reader"
+		if json {
+			result.Body = this.typeOf(swagger.Map(swagger.Map(content, mediaType), "schema"), result.Name+"Request", "of the request body of "+result.Name)
+			if !result.BodyRequired && !nillable(result.Body) {
+				result.Body = "*" + result.Body
+			}
+		}
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	code := "default"
+	for _, c := range codes {
+		if status, err := strconv.Atoi(c); err == nil && status >= 200 && status < 300 {
+			code, result.Status = c, status
+			break
+		}
+	}
+	response, _ := op.Responses[code].(map[string]interface{})
+	if ref := swagger.String(response, "$ref"); ref != "" {
+		response, _ = this.doc.Resolve(ref).(map[string]interface{})
+	}
+	if content := swagger.Map(response, "content"); len(content) > 0 {
+		mediaType, json := pickMediaType(content)
+		result.Accept = mediaType
+		result.ResultJSON = json
+		result.Result = "[]byte"
+		if json {
+			result.Result = this.typeOf(swagger.Map(swagger.Map(content, mediaType), "schema"), result.Name+"Response", "of the response of "+result.Name)
+		}
+	}
+	return result
+}
+
+// pickMediaType returns the json media type of content if any, or the first one
+func pickMediaType(content map[string]interface{}) (string, bool) {
+	types := make([]string, 0, len(content))
+	for mediaType := range content {
+		types = append(types, mediaType)
+	}
+	sort.Strings(types)
+	for _, mediaType := range types {
+		if mediaType == "application/json" {
+			return mediaType, true
+		}
+	}
+	for _, mediaType := range types {
+		if strings.HasSuffix(strings.SplitN(mediaType, ";", 2)[0], "json") {
+			return mediaType, true
+		}
+	}
+	return types[0], false
+}
+
+// signature returns the name, the arguments and the results of the method of the operation
+func (op operation) signature() string {
+	args := []string{"ctx context.Context"}
+	for _, p := range op.PathParams {
+		args = append(args, p.GoName+" "+p.Type)
+	}
+	if op.ParamsType != "" {
+		args = append(args, "params "+op.ParamsType)
+	}
+	if op.Body != "" {
+		args = append(args, "body "+op.Body)
+	}
+	results := "error"
+	if op.Result != "" {
+		results = "(" + op.Result + ", error)"
+	}
+	return fmt.Sprintf("%s(%s) %s", op.Name, strings.Join(args, ", "), results)
+}
+
+// doc returns the comment of the method of the operation
+func (op operation) doc(verb string) string {
+	text := fmt.Sprintf("%s %s %s %s", op.Name, verb, strings.ToUpper(op.Method), op.Path)
+	if op.Summary != "" {
+		text += "\n\n" + op.Summary
+	}
+	if op.Deprecated {
+		text += "\n\nDeprecated: the operation is deprecated."
+	}
+	return text
+}
+
+// serverURL returns the url of the first server, without variables
+func (this *generator) serverURL() string {
+	servers := swagger.Slice(this.doc, "servers")
+	if len(servers) == 0 {
+		return ""
+	}
+	server, _ := servers[0].(map[string]interface{})
+	variables := swagger.Map(server, "variables")
+	return pathParam.ReplaceAllStringFunc(swagger.String(server, "url"), func(v string) string {
+		return swagger.String(swagger.Map(variables, strings.Trim(v, "{}")), "default")
+	})
+}
+
+func (this *generator) client() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n// DefaultBaseURL is the url of the first server of the document\nconst DefaultBaseURL = %s\n", strconv.Quote(this.serverURL()))
+	b.WriteString(clientType)
+	for _, op := range this.operations {
+		b.WriteString("\n")
+		comment(&b, "", op.doc("calls"))
+		fmt.Fprintf(&b, "func (c *Client) %s {\n", op.signature())
+
+		path := []string{}
+		rest := op.Path
+		for _, p := range op.PathParams {
+			i := strings.Index(rest, "{"+p.Name+"}")
+			path = append(path, strconv.Quote(rest[:i]), "url.PathEscape(formatParam("+p.GoName+"))")
+			rest = rest[i+len(p.Name)+2:]
+		}
+		if rest != "" || len(path) == 0 {
+			path = append(path, strconv.Quote(rest))
+		}
+		fmt.Fprintf(&b, "\tpath := %s\n\tquery := url.Values{}\n\theader := http.Header{}\n", strings.Join(path, " + "))
+		for _, p := range op.Params {
+			set := "query.Add"
+			if p.In == "header" {
+				set = "header.Add"
+			}
+			switch {
+			case strings.HasPrefix(p.Type, "[]"):
+				fmt.Fprintf(&b, "\tfor _, v := range params.%s {\n\t\t%s(%q, formatParam(v))\n\t}\n", p.GoName, set, p.Name)
+			case strings.HasPrefix(p.Type, "*"):
+				fmt.Fprintf(&b, "\tif params.%s != nil {\n\t\t%s(%q, formatParam(*params.%s))\n\t}\n", p.GoName, set, p.Name, p.GoName)
+			case nillable(p.Type):
+				fmt.Fprintf(&b, "\tif params.%s != nil {\n\t\t%s(%q, formatParam(params.%s))\n\t}\n", p.GoName, set, p.Name, p.GoName)
+			default:
+				fmt.Fprintf(&b, "\t%s(%q, formatParam(params.%s))\n", set, p.Name, p.GoName)
+			}
+		}
+
+		payload := "nil"
+		if op.Body != "" {
+			payload = "body"
+			if strings.HasPrefix(op.Body, "*") {
+				// a nil pointer is no body rather than null
+				payload = "payload"
+				b.WriteString("\tvar payload interface{}\n\tif body != nil {\n\t\tpayload = body\n\t}\n")
+			}
+		}
+		if op.Result == "" {
+			fmt.Fprintf(&b, "\treturn c.do(ctx, %q, path, query, header, %s, %q, nil)\n}\n", strings.ToUpper(op.Method), payload, op.ContentType)
+			continue
+		}
+		fmt.Fprintf(&b, "\theader.Set(\"Accept\", %q)\n\tvar result %s\n", op.Accept, op.Result)
+		fmt.Fprintf(&b, "\terr := c.do(ctx, %q, path, query, header, %s, %q, &result)\n\treturn result, err\n}\n", strings.ToUpper(op.Method), payload, op.ContentType)
+	}
+	b.WriteString(clientHelpers)
+	return b.String()
+}
+
+func (this *generator) server() string {
+	var b strings.Builder
+	basePath := ""
+	if u, err := url.Parse(this.serverURL()); err == nil {
+		basePath = strings.TrimSuffix(u.Path, "/")
+	}
+	fmt.Fprintf(&b, "\n// BasePath is the path of the first server of the document, prefixing the paths of the operations\nconst BasePath = %s\n", strconv.Quote(basePath))
+
+	b.WriteString("\n// Server implements the operations. Errors of type *Error are written with their status code,\n// others as 500 Internal Server Error.\ntype Server interface {\n")
+	for i, op := range this.operations {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		comment(&b, "\t", op.doc("handles"))
+		fmt.Fprintf(&b, "\t%s\n", op.signature())
+	}
+	b.WriteString("}\n\n// NewHandler routes the requests of the operations to s\nfunc NewHandler(s Server) http.Handler {\n\tmux := http.NewServeMux()\n")
+	for _, op := range this.operations {
+		pattern, ok := muxPattern(op)
+		if !ok {
+			fmt.Fprintf(&b, "\t// %s %s is not routed: wildcards of ServeMux patterns are whole segments\n", strings.ToUpper(op.Method), op.Path)
+			continue
+		}
+		fmt.Fprintf(&b, "\tmux.HandleFunc(%s+%s, func(w http.ResponseWriter, r *http.Request) {\n", strconv.Quote(strings.ToUpper(op.Method)+" "), "BasePath+"+strconv.Quote(pattern))
+		args := []string{"r.Context()"}
+		for _, p := range op.PathParams {
+			fmt.Fprintf(&b, "\t\tvar %s %s\n", p.GoName, p.Type)
+			fmt.Fprintf(&b, "\t\tif err := parseParam([]string{r.PathValue(%q)}, true, &%s); err != nil {\n\t\t\twriteError(w, badRequest(%q, err))\n\t\t\treturn\n\t\t}\n", p.GoName, p.GoName, p.Name)
+			args = append(args, p.GoName)
+		}
+		if op.ParamsType != "" {
+			fmt.Fprintf(&b, "\t\tvar params %s\n", op.ParamsType)
+			for _, p := range op.Params {
+				values := fmt.Sprintf("r.URL.Query()[%q]", p.Name)
+				if p.In == "header" {
+					values = fmt.Sprintf("r.Header.Values(%q)", p.Name)
+				}
+				fmt.Fprintf(&b, "\t\tif err := parseParam(%s, %t, &params.%s); err != nil {\n\t\t\twriteError(w, badRequest(%q, err))\n\t\t\treturn\n\t\t}\n", values, p.Required, p.GoName, p.Name)
+			}
+			args = append(args, "params")
+		}
+		if op.Body != "" {
+			if op.BodyJSON {
+				fmt.Fprintf(&b, "\t\tvar body %s\n\t\tif err := decodeJSON(r, %t, &body); err != nil {\n\t\t\twriteError(w, badRequest(\"body\", err))\n\t\t\treturn\n\t\t}\n", op.Body, op.BodyRequired)
+				args = append(args, "body")
+			} else {
+				args = append(args, "r.Body")
+			}
+		}
+		call := fmt.Sprintf("s.%s(%s)", op.Name, strings.Join(args, ", "))
+		if op.Result == "" {
+			fmt.Fprintf(&b, "\t\tif err := %s; err != nil {\n\t\t\twriteError(w, err)\n\t\t\treturn\n\t\t}\n\t\tw.WriteHeader(%d)\n", call, op.Status)
+		} else {
+			fmt.Fprintf(&b, "\t\tresult, err := %s\n\t\tif err != nil {\n\t\t\twriteError(w, err)\n\t\t\treturn\n\t\t}\n", call)
+			if op.ResultJSON {
+				fmt.Fprintf(&b, "\t\twriteJSON(w, %d, %q, result)\n", op.Status, op.Accept)
+			} else {
+				fmt.Fprintf(&b, "\t\tw.Header().Set(\"Content-Type\", %q)\n\t\tw.WriteHeader(%d)\n\t\tw.Write(result)\n", op.Accept, op.Status)
+			}
+		}
+		b.WriteString("\t})\n")
+	}
+	b.WriteString("\treturn mux\n}\n")
+	b.WriteString(serverHelpers)
+	return b.String()
+}
+
+// muxPattern returns the path of the operation as a ServeMux pattern. Wildcards are named after
+// the arguments, since they must be identifiers.
+func muxPattern(op operation) (string, bool) {
+	segments := strings.Split(op.Path, "/")
+	for i, segment := range segments {
+		if !strings.ContainsAny(segment, "{}") {
+			continue
+		}
+		match := pathParam.FindStringSubmatch(segment)
+		if match == nil || match[0] != segment {
+			return "", false
+		}
+		for _, p := range op.PathParams {
+			if p.Name == match[1] {
+				segments[i] = "{" + p.GoName + "}"
+			}
+		}
+	}
+	pattern := strings.Join(segments, "/")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "{$}"
+	}
+	return pattern, true
+}
+
+const errorType = `
+// Error is a response with an error status code
+type Error struct {
+	StatusCode int    ` + "`json:\"statusCode\"`" + `
+	Message    string ` + "`json:\"message\"`" + `
+}
+
+func (e *Error) Error() string {
+	return strconv.Itoa(e.StatusCode) + " " + e.Message
+}
+`
+
+const clientType = `
+// Client calls the operations
+type Client struct {
+	// BaseURL prefixes the paths of the operations
+	BaseURL string
+	// HTTPClient sends the requests (http.DefaultClient if nil)
+	HTTPClient *http.Client
+	// Header is added to every request (e.g. Authorization)
+	Header http.Header
+}
+
+// NewClient returns a client of the api at baseURL (e.g. DefaultBaseURL)
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL, Header: http.Header{}}
+}
+`
+
+const clientHelpers = `
+// do sends a request. Bodies are io.Reader or encoded as JSON. Results are []byte or decoded from JSON.
+// Error status codes are returned as *Error.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, header http.Header, body interface{}, contentType string, result interface{}) error {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range c.Header {
+		req.Header[key] = values
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	switch r := result.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*r = data
+		return nil
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+func formatParam(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+`
+
+const serverHelpers = `
+func badRequest(name string, err error) error {
+	return &Error{StatusCode: http.StatusBadRequest, Message: name + ": " + err.Error()}
+}
+
+// parseParam parses the values of a parameter into dst. Arrays are repeated or comma separated values.
+func parseParam(values []string, required bool, dst interface{}) error {
+	if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+		if required {
+			return errors.New("is required")
+		}
+		return nil
+	}
+	v := reflect.ValueOf(dst).Elem()
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return parseScalar(values[0], v)
+	}
+	if len(values) == 1 {
+		values = strings.Split(values[0], ",")
+	}
+	slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := parseScalar(value, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+	v.Set(slice)
+	return nil
+}
+
+func parseScalar(s string, v reflect.Value) error {
+	if t, ok := v.Addr().Interface().(*time.Time); ok {
+		parsed, err := time.Parse(time.RFC3339, s)
+		*t = parsed
+		return err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return json.Unmarshal([]byte(s), v.Addr().Interface())
+	}
+	return nil
+}
+
+// decodeJSON decodes the body of r into dst. Optional bodies may be empty.
+func decodeJSON(r *http.Request, required bool, dst interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(dst)
+	if err == io.EOF {
+		if required {
+			return errors.New("is required")
+		}
+		return nil
+	}
+	return err
+}
+
+func writeJSON(w http.ResponseWriter, status int, contentType string, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{StatusCode: http.StatusInternalServerError, Message: http.StatusText(http.StatusInternalServerError)}
+	}
+	writeJSON(w, e.StatusCode, "application/json", e)
+}
+`
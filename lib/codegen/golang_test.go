@@ -0,0 +1,231 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var petstore = `
+openapi: 3.0.0
+info: {title: Pet Store, version: 1.0.0}
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, format: int32}}
+        - {name: tags, in: query, schema: {type: array, items: {type: string}}}
+        - {name: X-Request-Id, in: header, required: true, schema: {type: string}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                status: {$ref: '#/components/schemas/Status'}
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+  /pets/{pet_id}:
+    delete:
+      deprecated: true
+      parameters:
+        - {name: pet_id, in: path, required: true, schema: {type: integer, format: int64}}
+      responses: {'204': {description: deleted}}
+  /pets/{pet_id}/photo:
+    put:
+      operationId: uploadPhoto
+      parameters:
+        - {name: pet_id, in: path, required: true, schema: {type: integer, format: int64}}
+      requestBody:
+        content:
+          image/png: {schema: {type: string, format: binary}}
+      responses:
+        '200':
+          description: the photo
+          content:
+            image/png: {schema: {type: string, format: binary}}
+  /files/{name}.json:
+    get:
+      parameters:
+        - {name: name, in: path, required: true, schema: {type: string}}
+      responses: {'200': {description: ok}}
+components:
+  schemas:
+    Pet:
+      type: object
+      description: A pet
+      required: [id, name]
+      properties:
+        id: {type: integer, format: int64}
+        name: {type: string}
+        born: {type: string, format: date-time}
+        status: {$ref: '#/components/schemas/Status'}
+        parent: {$ref: '#/components/schemas/Pet'}
+        owner: {$ref: '#/components/schemas/Owner'}
+        tags: {type: object, additionalProperties: {type: string}}
+        shape: {oneOf: [{type: string}, {type: integer}]}
+        location:
+          type: object
+          properties:
+            lat: {type: number}
+            lng: {type: number}
+    Owner:
+      type: object
+      required: [pet]
+      properties:
+        pet: {$ref: '#/components/schemas/Pet'}
+    Status:
+      type: string
+      enum: [available, sold]
+    Cat:
+      allOf:
+        - {$ref: '#/components/schemas/Pet'}
+        - type: object
+          properties:
+            indoor: {type: boolean}
+    Timestamp: {type: string, format: date-time}
+`
+
+// check type checks the generated files
+func check(t *testing.T, files map[string]string) {
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for name, src := range files {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("failed test %v\n%s", err, src)
+		}
+		parsed = append(parsed, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("petstore", fset, parsed, nil); err != nil {
+		for name, src := range files {
+			t.Logf("%s\n%s", name, src)
+		}
+		t.Fatalf("failed test %v", err)
+	}
+}
+
+// contains ignores the alignment of gofmt
+func contains(src string, part string) bool {
+	return strings.Contains(strings.Join(strings.Fields(src), " "), strings.Join(strings.Fields(part), " "))
+}
+
+func generate(t *testing.T, kind string) map[string]string {
+	doc, err := swagger.Parse(common.Yml, petstore)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	files, err := Go(doc, kind, "")
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	return files
+}
+
+func TestGoClient(t *testing.T) {
+	files := generate(t, KindClient)
+	if len(files) != 2 || files["petstore/types.go"] == "" || files["petstore/client.go"] == "" {
+		t.Fatalf("invalid files %v", files)
+	}
+	check(t, files)
+
+	for _, part := range []string{
+		"// Code generated by swagger-viewer from \"Pet Store 1.0.0\". DO NOT EDIT.\n",
+		"type Pet struct {\n",
+		"\tID int64 `json:\"id\"`\n",
+		"\tBorn *time.Time `json:\"born,omitempty\"`\n",
+		// cycles are broken by pointers
+		"\tPet *Pet `json:\"pet\"`\n",
+		"\tParent *Pet `json:\"parent,omitempty\"`\n",
+		"\tTags map[string]string `json:\"tags,omitempty\"`\n",
+		"\tShape json.RawMessage `json:\"shape,omitempty\"`\n",
+		"\tLocation *PetLocation `json:\"location,omitempty\"`\n",
+		"type Status string\n",
+		"\tStatusAvailable Status = \"available\"\n",
+		"type Cat struct {\n\tPet\n",
+		"type Timestamp = time.Time\n",
+		"type CreatePetRequest struct {\n",
+	} {
+		if !contains(files["petstore/types.go"], part) {
+			t.Errorf("%q is missing\n%s", part, files["petstore/types.go"])
+		}
+	}
+	for _, part := range []string{
+		"const DefaultBaseURL = \"https://api.example.com/v1\"\n",
+		"func (c *Client) ListPets(ctx context.Context, params ListPetsParams) ([]Pet, error) {\n",
+		"func (c *Client) CreatePet(ctx context.Context, body CreatePetRequest) (Pet, error) {\n",
+		"// Deprecated: the operation is deprecated.\nfunc (c *Client) DeletePetsPetID(ctx context.Context, petID int64) error {\n",
+		"func (c *Client) UploadPhoto(ctx context.Context, petID int64, body io.Reader) ([]byte, error) {\n",
+		"path := \"/pets/\" + url.PathEscape(formatParam(petID)) + \"/photo\"\n",
+		"header.Add(\"X-Request-Id\", formatParam(params.XRequestID))\n",
+	} {
+		if !contains(files["petstore/client.go"], part) {
+			t.Errorf("%q is missing\n%s", part, files["petstore/client.go"])
+		}
+	}
+}
+
+func TestGoServer(t *testing.T) {
+	files := generate(t, KindServer)
+	if len(files) != 2 || files["petstore/server.go"] == "" {
+		t.Fatalf("invalid files %v", files)
+	}
+	check(t, files)
+
+	for _, part := range []string{
+		"const BasePath = \"/v1\"\n",
+		"\tCreatePet(ctx context.Context, body CreatePetRequest) (Pet, error)\n",
+		"mux.HandleFunc(\"DELETE \"+BasePath+\"/pets/{petID}\", func(w http.ResponseWriter, r *http.Request) {\n",
+		"writeJSON(w, 201, \"application/json\", result)\n",
+		"// GET /files/{name}.json is not routed",
+	} {
+		if !contains(files["petstore/server.go"], part) {
+			t.Errorf("%q is missing\n%s", part, files["petstore/server.go"])
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	for name, expected := range map[string][2]string{
+		"pet_id":     {"PetID", "petID"},
+		"petId":      {"PetID", "petID"},
+		"HTTPServer": {"HTTPServer", "httpServer"},
+		"url":        {"URL", "url"},
+		"type":       {"Type", "type_"},
+		"2fa-code":   {"N2faCode", "n2faCode"},
+	} {
+		if exported, unexported := exportedName(name), unexportedName(name); exported != expected[0] || unexported != expected[1] {
+			t.Errorf("%s: %s %s", name, exported, unexported)
+		}
+	}
+	if name := packageName("Pet Store v2"); name != "petstorev2" {
+		t.Errorf("invalid package %s", name)
+	}
+}
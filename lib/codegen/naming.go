@@ -0,0 +1,143 @@
+package codegen
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case in Go names (golint)
+var initialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "JWT": true,
+	"OS": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UI": true,
+	"UID": true, "URI": true, "URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true, "goto": true,
+	"if": true, "import": true, "interface": true, "map": true, "package": true, "range": true,
+	"return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+}
+
+// words splits a name at non alphanumeric characters and at camel case boundaries
+// ("petId" and "pet_id" are both [pet id])
+func words(name string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(name)
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, string(current))
+			current = nil
+		}
+	}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if len(current) > 0 && unicode.IsUpper(r) {
+			prev := current[len(current)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return result
+}
+
+// exportedName converts a name to an exported Go identifier ("pet_id" -> "PetID")
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		upper := strings.ToUpper(w)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(w))
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	s := b.String()
+	if s == "" {
+		return ""
+	}
+	if unicode.IsDigit([]rune(s)[0]) {
+		s = "N" + s
+	}
+	return s
+}
+
+// unexportedName converts a name to an unexported Go identifier ("PetID" -> "petID")
+func unexportedName(name string) string {
+	exported := exportedName(name)
+	if exported == "" {
+		return ""
+	}
+	first := words(name)[0]
+	if initialisms[strings.ToUpper(first)] && strings.HasPrefix(exported, strings.ToUpper(first)) {
+		exported = strings.ToLower(first) + exported[len(first):]
+	} else {
+		runes := []rune(exported)
+		runes[0] = unicode.ToLower(runes[0])
+		exported = string(runes)
+	}
+	if goKeywords[exported] {
+		exported += "_"
+	}
+	return exported
+}
+
+// packageName converts a title to a package name ("Pet Store v2" -> "petstorev2")
+func packageName(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9' && b.Len() > 0) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 || goKeywords[b.String()] {
+		return "api"
+	}
+	return b.String()
+}
+
+// namer returns unique names
+type namer map[string]bool
+
+// unique returns name, or name with a number if it is already used
+func (this namer) unique(name string) string {
+	candidate := name
+	for i := 2; this[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	this[candidate] = true
+	return candidate
+}
+
+// ValidPackageName returns true if name can be the name of a generated package
+func ValidPackageName(name string) bool {
+	return token.IsIdentifier(name) && !token.IsKeyword(name) && name == strings.ToLower(name)
+}
+
+// typeScriptName converts a name to a TypeScript type name ("pet_id" -> "PetId")
+func typeScriptName(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	s := b.String()
+	if s != "" && unicode.IsDigit([]rune(s)[0]) {
+		s = "N" + s
+	}
+	return s
+}
@@ -0,0 +1,317 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+type tsGenerator struct {
+	doc swagger.Document
+	// type names of named schemas
+	schemas map[string]string
+}
+
+// TypeScript generates a declaration file (.d.ts) with a type per schema and a namespace per operation,
+// declaring its parameters, request body and responses. Names are derived from the schema names and
+// operationIds (method and path without operationId), so they are stable across versions.
+// oneOf and anyOf are unions, allOf is an intersection. Swagger 2.0 documents are converted to OpenAPI 3 first.
+func TypeScript(doc swagger.Document) string {
+	doc = swagger.ConvertToOAS3(doc)
+	g := &tsGenerator{doc: doc, schemas: map[string]string{}}
+	names := namer{}
+
+	schemas := doc.Schemas()
+	schemaNames := make([]string, 0, len(schemas))
+	for name := range schemas {
+		schemaNames = append(schemaNames, name)
+	}
+	sort.Strings(schemaNames)
+	for _, name := range schemaNames {
+		typeName := typeScriptName(name)
+		if typeName == "" {
+			typeName = "Schema"
+		}
+		g.schemas[name] = names.unique(typeName)
+	}
+
+	var b strings.Builder
+	title := strings.Join(strings.Fields(doc.Title()+" "+doc.Version()), " ")
+	fmt.Fprintf(&b, "// Generated by swagger-viewer from %q. Do not edit.\n", title)
+	for _, name := range schemaNames {
+		schema, _ := schemas[name].(map[string]interface{})
+		b.WriteString("\n")
+		g.jsDoc(&b, "", swagger.String(schema, "description"), swagger.Bool(schema, "deprecated"))
+		typ := g.typeOf(schema, "")
+		if strings.HasPrefix(typ, "{") && parenthesize(typ) == typ {
+			fmt.Fprintf(&b, "export interface %s %s\n", g.schemas[name], typ)
+		} else {
+			fmt.Fprintf(&b, "export type %s = %s;\n", g.schemas[name], typ)
+		}
+	}
+
+	for _, op := range doc.Operations() {
+		name := typeScriptName(op.OperationId)
+		if name == "" {
+			name = typeScriptName(op.Method + " " + pathParam.ReplaceAllString(op.Path, "$1"))
+		}
+		b.WriteString("\n")
+		text := strings.ToUpper(op.Method) + " " + op.Path
+		if op.Summary != "" {
+			text += "\n\n" + op.Summary
+		}
+		g.jsDoc(&b, "", text, op.Deprecated)
+		fmt.Fprintf(&b, "export namespace %s {\n", names.unique(name))
+		g.operation(&b, op)
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func (this *tsGenerator) operation(b *strings.Builder, op swagger.Operation) {
+	for _, location := range []struct{ in, name string }{
+		{"path", "PathParameters"}, {"query", "QueryParameters"}, {"header", "HeaderParameters"}, {"cookie", "CookieParameters"},
+	} {
+		var params []swagger.Parameter
+		for _, p := range op.Parameters {
+			if p.In == location.in {
+				params = append(params, p)
+			}
+		}
+		if len(params) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "  export interface %s {\n", location.name)
+		for _, p := range params {
+			this.jsDoc(b, "    ", swagger.String(p.Raw, "description"), swagger.Bool(p.Raw, "deprecated"))
+			fmt.Fprintf(b, "    %s%s: %s;\n", propertyName(p.Name), optional(p.Required), this.typeOf(swagger.Map(p.Raw, "schema"), "    "))
+		}
+		b.WriteString("  }\n")
+	}
+
+	if content := swagger.Map(op.RequestBody, "content"); len(content) > 0 {
+		fmt.Fprintf(b, "  export type RequestBody = %s;\n", this.contentType(content, "  "))
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	success := "void"
+	if len(codes) > 0 {
+		b.WriteString("  export interface Responses {\n")
+		for _, code := range codes {
+			response, _ := op.Responses[code].(map[string]interface{})
+			if ref := swagger.String(response, "$ref"); ref != "" {
+				response, _ = this.doc.Resolve(ref).(map[string]interface{})
+			}
+			typ := "void"
+			if content := swagger.Map(response, "content"); len(content) > 0 {
+				typ = this.contentType(content, "    ")
+			}
+			this.jsDoc(b, "    ", swagger.String(response, "description"), false)
+			fmt.Fprintf(b, "    %s: %s;\n", strconv.Quote(code), typ)
+			if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 && success == "void" {
+				success = fmt.Sprintf("Responses[%q]", code)
+			}
+		}
+		b.WriteString("  }\n")
+	}
+	// the body of the first successful response
+	fmt.Fprintf(b, "  export type Response = %s;\n", success)
+}
+
+// contentType returns the type of the json media type of content if any, or Blob
+func (this *tsGenerator) contentType(content map[string]interface{}, indent string) string {
+	mediaType, json := pickMediaType(content)
+	if !json && !strings.Contains(mediaType, "form") {
+		return "Blob"
+	}
+	return this.typeOf(swagger.Map(swagger.Map(content, mediaType), "schema"), indent)
+}
+
+// typeOf returns the type of a schema. Objects are object literals, written at indent.
+func (this *tsGenerator) typeOf(schema map[string]interface{}, indent string) string {
+	typ := this.baseType(schema, indent)
+	if swagger.Bool(schema, "nullable") {
+		typ += " | null"
+	}
+	return typ
+}
+
+func (this *tsGenerator) baseType(schema map[string]interface{}, indent string) string {
+	if schema == nil {
+		return "unknown"
+	}
+	if ref := swagger.String(schema, "$ref"); ref != "" {
+		if name, ok := this.schemas[strings.TrimPrefix(ref, schemaPrefix)]; ok && strings.HasPrefix(ref, schemaPrefix) {
+			return name
+		}
+		resolved, _ := this.doc.Resolve(ref).(map[string]interface{})
+		if swagger.String(resolved, "$ref") == ref {
+			return "unknown"
+		}
+		return this.typeOf(resolved, indent)
+	}
+	for _, composition := range []struct{ key, operator string }{{"oneOf", " | "}, {"anyOf", " | "}, {"allOf", " & "}} {
+		members := swagger.Slice(schema, composition.key)
+		if len(members) == 0 {
+			continue
+		}
+		types := []string{}
+		for _, m := range members {
+			member, _ := m.(map[string]interface{})
+			types = append(types, parenthesize(this.typeOf(member, indent)))
+		}
+		// properties next to allOf are a member of the intersection
+		if composition.key == "allOf" && swagger.Map(schema, "properties") != nil {
+			types = append(types, this.object(schema, indent))
+		}
+		return strings.Join(types, composition.operator)
+	}
+
+	if enum := swagger.Slice(schema, "enum"); len(enum) > 0 {
+		values := []string{}
+		for _, value := range enum {
+			literal, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			values = append(values, string(literal))
+		}
+		return strings.Join(values, " | ")
+	}
+	switch swagger.String(schema, "type") {
+	case "string":
+		if swagger.String(schema, "format") == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return parenthesize(this.typeOf(swagger.Map(schema, "items"), indent)) + "[]"
+	case "object":
+		return this.object(schema, indent)
+	}
+	if swagger.Map(schema, "properties") != nil || schema["additionalProperties"] != nil {
+		return this.object(schema, indent)
+	}
+	return "unknown"
+}
+
+// object returns an object literal type
+func (this *tsGenerator) object(schema map[string]interface{}, indent string) string {
+	properties := swagger.Map(schema, "properties")
+	required := stringSet(swagger.Strings(schema, "required"))
+	additional := "unknown"
+	switch value := schema["additionalProperties"].(type) {
+	case map[string]interface{}:
+		additional = this.typeOf(value, indent+"  ")
+	case bool:
+		if !value {
+			additional = ""
+		}
+	case nil:
+		if len(properties) > 0 {
+			additional = ""
+		}
+	}
+	if len(properties) == 0 && additional != "" {
+		return "{ [key: string]: " + additional + " }"
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range names {
+		property, _ := properties[name].(map[string]interface{})
+		this.jsDoc(&b, indent+"  ", swagger.String(property, "description"), swagger.Bool(property, "deprecated"))
+		readonly := ""
+		if swagger.Bool(property, "readOnly") {
+			readonly = "readonly "
+		}
+		fmt.Fprintf(&b, "%s  %s%s%s: %s;\n", indent, readonly, propertyName(name), optional(required[name]), this.typeOf(property, indent+"  "))
+	}
+	if additional != "" {
+		// the index signature must accept the types of the properties
+		fmt.Fprintf(&b, "%s  [key: string]: unknown;\n", indent)
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// jsDoc writes a documentation comment
+func (this *tsGenerator) jsDoc(b *strings.Builder, indent string, text string, deprecated bool) {
+	text = strings.TrimSpace(strings.Replace(text, "*/", "*\\/", -1))
+	if deprecated {
+		text = strings.TrimSpace(text + "\n\n@deprecated")
+	}
+	if text == "" {
+		return
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s", strings.TrimRight(indent+" * "+line, " \t\r")+"\n")
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+func propertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func optional(required bool) string {
+	if required {
+		return ""
+	}
+	return "?"
+}
+
+// parenthesize wraps unions and intersections, so that they can be members or items
+func parenthesize(typ string) string {
+	depth := 0
+	for i, r := range typ {
+		switch r {
+		case '{', '(', '[':
+			depth++
+		case '}', ')', ']':
+			depth--
+		case '|', '&':
+			if depth == 0 && i > 0 && typ[i-1] == ' ' {
+				return "(" + typ + ")"
+			}
+		}
+	}
+	return typ
+}
+
+func stringSet(strs []string) map[string]bool {
+	set := map[string]bool{}
+	for _, s := range strs {
+		set[s] = true
+	}
+	return set
+}
@@ -0,0 +1,54 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+func TestTypeScript(t *testing.T) {
+	doc, err := swagger.Parse(common.Yml, petstore)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	ts := TypeScript(doc)
+	for _, part := range []string{
+		"/** A pet */\nexport interface Pet {\n",
+		"  location?: {\n    lat?: number;\n    lng?: number;\n  };\n",
+		"  shape?: string | number;\n",
+		"  tags?: { [key: string]: string };\n",
+		"export type Cat = Pet & {\n  indoor?: boolean;\n};\n",
+		"export type Status = \"available\" | \"sold\";\n",
+		"export namespace ListPets {\n  export interface QueryParameters {\n    limit?: number;\n    tags?: string[];\n  }\n",
+		"    \"X-Request-Id\": string;\n",
+		"  export type RequestBody = {\n    name: string;\n    status?: Status;\n  };\n",
+		"    \"201\": Pet;\n  }\n  export type Response = Responses[\"201\"];\n",
+		" * @deprecated\n */\nexport namespace DeletePetsPetId {\n",
+		"  export type RequestBody = Blob;\n",
+	} {
+		if !strings.Contains(ts, part) {
+			t.Errorf("%q is missing\n%s", part, ts)
+		}
+	}
+
+	// names are stable, whatever else the document contains
+	doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})["pet"] = map[string]interface{}{"type": "object"}
+	if !strings.Contains(TypeScript(doc), "export interface Pet {\n") {
+		t.Errorf("names must not depend on other schemas")
+	}
+}
+
+func TestParenthesize(t *testing.T) {
+	for typ, expected := range map[string]string{
+		"string":                    "string",
+		"string | number":           "(string | number)",
+		"{\n  a: string | null;\n}": "{\n  a: string | null;\n}",
+		"{\n  a: string;\n} | null": "({\n  a: string;\n} | null)",
+	} {
+		if actual := parenthesize(typ); actual != expected {
+			t.Errorf("%q: %q", typ, actual)
+		}
+	}
+}
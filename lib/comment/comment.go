@@ -0,0 +1,137 @@
+// Package comment implements the threaded comments on the operations and schemas of versions.
+// A thread is carried forward to the later versions of the service as long as its target still exists.
+package comment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	commentdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/comment"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/semver"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// MaxBodyLength is the maximum length of the body of a comment
+const MaxBodyLength = 10000
+
+// CodeInvalid is the error code of an invalid comment
+const CodeInvalid = 28001
+
+// Thread is the first comment of a thread with its replies in posted order.
+// Carried is true if the thread was posted on an earlier version than the listed one.
+type Thread struct {
+	commentdb.CommentEntity
+	Replies []commentdb.CommentEntity `json:"replies"`
+	Carried bool                      `json:"carried"`
+}
+
+// OperationPointer returns the JSON pointer of an operation ("/paths/~1pets/get")
+func OperationPointer(path string, method string) string {
+	return "/paths/" + swagger.EscapePointerToken(path) + "/" + strings.ToLower(method)
+}
+
+// SchemaPointer returns the JSON pointer of a schema of doc
+// ("/components/schemas/Pet" or "/definitions/Pet")
+func SchemaPointer(doc swagger.Document, name string) string {
+	return strings.TrimPrefix(doc.SchemaRefPrefix(), "#") + swagger.EscapePointerToken(name)
+}
+
+// ValidatePointer returns an error if pointer does not target an operation or a schema of doc
+func ValidatePointer(doc swagger.Document, pointer string) error {
+	if !target(doc, pointer) {
+		return common.NewError(CodeInvalid, "pointer must target an operation (/paths/{path}/{method}) or a schema ("+strings.TrimPrefix(doc.SchemaRefPrefix(), "#")+"{name})", nil)
+	}
+	if !Exists(doc, pointer) {
+		return common.NewError(CodeInvalid, pointer+" does not exist in the version", nil)
+	}
+	return nil
+}
+
+// ValidateBody returns an error if the body of a comment is empty or too long
+func ValidateBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return common.NewError(CodeInvalid, "body must not be empty", nil)
+	}
+	if len(body) > MaxBodyLength {
+		return common.NewError(CodeInvalid, fmt.Sprintf("body must not be longer than %d bytes", MaxBodyLength), nil)
+	}
+	return nil
+}
+
+// Exists returns true if pointer resolves in doc
+func Exists(doc swagger.Document, pointer string) bool {
+	_, ok := swagger.Pointer(map[string]interface{}(doc), pointer)
+	return ok
+}
+
+func target(doc swagger.Document, pointer string) bool {
+	tokens := strings.Split(pointer, "/")
+	if len(tokens) == 4 && tokens[0] == "" && tokens[1] == "paths" && tokens[2] != "" {
+		for _, m := range swagger.Methods {
+			if tokens[3] == m {
+				return true
+			}
+		}
+		return false
+	}
+	prefix := strings.TrimPrefix(doc.SchemaRefPrefix(), "#")
+	name := strings.TrimPrefix(pointer, prefix)
+	return strings.HasPrefix(pointer, prefix) && name != "" && !strings.Contains(name, "/")
+}
+
+// Threads groups comments into threads sorted by posted time.
+// The replies of a missing thread are dropped.
+func Threads(comments []commentdb.CommentEntity) []Thread {
+	sorted := append([]commentdb.CommentEntity{}, comments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Createdat != sorted[j].Createdat {
+			return sorted[i].Createdat < sorted[j].Createdat
+		}
+		return sorted[i].Id < sorted[j].Id
+	})
+
+	threads := []Thread{}
+	index := map[string]int{}
+	for _, c := range sorted {
+		if c.Parentid == "" {
+			index[c.Id] = len(threads)
+			threads = append(threads, Thread{CommentEntity: c, Replies: []commentdb.CommentEntity{}})
+		}
+	}
+	for _, c := range sorted {
+		if i, ok := index[c.Parentid]; ok && c.Parentid != "" {
+			threads[i].Replies = append(threads[i].Replies, c)
+		}
+	}
+	return threads
+}
+
+// ForVersion returns the threads shown on version: the threads posted on it and the threads
+// posted on earlier versions whose target exists in doc, the document of version
+func ForVersion(threads []Thread, version string, doc swagger.Document) []Thread {
+	shown := []Thread{}
+	for _, t := range threads {
+		switch {
+		case t.Version == version:
+			t.Carried = false
+			shown = append(shown, t)
+		case semver.Compare(t.Version, version) < 0 && Exists(doc, t.Pointer):
+			t.Carried = true
+			shown = append(shown, t)
+		}
+	}
+	return shown
+}
+
+// Filter returns the threads on pointer ("" for all) which are resolved or not (nil for all)
+func Filter(threads []Thread, pointer string, resolved *bool) []Thread {
+	filtered := []Thread{}
+	for _, t := range threads {
+		if (pointer == "" || t.Pointer == pointer) && (resolved == nil || t.Resolved == *resolved) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
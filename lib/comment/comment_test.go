@@ -0,0 +1,115 @@
+package comment
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	commentdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/comment"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+const v1 = `{"openapi": "3.0.0", "info": {"title": "pets", "version": "1.0.0"},
+  "paths": {"/pets": {"get": {}, "post": {}}, "/pets/{id}": {"get": {}}},
+  "components": {"schemas": {"Pet": {"type": "object"}, "Error": {"type": "object"}}}}`
+
+const v2 = `{"openapi": "3.0.0", "info": {"title": "pets", "version": "2.0.0"},
+  "paths": {"/pets": {"get": {}}},
+  "components": {"schemas": {"Pet": {"type": "object"}}}}`
+
+func parse(t *testing.T, contents string) swagger.Document {
+	doc, err := swagger.Parse(common.Json, contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestValidatePointer(t *testing.T) {
+	doc := parse(t, v1)
+	for _, pointer := range []string{
+		OperationPointer("/pets", "GET"),
+		OperationPointer("/pets/{id}", "get"),
+		SchemaPointer(doc, "Pet"),
+	} {
+		if err := ValidatePointer(doc, pointer); err != nil {
+			t.Errorf("%s must be valid %#v", pointer, err)
+		}
+	}
+	for _, pointer := range []string{
+		"",
+		"/paths/~1pets",
+		"/paths/~1pets/get/responses",
+		"/paths/~1pets/delete",
+		"/paths/~1owners/get",
+		"/info",
+		"/definitions/Pet",
+		"/components/schemas/Pet/properties",
+		"/components/schemas/Owner",
+	} {
+		if err := ValidatePointer(doc, pointer); err == nil || err.(*common.Error).Code != CodeInvalid {
+			t.Errorf("%s must be invalid", pointer)
+		}
+	}
+	if pointer := SchemaPointer(parse(t, `{"swagger": "2.0", "definitions": {"Pet": {}}}`), "Pet"); pointer != "/definitions/Pet" {
+		t.Errorf("invalid swagger 2.0 pointer %s", pointer)
+	}
+}
+
+func TestValidateBody(t *testing.T) {
+	if err := ValidateBody("looks good"); err != nil {
+		t.Errorf("failed test %#v", err)
+	}
+	if err := ValidateBody(" \n"); err == nil {
+		t.Errorf("an empty body must be invalid")
+	}
+	if err := ValidateBody(string(make([]byte, MaxBodyLength+1))); err == nil {
+		t.Errorf("a long body must be invalid")
+	}
+}
+
+func TestThreads(t *testing.T) {
+	comments := []commentdb.CommentEntity{
+		{Id: "r2", Parentid: "a", Createdat: 4},
+		{Id: "b", Version: "1.0.0", Pointer: "/paths/~1pets/post", Createdat: 2},
+		{Id: "r1", Parentid: "a", Createdat: 3},
+		{Id: "a", Version: "1.0.0", Pointer: "/paths/~1pets/get", Createdat: 1},
+		{Id: "c", Version: "2.0.0", Pointer: "/components/schemas/Pet", Createdat: 5, Resolved: true},
+		{Id: "d", Version: "1.1.0", Pointer: "/components/schemas/Error", Createdat: 6},
+		{Id: "e", Version: "3.0.0", Pointer: "/paths/~1pets/get", Createdat: 7},
+		{Id: "orphan", Parentid: "missing", Createdat: 8},
+	}
+	threads := Threads(comments)
+	if len(threads) != 5 || threads[0].Id != "a" || threads[1].Id != "b" {
+		t.Fatalf("invalid threads %+v", threads)
+	}
+	if len(threads[0].Replies) != 2 || threads[0].Replies[0].Id != "r1" || threads[0].Replies[1].Id != "r2" {
+		t.Errorf("invalid replies %+v", threads[0].Replies)
+	}
+
+	// a and b are carried while their operations exist, d is gone with the Error schema and e is later
+	shown := ForVersion(threads, "2.0.0", parse(t, v2))
+	ids := []string{}
+	for _, thread := range shown {
+		ids = append(ids, thread.Id)
+	}
+	if len(shown) != 2 || shown[0].Id != "a" || !shown[0].Carried || shown[1].Id != "c" || shown[1].Carried {
+		t.Errorf("invalid threads of 2.0.0 %v", ids)
+	}
+
+	resolved := true
+	if filtered := Filter(shown, "", &resolved); len(filtered) != 1 || filtered[0].Id != "c" {
+		t.Errorf("invalid resolved threads %+v", filtered)
+	}
+	if filtered := Filter(threads, "/paths/~1pets/get", nil); len(filtered) != 2 {
+		t.Errorf("invalid threads of the pointer %+v", filtered)
+	}
+
+	// the first comment is flattened into the thread
+	data, _ := json.Marshal(shown[0])
+	var body map[string]interface{}
+	json.Unmarshal(data, &body)
+	if body["id"] != "a" || body["carried"] != true || len(body["replies"].([]interface{})) != 2 {
+		t.Errorf("invalid json %s", data)
+	}
+}
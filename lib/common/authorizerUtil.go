@@ -3,8 +3,15 @@ package common
 import "gopkg.in/yaml.v2"
 
 type AuthorizerConfig struct {
-	WhitelistIP []string `json:"whitelist_ip" yaml:"whitelist_ip" validate:"required"`
-	BlacklistIP []string `json:"blacklist_ip" yaml:"blacklist_ip" validate:"required"`
+	WhitelistIP []string                    `json:"whitelist_ip" yaml:"whitelist_ip" validate:"required"`
+	BlacklistIP []string                    `json:"blacklist_ip" yaml:"blacklist_ip" validate:"required"`
+	Callers     map[string]AuthorizerCaller `json:"callers" yaml:"callers"` // keyed by the sha256 hex digest of the api key
+}
+
+// AuthorizerCaller is a caller allowed by the authorizer with the namespaces granted to it. see lib/tenant
+type AuthorizerCaller struct {
+	Principal  string   `json:"principal" yaml:"principal"`
+	Namespaces []string `json:"namespaces" yaml:"namespaces"`
 }
 
 func ParseAuthorizerConfig(config string) (AuthorizerConfig, error) {
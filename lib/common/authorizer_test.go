@@ -13,3 +13,21 @@ whitelist_ip:
 	conf, err := ParseAuthorizerConfig(configyaml)
 	fmt.Println(conf, err)
 }
+
+func TestAuthorizerCallers(t *testing.T) {
+	configyaml := `
+callers:
+  9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08:
+    principal: alice
+    namespaces:
+      - pets/store
+`
+	conf, err := ParseAuthorizerConfig(configyaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caller := conf.Callers["9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"]
+	if caller.Principal != "alice" || len(caller.Namespaces) != 1 || caller.Namespaces[0] != "pets/store" {
+		t.Errorf("invalid callers %+v", conf.Callers)
+	}
+}
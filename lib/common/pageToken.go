@@ -0,0 +1,32 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// EncodePageToken encodes the last evaluated key of a page into an opaque url-safe token.
+// It returns "" for the last page.
+func EncodePageToken(key map[string]string) string {
+	if len(key) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(key)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePageToken decodes a token returned by EncodePageToken. It returns nil for "".
+func DecodePageToken(token string) (map[string]string, error) {
+	if token == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, NewError(303, "invalid page token", err)
+	}
+	var key map[string]string
+	if err := json.Unmarshal(b, &key); err != nil {
+		return nil, NewError(303, "invalid page token", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,323 @@
+package auditdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// Actions recorded in the audit table
+const (
+	ActionCreateService      = "CreateService"
+	ActionUpdateService      = "UpdateService"
+	ActionDeleteService      = "DeleteService"
+	ActionUploadVersion      = "UploadVersion"
+	ActionUpdateVersion      = "UpdateVersion"
+	ActionDeleteVersion      = "DeleteVersion"
+	ActionUpdateLintRules    = "UpdateLintRules"
+	ActionUpdateDependencies = "UpdateDependencies"
+	ActionUpdateReviewPolicy = "UpdateReviewPolicy"
+	ActionReviewVersion      = "ReviewVersion"
+	ActionCreateComment      = "CreateComment"
+	ActionResolveComment     = "ResolveComment"
+)
+
+// LogAudit is the Log of every audit record
+const LogAudit = "audit"
+
+// timeIndex is the global secondary index of the records of all services sorted by time.
+// Every record has the same Log, so that they are queried in order without scanning the table.
+const timeIndex = "time-index"
+
+// AuditEntity provides Audit DB Record Contents
+// Eventid is the range key: zero-padded unix millis + "#" + request id,
+// so that records of a service are sorted by time.
+type AuditEntity struct {
+	Serviceid string        `json:"serviceid"`
+	Eventid   string        `json:"eventid"`
+	Log       string        `json:"log"` // LogAudit. the hash key of the time index
+	Timestamp int64         `json:"timestamp"`
+	Principal string        `json:"principal"`
+	Sourceip  string        `json:"sourceip"`
+	Action    string        `json:"action"`
+	Requestid string        `json:"requestid"`
+	Changes   []ChangeEntry `json:"changes"`
+}
+
+// ChangeEntry is a field level before/after diff.
+// Before is nil for added fields and After is nil for removed fields.
+type ChangeEntry struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditRepositoryDao provides an interface of Dao for audit db.
+// The table is append-only: records are never updated or deleted.
+type AuditRepositoryDao interface {
+	PutAudit(audit AuditEntity) error
+	GetAudits(serviceId string, since int64) ([]AuditEntity, error)
+	GetAllAudits(since int64) ([]AuditEntity, error)
+}
+
+type auditRepositoryDaoImpl struct {
+	tableName    string
+	dynamoClient *dynamodb.DynamoDB
+}
+
+// NewDaoDefaultConfig return DynamoDB Session
+func NewDaoDefaultConfig(tableName string) (AuditRepositoryDao, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	cfg.DisableEndpointHostPrefix = true
+
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+
+	return &auditRepositoryDaoImpl{
+		dynamoClient: dynamodb.New(cfg),
+		tableName:    tableName,
+	}, nil
+}
+
+// NewDaoWithRegionAndEndpoint return DynamoDB Session
+// If you are using dynamodb local, use it.
+// example: dao, err := NewDaoWithRegionAndEndpoint("tablename", "ap-northeast-1", "http://localhost:8000")
+func NewDaoWithRegionAndEndpoint(tableName string, region string, endpoint string) (AuditRepositoryDao, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	cfg.EndpointResolver = aws.ResolveWithEndpointURL(endpoint)
+	cfg.Region = region
+	cfg.DisableEndpointHostPrefix = true
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+
+	return &auditRepositoryDaoImpl{
+		dynamoClient: dynamodb.New(cfg),
+		tableName:    tableName,
+	}, nil
+}
+
+// NewAuditEntity creates an audit record of a mutation from the api gateway request.
+// before/after are the entities before and after the mutation (nil if absent).
+func NewAuditEntity(request events.APIGatewayProxyRequest, serviceId string, action string, before interface{}, after interface{}) (AuditEntity, error) {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return AuditEntity{}, err
+	}
+	now := time.Now().Unix() * 1000
+	requestId := request.RequestContext.RequestID
+	return AuditEntity{
+		Serviceid: serviceId,
+		Eventid:   EventId(now, requestId),
+		Log:       LogAudit,
+		Timestamp: now,
+		Principal: Principal(request),
+		Sourceip:  request.RequestContext.Identity.SourceIP,
+		Action:    action,
+		Requestid: requestId,
+		Changes:   changes,
+	}, nil
+}
+
+// Record appends the audit record of a mutation which is already done. The mutation is not failed
+// by the record: a record which fails to be appended is printed, so that it can be recovered from the logs.
+func Record(dao AuditRepositoryDao, request events.APIGatewayProxyRequest, serviceId string, action string, before interface{}, after interface{}) {
+	audit, err := NewAuditEntity(request, serviceId, action, before, after)
+	if err == nil {
+		err = dao.PutAudit(audit)
+	}
+	if err != nil {
+		fmt.Println(err)
+		record, _ := json.Marshal(audit)
+		fmt.Printf("audit record failed: %s\n", record)
+	}
+}
+
+// EventId returns the range key of an audit record
+func EventId(timestamp int64, requestId string) string {
+	return fmt.Sprintf("%013d#%s", timestamp, requestId)
+}
+
+// Principal returns the caller of the request.
+// The authorizer principal is preferred, then the IAM identity.
+func Principal(request events.APIGatewayProxyRequest) string {
+	if principal, ok := request.RequestContext.Authorizer["principalId"].(string); ok && principal != "" {
+		return principal
+	}
+	if request.RequestContext.Identity.UserArn != "" {
+		return request.RequestContext.Identity.UserArn
+	}
+	if request.RequestContext.Identity.User != "" {
+		return request.RequestContext.Identity.User
+	}
+	return "anonymous"
+}
+
+// Diff compares json representations of before and after, and returns changed fields sorted by name.
+func Diff(before interface{}, after interface{}) ([]ChangeEntry, error) {
+	beforeMap, err := toMap(before)
+	if err != nil {
+		return nil, common.NewError(101, "marshal error", err)
+	}
+	afterMap, err := toMap(after)
+	if err != nil {
+		return nil, common.NewError(101, "marshal error", err)
+	}
+
+	fields := map[string]bool{}
+	for k := range beforeMap {
+		fields[k] = true
+	}
+	for k := range afterMap {
+		fields[k] = true
+	}
+	var names []string
+	for k := range fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	changes := []ChangeEntry{}
+	for _, name := range names {
+		b, a := beforeMap[name], afterMap[name]
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+		changes = append(changes, ChangeEntry{Field: name, Before: b, After: a})
+	}
+	return changes, nil
+}
+
+func toMap(entity interface{}) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	if entity == nil || (reflect.ValueOf(entity).Kind() == reflect.Ptr && reflect.ValueOf(entity).IsNil()) {
+		return m, nil
+	}
+	bytes, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bytes, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PutAudit appends an audit record
+func (this *auditRepositoryDaoImpl) PutAudit(audit AuditEntity) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	item, err := dynamodbattribute.MarshalMap(audit)
+	if err != nil {
+		return common.NewError(301, "dynamoDB marhsallist error", err)
+	}
+	_, err = this.dynamoClient.PutItemRequest(&dynamodb.PutItemInput{
+		TableName:           aws.String(this.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(#eventid)"),
+		ExpressionAttributeNames: map[string]string{
+			"#eventid": "eventid",
+		},
+	}).Send()
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case dynamodb.ErrCodeConditionalCheckFailedException:
+				return common.NewError(1000, "audit event already exists", aerr)
+			default:
+				return common.NewError(300, "dynamodb put error", aerr)
+			}
+		}
+		return common.NewError(0, "unknown error", err)
+	}
+	return nil
+}
+
+// GetAudits gets audit records of a service recorded at or after since(unix millis)
+func (this *auditRepositoryDaoImpl) GetAudits(serviceId string, since int64) ([]AuditEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+	keyCond := expression.Key("serviceid").Equal(expression.Value(serviceId)).
+		And(expression.Key("eventid").GreaterThanEqual(expression.Value(fmt.Sprintf("%013d", since))))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, common.NewError(302, "expression build error", err)
+	}
+
+	req := this.dynamoClient.QueryRequest(&dynamodb.QueryInput{
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(this.tableName),
+	})
+	p := req.Paginate()
+
+	var items []map[string]dynamodb.AttributeValue
+	for p.Next() {
+		page := p.CurrentPage()
+		items = append(items, page.Items...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, common.NewError(300, "dynamodb query paginate error", err)
+	}
+
+	audits := []AuditEntity{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &audits); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return audits, nil
+}
+
+// GetAllAudits gets audit records of all services recorded at or after since(unix millis).
+// The records written before the time index existed have no Log and are not listed.
+func (this *auditRepositoryDaoImpl) GetAllAudits(since int64) ([]AuditEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+	keyCond := expression.Key("log").Equal(expression.Value(LogAudit)).
+		And(expression.Key("eventid").GreaterThanEqual(expression.Value(fmt.Sprintf("%013d", since))))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, common.NewError(302, "expression build error", err)
+	}
+
+	req := this.dynamoClient.QueryRequest(&dynamodb.QueryInput{
+		IndexName:                 aws.String(timeIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(this.tableName),
+	})
+	p := req.Paginate()
+
+	var items []map[string]dynamodb.AttributeValue
+	for p.Next() {
+		page := p.CurrentPage()
+		items = append(items, page.Items...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, common.NewError(300, "dynamodb query paginate error", err)
+	}
+
+	audits := []AuditEntity{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &audits); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return audits, nil
+}
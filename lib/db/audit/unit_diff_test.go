@@ -0,0 +1,90 @@
+package auditdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/go-cmp/cmp"
+)
+
+type entity struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+func TestDiffUpdate(t *testing.T) {
+	changes, err := Diff(&entity{Id: "a", Name: "old", Tag: "dev"}, entity{Id: "a", Name: "new"})
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	expected := []ChangeEntry{
+		{Field: "name", Before: "old", After: "new"},
+		{Field: "tag", Before: "dev", After: nil},
+	}
+	if diff := cmp.Diff(expected, changes); diff != "" {
+		t.Fatalf("diff %s", diff)
+	}
+}
+
+func TestDiffCreate(t *testing.T) {
+	var before *entity
+	changes, err := Diff(before, entity{Id: "a", Name: "new"})
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if len(changes) != 2 || changes[0].Before != nil || changes[0].After != "a" {
+		t.Fatalf("invalid changes %v", changes)
+	}
+}
+
+func TestNewAuditEntity(t *testing.T) {
+	request := events.APIGatewayProxyRequest{}
+	request.RequestContext.RequestID = "req-1"
+	request.RequestContext.Identity.SourceIP = "192.168.0.1"
+	request.RequestContext.Authorizer = map[string]interface{}{"principalId": "user"}
+
+	audit, err := NewAuditEntity(request, "service-1", ActionDeleteService, entity{Id: "a"}, nil)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if audit.Principal != "user" || audit.Sourceip != "192.168.0.1" || audit.Requestid != "req-1" {
+		t.Fatalf("invalid audit %v", audit)
+	}
+	if audit.Eventid != EventId(audit.Timestamp, "req-1") || len(audit.Eventid) != 13+1+5 {
+		t.Fatalf("invalid eventid %v", audit.Eventid)
+	}
+}
+
+func TestPrincipalAnonymous(t *testing.T) {
+	if p := Principal(events.APIGatewayProxyRequest{}); p != "anonymous" {
+		t.Fatalf("invalid principal %v", p)
+	}
+}
+
+type auditRepositoryDaoMock struct {
+	AuditRepositoryDao
+	audits []AuditEntity
+	err    error
+}
+
+func (this *auditRepositoryDaoMock) PutAudit(audit AuditEntity) error {
+	this.audits = append(this.audits, audit)
+	return this.err
+}
+
+func TestRecord(t *testing.T) {
+	dao := &auditRepositoryDaoMock{}
+	Record(dao, events.APIGatewayProxyRequest{}, "service-1", ActionCreateService, nil, entity{Id: "a"})
+	if len(dao.audits) != 1 || dao.audits[0].Action != ActionCreateService || dao.audits[0].Log != LogAudit {
+		t.Fatalf("invalid audits %v", dao.audits)
+	}
+
+	// a failed record does not fail the mutation
+	dao.err = errors.New("throttled")
+	Record(dao, events.APIGatewayProxyRequest{}, "service-1", ActionDeleteService, entity{Id: "a"}, nil)
+	if len(dao.audits) != 2 {
+		t.Fatalf("invalid audits %v", dao.audits)
+	}
+}
@@ -0,0 +1,225 @@
+package commentdb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// CommentEntity provides Comment DB Record Contents (one record per comment)
+// Version is the version the comment was posted on and Pointer is the JSON pointer of the
+// commented operation or schema. Parentid is the id of the thread of a reply, "" for the first
+// comment of a thread. Only the first comment of a thread is resolved.
+type CommentEntity struct {
+	Serviceid  string `json:"serviceid"`
+	Id         string `json:"id"`
+	Version    string `json:"version"`
+	Pointer    string `json:"pointer"`
+	Parentid   string `json:"parentid"`
+	Author     string `json:"author"`
+	Body       string `json:"body"`
+	Resolved   bool   `json:"resolved"`
+	Resolvedby string `json:"resolvedby"`
+	Resolvedat int64  `json:"resolvedat"`
+	Createdat  int64  `json:"createdat"`
+}
+
+// CommentRepositoryDao provides an interface of Dao for comments
+type CommentRepositoryDao interface {
+	GetComments(serviceId string) ([]CommentEntity, error)
+	GetComment(serviceId string, commentId string) (*CommentEntity, error)
+	CreateComment(comment CommentEntity) error
+	ResolveComment(serviceId string, commentId string, resolved bool, principal string, timestamp int64) (*CommentEntity, error)
+}
+
+type commentRepositoryDaoImpl struct {
+	tableName    string
+	dynamoClient *dynamodb.DynamoDB
+}
+
+// NewDaoDefaultConfig return DynamoDB Session
+func NewDaoDefaultConfig(tableName string) (CommentRepositoryDao, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	cfg.DisableEndpointHostPrefix = true
+
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+
+	return &commentRepositoryDaoImpl{
+		dynamoClient: dynamodb.New(cfg),
+		tableName:    tableName,
+	}, nil
+}
+
+// NewDaoWithRegionAndEndpoint return DynamoDB Session
+// If you are using dynamodb local, use it.
+// example: dao, err := NewDaoWithRegionAndEndpoint("tablename", "ap-northeast-1", "http://localhost:8000")
+func NewDaoWithRegionAndEndpoint(tableName string, region string, endpoint string) (CommentRepositoryDao, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	cfg.EndpointResolver = aws.ResolveWithEndpointURL(endpoint)
+	cfg.Region = region
+	cfg.DisableEndpointHostPrefix = true
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+
+	return &commentRepositoryDaoImpl{
+		dynamoClient: dynamodb.New(cfg),
+		tableName:    tableName,
+	}, nil
+}
+
+// GetComments gets all comments of a service
+func (this *commentRepositoryDaoImpl) GetComments(serviceId string) ([]CommentEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	expr, err := expression.NewBuilder().WithKeyCondition(expression.Key("serviceid").Equal(expression.Value(serviceId))).Build()
+	if err != nil {
+		return nil, common.NewError(302, "expression build error", err)
+	}
+	req := this.dynamoClient.QueryRequest(&dynamodb.QueryInput{
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(this.tableName),
+	})
+	p := req.Paginate()
+
+	var items []map[string]dynamodb.AttributeValue
+	for p.Next() {
+		items = append(items, p.CurrentPage().Items...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, common.NewError(300, "dynamodb query paginate error", err)
+	}
+
+	comments := []CommentEntity{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &comments); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return comments, nil
+}
+
+// GetComment gets a comment. It returns nil if the comment does not exist.
+func (this *commentRepositoryDaoImpl) GetComment(serviceId string, commentId string) (*CommentEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	result, err := this.dynamoClient.GetItemRequest(&dynamodb.GetItemInput{
+		TableName: aws.String(this.tableName),
+		Key:       key(serviceId, commentId),
+	}).Send()
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			return nil, common.NewError(300, "dynamodb get error", aerr)
+		}
+		return nil, common.NewError(0, "unknown error", err)
+	}
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	comment := CommentEntity{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &comment); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return &comment, nil
+}
+
+// CreateComment creates a comment
+func (this *commentRepositoryDaoImpl) CreateComment(comment CommentEntity) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	item, err := dynamodbattribute.MarshalMap(comment)
+	if err != nil {
+		return common.NewError(301, "dynamoDB marhsallist error", err)
+	}
+	if _, err := this.dynamoClient.PutItemRequest(&dynamodb.PutItemInput{
+		TableName:           aws.String(this.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(#id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
+		},
+	}).Send(); err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case dynamodb.ErrCodeConditionalCheckFailedException:
+				return common.NewError(1000, "id already exists", aerr)
+			default:
+				return common.NewError(300, "dynamodb put error", aerr)
+			}
+		}
+		return common.NewError(0, "unknown error", err)
+	}
+	return nil
+}
+
+// ResolveComment resolves or unresolves a comment and returns the updated comment.
+// It returns error code 1001 if the comment does not exist.
+func (this *commentRepositoryDaoImpl) ResolveComment(serviceId string, commentId string, resolved bool, principal string, timestamp int64) (*CommentEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	if !resolved {
+		principal = ""
+		timestamp = 0
+	}
+	update := expression.Set(expression.Name("resolved"), expression.Value(resolved)).
+		Set(expression.Name("resolvedby"), expression.Value(principal)).
+		Set(expression.Name("resolvedat"), expression.Value(timestamp))
+	condition := expression.AttributeExists(expression.Name("id"))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return nil, common.NewError(302, "expression build error", err)
+	}
+
+	result, err := this.dynamoClient.UpdateItemRequest(&dynamodb.UpdateItemInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(this.tableName),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		Key:                       key(serviceId, commentId),
+		ReturnValues:              dynamodb.ReturnValueAllNew,
+	}).Send()
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case dynamodb.ErrCodeConditionalCheckFailedException:
+				return nil, common.NewError(1001, "comment does not exist", aerr)
+			default:
+				return nil, common.NewError(300, "dynamodb update error", aerr)
+			}
+		}
+		return nil, common.NewError(0, "unknown error", err)
+	}
+
+	comment := CommentEntity{}
+	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &comment); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return &comment, nil
+}
+
+func key(serviceId string, commentId string) map[string]dynamodb.AttributeValue {
+	return map[string]dynamodb.AttributeValue{
+		"serviceid": {
+			S: aws.String(serviceId),
+		},
+		"id": {
+			S: aws.String(commentId),
+		},
+	}
+}
@@ -0,0 +1,231 @@
+package searchdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/search"
+)
+
+// PostingEntity provides Search Index DB Record Contents: an entry of a version listed under one of its words.
+// An entry is stored once per word, so that a query reads the entries of its word only.
+type PostingEntity struct {
+	Prefix     string `json:"prefix"`     // the first letters of the word. see Prefix
+	Posting    string `json:"posting"`    // word#id#version#entry number. sorted by word
+	Versionkey string `json:"versionkey"` // id#version. the key of the version index
+	ID         string `json:"id"`
+	Version    string `json:"version"`
+	search.Entry
+	Lastupdated int64 `json:"lastupdated"`
+}
+
+// Ref returns the reference of the entry, shared by the postings of all its words
+func (this PostingEntity) Ref() string {
+	return this.Posting[strings.Index(this.Posting, "#")+1:]
+}
+
+// PrefixLength is the number of letters of the prefix of a word. Shorter words are their own prefix.
+const PrefixLength = 2
+
+// Prefix returns the prefix of a word, under which the words starting with it are stored
+func Prefix(word string) string {
+	runes := []rune(word)
+	if len(runes) > PrefixLength {
+		runes = runes[:PrefixLength]
+	}
+	return string(runes)
+}
+
+// versionIndex is the global secondary index of the postings of a version
+const versionIndex = "version-index"
+
+// batchSize is the max number of writes of a BatchWriteItem request
+const batchSize = 25
+
+// SearchRepositoryDao provides an interface of Dao for the search index
+type SearchRepositoryDao interface {
+	PutIndex(serviceId string, version string, entries []search.Entry, lastupdated int64) error
+	DeleteIndex(serviceId string, version string) error
+	GetPostings(word string) ([]PostingEntity, error)
+}
+
+type searchRepositoryDaoImpl struct {
+	tableName    string
+	dynamoClient *dynamodb.DynamoDB
+}
+
+// NewDaoDefaultConfig return DynamoDB Session
+func NewDaoDefaultConfig(tableName string) (SearchRepositoryDao, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	cfg.DisableEndpointHostPrefix = true
+
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+
+	return &searchRepositoryDaoImpl{
+		dynamoClient: dynamodb.New(cfg),
+		tableName:    tableName,
+	}, nil
+}
+
+// NewDaoWithRegionAndEndpoint return DynamoDB Session
+// If you are using dynamodb local, use it.
+// example: dao, err := NewDaoWithRegionAndEndpoint("tablename", "ap-northeast-1", "http://localhost:8000")
+func NewDaoWithRegionAndEndpoint(tableName string, region string, endpoint string) (SearchRepositoryDao, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	cfg.EndpointResolver = aws.ResolveWithEndpointURL(endpoint)
+	cfg.Region = region
+	cfg.DisableEndpointHostPrefix = true
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+
+	return &searchRepositoryDaoImpl{
+		dynamoClient: dynamodb.New(cfg),
+		tableName:    tableName,
+	}, nil
+}
+
+// PutIndex creates or replaces the index of a version
+func (this *searchRepositoryDaoImpl) PutIndex(serviceId string, version string, entries []search.Entry, lastupdated int64) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	if err := this.DeleteIndex(serviceId, version); err != nil {
+		return err
+	}
+
+	requests := []dynamodb.WriteRequest{}
+	for i, entry := range entries {
+		words := map[string]bool{}
+		for _, text := range []string{entry.Path, entry.Name, entry.Text} {
+			for _, word := range search.Tokenize(text) {
+				if words[word] {
+					continue
+				}
+				words[word] = true
+				item, err := dynamodbattribute.MarshalMap(PostingEntity{
+					Prefix:      Prefix(word),
+					Posting:     fmt.Sprintf("%s#%s#%s#%05d", word, serviceId, version, i),
+					Versionkey:  serviceId + "#" + version,
+					ID:          serviceId,
+					Version:     version,
+					Entry:       entry,
+					Lastupdated: lastupdated,
+				})
+				if err != nil {
+					return common.NewError(301, "dynamoDB marhsallist error", err)
+				}
+				requests = append(requests, dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}})
+			}
+		}
+	}
+	return this.batchWrite(requests)
+}
+
+// DeleteIndex deletes the index of a version
+func (this *searchRepositoryDaoImpl) DeleteIndex(serviceId string, version string) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	expr, err := expression.NewBuilder().WithKeyCondition(expression.Key("versionkey").Equal(expression.Value(serviceId + "#" + version))).Build()
+	if err != nil {
+		return common.NewError(302, "expression build error", err)
+	}
+	req := this.dynamoClient.QueryRequest(&dynamodb.QueryInput{
+		IndexName:                 aws.String(versionIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(this.tableName),
+	})
+	requests := []dynamodb.WriteRequest{}
+	p := req.Paginate()
+	for p.Next() {
+		for _, item := range p.CurrentPage().Items {
+			requests = append(requests, dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{
+				Key: map[string]dynamodb.AttributeValue{
+					"prefix":  item["prefix"],
+					"posting": item["posting"],
+				},
+			}})
+		}
+	}
+	if err := p.Err(); err != nil {
+		return common.NewError(300, "dynamodb query paginate error", err)
+	}
+	return this.batchWrite(requests)
+}
+
+// GetPostings gets the postings of the words starting with word
+func (this *searchRepositoryDaoImpl) GetPostings(word string) ([]PostingEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	keyCondition := expression.Key("prefix").Equal(expression.Value(Prefix(word))).
+		And(expression.Key("posting").BeginsWith(word))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, common.NewError(302, "expression build error", err)
+	}
+	req := this.dynamoClient.QueryRequest(&dynamodb.QueryInput{
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(this.tableName),
+	})
+	var items []map[string]dynamodb.AttributeValue
+	p := req.Paginate()
+	for p.Next() {
+		items = append(items, p.CurrentPage().Items...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, common.NewError(300, "dynamodb query paginate error", err)
+	}
+
+	postings := []PostingEntity{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &postings); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return postings, nil
+}
+
+// batchWrite writes the requests by batches, and writes the unprocessed ones again
+func (this *searchRepositoryDaoImpl) batchWrite(requests []dynamodb.WriteRequest) error {
+	for len(requests) > 0 {
+		n := batchSize
+		if len(requests) < n {
+			n = len(requests)
+		}
+		batch := requests[:n]
+		requests = requests[n:]
+		for attempt := 0; len(batch) > 0; attempt++ {
+			if attempt == 3 {
+				return common.NewError(303, "dynamodb batch write unprocessed items", nil)
+			}
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+			resp, err := this.dynamoClient.BatchWriteItemRequest(&dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]dynamodb.WriteRequest{this.tableName: batch},
+			}).Send()
+			if err != nil {
+				if aerr, ok := err.(awserr.Error); ok {
+					return common.NewError(300, "dynamodb batch write error", aerr)
+				}
+				return common.NewError(0, "unknown error", err)
+			}
+			batch = resp.UnprocessedItems[this.tableName]
+		}
+	}
+	return nil
+}
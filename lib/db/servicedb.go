@@ -1,6 +1,7 @@
 package servicedb
 
 import (
+	"fmt"
 	"regexp"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/event"
 )
 
 func ValidateServiceName(serviceName string) bool {
@@ -18,19 +20,42 @@ func ValidateServiceName(serviceName string) bool {
 
 // ServiceEntity provides Service DB Record Contents
 type ServiceEntity struct {
-	Id            string `json:"id"`
-	Servicename   string `json:"servicename"`
-	Latestversion string `json:"latestversion"`
-	Lastupdated   int64  `json:"lastupdated"`
+	Id            string        `json:"id"`
+	Servicename   string        `json:"servicename"`
+	Namespace     string        `json:"namespace,omitempty"` // organization/team. "" is the default namespace
+	Latestversion string        `json:"latestversion"`
+	Lastupdated   int64         `json:"lastupdated"`
+	Lintrules     string        `json:"lintrules,omitempty"` // YAML lint ruleset of the service
+	Dependencies  []Dependency  `json:"dependencies,omitempty"`
+	Review        *ReviewPolicy `json:"review,omitempty"` // uploaded versions are reviewed before they are published
+}
+
+// ReviewPolicy requires the versions uploaded to a service to be approved before they are enabled or promoted
+type ReviewPolicy struct {
+	Approvals    int      `json:"approvals"`              // approvals required
+	Reviewers    []string `json:"reviewers,omitempty"`    // principals allowed to review. at least as many as approvals
+	SelfApproval bool     `json:"selfApproval,omitempty"` // the uploader may approve the version
+}
+
+// Dependency is a service consumed by a service
+type Dependency struct {
+	ServiceId string `json:"serviceId"`
+	Range     string `json:"range"` // semantic version range of the consumed versions (^1.2.0, >=1.0.0 <3.0.0 etc.)
+	// operations called by the consumer ("GET /pets/{id}" or operationIds). all operations if empty
+	Operations []string `json:"operations,omitempty"`
 }
 
 // UpdateServiceEntity is used for UpdateServiceRepositoryDao
 // if value is nil, it is not updated.
 type UpdateServiceEntity struct {
-	Id            *string `json:"id"`
-	Servicename   *string `json:"servicename"`
-	Latestversion *string `json:"latestversion"`
-	Lastupdated   *int64  `json:"lastupdated"`
+	Id            *string       `json:"id"`
+	Servicename   *string       `json:"servicename"`
+	Latestversion *string       `json:"latestversion"`
+	Lastupdated   *int64        `json:"lastupdated"`
+	Lintrules     *string       `json:"lintrules"`    // "" removes the ruleset
+	Dependencies  *[]Dependency `json:"dependencies"` // an empty list removes the dependencies
+	Review        *ReviewPolicy `json:"review"`       // a policy without approvals removes the policy
+	Replaced      *[]Dependency `json:"-"`            // optional. the dependencies replaced by Dependencies
 }
 
 // ServiceRepositoryDao provides an interface of Dao for service db
@@ -55,14 +80,30 @@ type UpdateServiceEntity struct {
 type ServiceRepositoryDao interface {
 	GetService(serviceId string) (*ServiceEntity, error)
 	GetServiceList() ([]ServiceEntity, error)
+	GetServicePage(limit int64, next string) ([]ServiceEntity, string, error)
 	CreateService(service ServiceEntity) (*ServiceEntity, error)
 	UpdateService(service UpdateServiceEntity) (*ServiceEntity, error)
 	DeleteService(serviceId string) (*ServiceEntity, error)
+	SetPublisher(publisher event.Publisher)
+	SetNameTable(tableName string)
 }
 
 type serviceRepositoryDaoImpl struct {
-	tableName    string
-	dynamoClient *dynamodb.DynamoDB
+	tableName     string
+	nameTableName string
+	dynamoClient  *dynamodb.DynamoDB
+	publisher     event.Publisher
+}
+
+// DefaultNamespace is the namespace of the services stored without one (tenant.Default)
+const DefaultNamespace = "default/default"
+
+// NameKey returns the key of the name of a service in the name table: names are unique per namespace
+func NameKey(namespace string, servicename string) string {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return namespace + "#" + servicename
 }
 
 // NewDaoDefaultConfig return DynamoDB Session
@@ -77,6 +118,7 @@ func NewDaoDefaultConfig(tableName string) (ServiceRepositoryDao, error) {
 	return &serviceRepositoryDaoImpl{
 		dynamoClient: dynamodb.New(cfg),
 		tableName:    tableName,
+		publisher:    event.NopPublisher{},
 	}, nil
 }
 
@@ -91,6 +133,7 @@ func NewDaoWithRegion(tableName string, region string) (ServiceRepositoryDao, er
 	return &serviceRepositoryDaoImpl{
 		dynamoClient: dynamodb.New(cfg),
 		tableName:    tableName,
+		publisher:    event.NopPublisher{},
 	}, nil
 }
 
@@ -109,9 +152,27 @@ func NewDaoWithRegionAndEndpoint(tableName string, region string, endpoint strin
 	return &serviceRepositoryDaoImpl{
 		dynamoClient: dynamodb.New(cfg),
 		tableName:    tableName,
+		publisher:    event.NopPublisher{},
 	}, nil
 }
 
+// SetPublisher sets the publisher of domain events (ServiceCreated, ServiceDeleted)
+func (this *serviceRepositoryDaoImpl) SetPublisher(publisher event.Publisher) {
+	this.publisher = publisher
+}
+
+// SetNameTable sets the table reserving the names of services. If it is set, services are created,
+// renamed and deleted in a transaction with their names, and a name taken in the namespace fails with code 1003.
+func (this *serviceRepositoryDaoImpl) SetNameTable(tableName string) {
+	this.nameTableName = tableName
+}
+
+func (this *serviceRepositoryDaoImpl) publish(e event.Event) {
+	if err := this.publisher.Publish(e); err != nil {
+		fmt.Println(err)
+	}
+}
+
 // GetService gets a service info.
 func (this *serviceRepositoryDaoImpl) GetService(serviceId string) (*ServiceEntity, error) {
 	if this == nil {
@@ -167,6 +228,41 @@ func (this *serviceRepositoryDaoImpl) GetServiceList() ([]ServiceEntity, error)
 	return services, nil
 }
 
+// GetServicePage gets at most limit services after the page token next.
+// It returns the token of the next page, or "" for the last page.
+func (this *serviceRepositoryDaoImpl) GetServicePage(limit int64, next string) ([]ServiceEntity, string, error) {
+	if this == nil {
+		return nil, "", common.NewError(100, "nil pointer receiver", nil)
+	}
+	startKey, err := common.DecodePageToken(next)
+	if err != nil {
+		return nil, "", err
+	}
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(this.tableName),
+		Limit:     aws.Int64(limit),
+	}
+	if startKey != nil {
+		if input.ExclusiveStartKey, err = dynamodbattribute.MarshalMap(startKey); err != nil {
+			return nil, "", common.NewError(301, "dynamoDB marhsallist error", err)
+		}
+	}
+	result, err := this.dynamoClient.ScanRequest(input).Send()
+	if err != nil {
+		return nil, "", common.NewError(300, "dynamodb scan error", err)
+	}
+
+	services := []ServiceEntity{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, &services); err != nil {
+		return nil, "", common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	var lastKey map[string]string
+	if err := dynamodbattribute.UnmarshalMap(result.LastEvaluatedKey, &lastKey); err != nil {
+		return nil, "", common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return services, common.EncodePageToken(lastKey), nil
+}
+
 // CreateService creates service
 func (this *serviceRepositoryDaoImpl) CreateService(service ServiceEntity) (*ServiceEntity, error) {
 	if this == nil {
@@ -177,6 +273,25 @@ func (this *serviceRepositoryDaoImpl) CreateService(service ServiceEntity) (*Ser
 	if err != nil {
 		return nil, common.NewError(301, "dynamoDB marhsallist error", err)
 	}
+	if this.nameTableName != "" {
+		if err := this.transact([]dynamodb.TransactWriteItem{
+			{
+				Put: &dynamodb.Put{
+					TableName:           aws.String(this.tableName),
+					Item:                item,
+					ConditionExpression: aws.String("attribute_not_exists(#id)"),
+					ExpressionAttributeNames: map[string]string{
+						"#id": "id",
+					},
+				},
+			},
+			this.reserveName(service.Namespace, service.Servicename, service.Id),
+		}); err != nil {
+			return nil, err
+		}
+		this.publish(event.NewServiceCreated(service.Id, service))
+		return &ServiceEntity{}, nil
+	}
 	result, err := this.dynamoClient.PutItemRequest(&dynamodb.PutItemInput{
 		TableName:           aws.String(this.tableName),
 		Item:                item,
@@ -202,10 +317,13 @@ func (this *serviceRepositoryDaoImpl) CreateService(service ServiceEntity) (*Ser
 	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &entity); err != nil {
 		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
 	}
+	this.publish(event.NewServiceCreated(service.Id, service))
 	return &entity, nil // return old data. Usually, This value is nothing.
 }
 
 // UpdateService updates service info.
+// If Replaced is set, the update fails with code 1004 unless the replaced dependencies are still stored,
+// so that concurrent read-modify-writes of the dependencies do not overwrite each other.
 func (this *serviceRepositoryDaoImpl) UpdateService(service UpdateServiceEntity) (*ServiceEntity, error) {
 	if this == nil {
 		return nil, common.NewError(100, "nil pointer receiver", nil)
@@ -229,6 +347,31 @@ func (this *serviceRepositoryDaoImpl) UpdateService(service UpdateServiceEntity)
 		willBeUpdated = true
 		update = update.Set(expression.Name("lastupdated"), expression.Value(*service.Lastupdated))
 	}
+	if service.Lintrules != nil {
+		willBeUpdated = true
+		if *service.Lintrules == "" {
+			update = update.Remove(expression.Name("lintrules"))
+		} else {
+			update = update.Set(expression.Name("lintrules"), expression.Value(*service.Lintrules))
+		}
+	}
+	if service.Dependencies != nil {
+		willBeUpdated = true
+		if len(*service.Dependencies) == 0 {
+			update = update.Remove(expression.Name("dependencies"))
+		} else {
+			update = update.Set(expression.Name("dependencies"), expression.Value(*service.Dependencies))
+		}
+	}
+
+	if service.Review != nil {
+		willBeUpdated = true
+		if service.Review.Approvals == 0 {
+			update = update.Remove(expression.Name("review"))
+		} else {
+			update = update.Set(expression.Name("review"), expression.Value(*service.Review))
+		}
+	}
 
 	if !willBeUpdated {
 		return nil, common.NewError(1001, "one or more attributes are required", nil)
@@ -236,6 +379,17 @@ func (this *serviceRepositoryDaoImpl) UpdateService(service UpdateServiceEntity)
 
 	condition := expression.AttributeExists(expression.Name("id"))
 	// anotherCondition := expression.Not(condition)
+	if service.Replaced != nil {
+		if len(*service.Replaced) == 0 {
+			condition = condition.And(expression.AttributeNotExists(expression.Name("dependencies")))
+		} else {
+			condition = condition.And(expression.Name("dependencies").Equal(expression.Value(*service.Replaced)))
+		}
+	}
+
+	if service.Servicename != nil && this.nameTableName != "" {
+		return this.rename(*service.Id, *service.Servicename, update)
+	}
 
 	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
 	if err != nil {
@@ -262,6 +416,9 @@ func (this *serviceRepositoryDaoImpl) UpdateService(service UpdateServiceEntity)
 		if aerr, ok := err.(awserr.Error); ok {
 			switch aerr.Code() {
 			case dynamodb.ErrCodeConditionalCheckFailedException:
+				if service.Replaced != nil {
+					return nil, common.NewError(1004, "id does not exists or dependencies were changed", aerr)
+				}
 				return nil, common.NewError(1002, "id does not exists", aerr)
 			default:
 				return nil, common.NewError(300, "dynamodb put error", aerr)
@@ -283,6 +440,9 @@ func (this *serviceRepositoryDaoImpl) DeleteService(serviceId string) (*ServiceE
 	if this == nil {
 		return nil, common.NewError(100, "nil pointer receiver", nil)
 	}
+	if this.nameTableName != "" {
+		return this.deleteWithName(serviceId)
+	}
 	result, err := this.dynamoClient.DeleteItemRequest(&dynamodb.DeleteItemInput{
 		Key: map[string]dynamodb.AttributeValue{
 			"id": {
@@ -305,5 +465,150 @@ func (this *serviceRepositoryDaoImpl) DeleteService(serviceId string) (*ServiceE
 	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &entity); err != nil {
 		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
 	}
+	if entity.Id != "" {
+		this.publish(event.NewServiceDeleted(serviceId, entity))
+	}
 	return &entity, nil
 }
+
+// rename updates a service and moves its name in a transaction. The update is applied
+// only if the service still has the name read before, and the new name must be free in the namespace.
+func (this *serviceRepositoryDaoImpl) rename(serviceId string, servicename string, update expression.UpdateBuilder) (*ServiceEntity, error) {
+	before, err := this.GetService(serviceId)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil {
+		return nil, common.NewError(1002, "id does not exists", nil)
+	}
+
+	condition := expression.Name("servicename").Equal(expression.Value(before.Servicename))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return nil, common.NewError(302, "expression build error", err)
+	}
+	items := []dynamodb.TransactWriteItem{
+		{
+			Update: &dynamodb.Update{
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+				TableName:                 aws.String(this.tableName),
+				UpdateExpression:          expr.Update(),
+				ConditionExpression:       expr.Condition(),
+				Key: map[string]dynamodb.AttributeValue{
+					"id": {
+						S: aws.String(serviceId),
+					},
+				},
+			},
+		},
+	}
+	if servicename != before.Servicename {
+		items = append(items, this.reserveName(before.Namespace, servicename, serviceId), this.releaseName(before.Namespace, before.Servicename, serviceId))
+	}
+	if err := this.transact(items); err != nil {
+		return nil, err
+	}
+	return this.GetService(serviceId)
+}
+
+// deleteWithName deletes a service and releases its name in a transaction
+func (this *serviceRepositoryDaoImpl) deleteWithName(serviceId string) (*ServiceEntity, error) {
+	before, err := this.GetService(serviceId)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil {
+		return &ServiceEntity{}, nil
+	}
+	if err := this.transact([]dynamodb.TransactWriteItem{
+		{
+			Delete: &dynamodb.Delete{
+				TableName:           aws.String(this.tableName),
+				ConditionExpression: aws.String("#servicename = :servicename"),
+				ExpressionAttributeNames: map[string]string{
+					"#servicename": "servicename",
+				},
+				ExpressionAttributeValues: map[string]dynamodb.AttributeValue{
+					":servicename": {
+						S: aws.String(before.Servicename),
+					},
+				},
+				Key: map[string]dynamodb.AttributeValue{
+					"id": {
+						S: aws.String(serviceId),
+					},
+				},
+			},
+		},
+		this.releaseName(before.Namespace, before.Servicename, serviceId),
+	}); err != nil {
+		return nil, err
+	}
+	this.publish(event.NewServiceDeleted(serviceId, *before))
+	return before, nil
+}
+
+// reserveName puts the name of a service if the name is free in the namespace
+func (this *serviceRepositoryDaoImpl) reserveName(namespace string, servicename string, serviceId string) dynamodb.TransactWriteItem {
+	return dynamodb.TransactWriteItem{
+		Put: &dynamodb.Put{
+			TableName: aws.String(this.nameTableName),
+			Item: map[string]dynamodb.AttributeValue{
+				"name": {
+					S: aws.String(NameKey(namespace, servicename)),
+				},
+				"id": {
+					S: aws.String(serviceId),
+				},
+			},
+			ConditionExpression: aws.String("attribute_not_exists(#name)"),
+			ExpressionAttributeNames: map[string]string{
+				"#name": "name",
+			},
+		},
+	}
+}
+
+// releaseName deletes the name of a service unless another service holds it.
+// Services created before the name table have no name to release.
+func (this *serviceRepositoryDaoImpl) releaseName(namespace string, servicename string, serviceId string) dynamodb.TransactWriteItem {
+	return dynamodb.TransactWriteItem{
+		Delete: &dynamodb.Delete{
+			TableName:           aws.String(this.nameTableName),
+			ConditionExpression: aws.String("attribute_not_exists(#id) OR #id = :id"),
+			ExpressionAttributeNames: map[string]string{
+				"#id": "id",
+			},
+			ExpressionAttributeValues: map[string]dynamodb.AttributeValue{
+				":id": {
+					S: aws.String(serviceId),
+				},
+			},
+			Key: map[string]dynamodb.AttributeValue{
+				"name": {
+					S: aws.String(NameKey(namespace, servicename)),
+				},
+			},
+		},
+	}
+}
+
+// transact writes items in a transaction. A failed condition returns code 1003:
+// the name is taken, or the service was changed or deleted concurrently.
+func (this *serviceRepositoryDaoImpl) transact(items []dynamodb.TransactWriteItem) error {
+	if _, err := this.dynamoClient.TransactWriteItemsRequest(&dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	}).Send(); err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case dynamodb.ErrCodeTransactionCanceledException:
+				return common.NewError(1003, "service name already exists in the namespace or the service was changed", aerr)
+			default:
+				return common.NewError(300, "dynamodb transaction error", aerr)
+			}
+		}
+		return common.NewError(0, "unknown error", err)
+	}
+	return nil
+}
@@ -46,6 +46,8 @@ func TestCREATE_GET(t *testing.T) {
 		Lastupdated: time.Now().Unix() * 1000,
 		Enable:      true,
 		Tag:         tag,
+		Format:      "yaml",
+		Jsonpath:    keyName + ".canonical.json",
 	}
 	if _, err := dao.UploadVersion(requestEntity, bucketName, keyName, contents); err != nil {
 		t.Fatalf("upload error %#v", err)
@@ -3,6 +3,9 @@ package versiondb
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/awserr"
@@ -12,15 +15,43 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/event"
 )
 
+// PromotedTag is the tag which means the version is promoted
+const PromotedTag = "prod"
+
 type VersionEntity struct {
-	ID          string `json:"id"`
-	Version     string `json:"version"`
-	Path        string `json:"path"`
-	Lastupdated int64  `json:"lastupdated"`
-	Enable      bool   `json:"enable"`
-	Tag         string `json:"tag"`
+	ID            string  `json:"id"`
+	Version       string  `json:"version"`
+	Path          string  `json:"path"`
+	Lastupdated   int64   `json:"lastupdated"`
+	Enable        bool    `json:"enable"`
+	Tag           string  `json:"tag"`
+	Sourcepath    string  `json:"sourcepath,omitempty"`    // original tree of a bundled (multi-file) upload
+	Format        string  `json:"format,omitempty"`        // format of the uploaded file (yaml or json)
+	Jsonpath      string  `json:"jsonpath,omitempty"`      // canonical JSON rendition of the swagger file
+	Inventorypath string  `json:"inventorypath,omitempty"` // operation inventory (JSON)
+	Docspath      string  `json:"docspath,omitempty"`      // pre-rendered HTML documentation, rendered on the first request
+	Review        *Review `json:"review,omitempty"`        // review of a version uploaded under a review policy
+}
+
+// Review is the review of a version. The version is published (enabled, tagged prod) only once approved.
+type Review struct {
+	Status    string           `json:"status"` // pending, approved or rejected
+	Uploader  string           `json:"uploader"`
+	Enable    bool             `json:"enable"` // requested on upload, applied on approval
+	Tag       string           `json:"tag"`
+	Decisions []ReviewDecision `json:"decisions"`
+	Revision  int              `json:"revision"` // incremented by every decision, so that concurrent decisions conflict
+}
+
+// ReviewDecision is the approval or rejection of a reviewer
+type ReviewDecision struct {
+	Principal string `json:"principal"`
+	Decision  string `json:"decision"` // approve or reject
+	Comment   string `json:"comment,omitempty"`
+	Timestamp int64  `json:"timestamp"`
 }
 
 type UpdateVersionEntity struct {
@@ -39,15 +70,26 @@ type AwsEndpoint struct {
 
 type VersionRepositoryDao interface {
 	GetAllVersions(servicId string) ([]VersionEntity, error)
+	GetVersionPage(serviceId string, limit int64, next string) ([]VersionEntity, string, error)
+	GetVersion(serviceId string, version string) (*VersionEntity, error)
 	CreateVersion(version VersionEntity) (*VersionEntity, error)
 	UpdateVersion(version VersionEntity) (*VersionEntity, error)
 	UploadVersion(version VersionEntity, bucket string, key string, contents string) (*VersionEntity, error)
+	DeleteVersion(serviceId string, version string, bucket string) (*VersionEntity, error)
+	DownloadVersion(bucket string, key string) (string, error)
+	UploadFile(bucket string, key string, contents string) error
+	DeleteFile(bucket string, key string) error
+	SetDocspath(version VersionEntity, key string) error
+	SetReview(before VersionEntity, after VersionEntity) error
+	SetStatus(before VersionEntity, after VersionEntity) error
+	SetPublisher(publisher event.Publisher)
 }
 
 type versionRepositoryDaoImpl struct {
 	tableName    string
 	dynamoClient *dynamodb.DynamoDB
 	s3Client     *s3.S3
+	publisher    event.Publisher
 }
 
 // NewDaoDefaultConfig return DynamoDB Session
@@ -66,6 +108,7 @@ func NewDaoDefaultConfig(tableName string) (VersionRepositoryDao, error) {
 		dynamoClient: dynamodb.New(cfg),
 		tableName:    tableName,
 		s3Client:     s3Client,
+		publisher:    event.NopPublisher{},
 	}, nil
 }
 
@@ -84,6 +127,7 @@ func NewDaoWithRegion(tableName string, region string) (VersionRepositoryDao, er
 		dynamoClient: dynamodb.New(cfg),
 		tableName:    tableName,
 		s3Client:     s3Client,
+		publisher:    event.NopPublisher{},
 	}, nil
 }
 
@@ -106,6 +150,7 @@ func NewDaoWithRegionAndEndpoint(tableName string, dynamoRegion string, dynamoEn
 		dynamoClient: dynamodb.New(cfg),
 		tableName:    tableName,
 		s3Client:     s3Client,
+		publisher:    event.NopPublisher{},
 	}, nil
 }
 
@@ -133,9 +178,21 @@ func NewDaoWithEndpoints(tableName string, dynamoEndpoint AwsEndpoint, s3Endpoin
 		dynamoClient: dynamodb.New(dynamoCfg),
 		tableName:    tableName,
 		s3Client:     s3Client,
+		publisher:    event.NopPublisher{},
 	}, nil
 }
 
+// SetPublisher sets the publisher of domain events (VersionUploaded, VersionUpdated)
+func (this *versionRepositoryDaoImpl) SetPublisher(publisher event.Publisher) {
+	this.publisher = publisher
+}
+
+func (this *versionRepositoryDaoImpl) publish(e event.Event) {
+	if err := this.publisher.Publish(e); err != nil {
+		fmt.Println(err)
+	}
+}
+
 func (this *versionRepositoryDaoImpl) GetAllVersions(serviceId string) ([]VersionEntity, error) {
 	if this == nil {
 		return nil, common.NewError(100, "nil pointer receiver", nil)
@@ -185,6 +242,81 @@ func (this *versionRepositoryDaoImpl) GetAllVersions(serviceId string) ([]Versio
 	return versions, nil
 }
 
+// GetVersionPage gets at most limit versions of a service after the page token next.
+// It returns the token of the next page, or "" for the last page.
+func (this *versionRepositoryDaoImpl) GetVersionPage(serviceId string, limit int64, next string) ([]VersionEntity, string, error) {
+	if this == nil {
+		return nil, "", common.NewError(100, "nil pointer receiver", nil)
+	}
+	startKey, err := common.DecodePageToken(next)
+	if err != nil {
+		return nil, "", err
+	}
+	keyCond := expression.Key("id").Equal(expression.Value(serviceId))
+	expression, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, "", common.NewError(302, "expression build error", err)
+	}
+	input := &dynamodb.QueryInput{
+		KeyConditionExpression:    expression.KeyCondition(),
+		ExpressionAttributeNames:  expression.Names(),
+		ExpressionAttributeValues: expression.Values(),
+		TableName:                 aws.String(this.tableName),
+		Limit:                     aws.Int64(limit),
+	}
+	if startKey != nil {
+		if input.ExclusiveStartKey, err = dynamodbattribute.MarshalMap(startKey); err != nil {
+			return nil, "", common.NewError(301, "dynamoDB marhsallist error", err)
+		}
+	}
+	result, err := this.dynamoClient.QueryRequest(input).Send()
+	if err != nil {
+		return nil, "", common.NewError(300, "dynamodb query error", err)
+	}
+
+	versions := []VersionEntity{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, &versions); err != nil {
+		return nil, "", common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	var lastKey map[string]string
+	if err := dynamodbattribute.UnmarshalMap(result.LastEvaluatedKey, &lastKey); err != nil {
+		return nil, "", common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return versions, common.EncodePageToken(lastKey), nil
+}
+
+// GetVersion gets a version info. It returns nil if the version does not exist.
+func (this *versionRepositoryDaoImpl) GetVersion(serviceId string, version string) (*VersionEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+	result, err := this.dynamoClient.GetItemRequest(&dynamodb.GetItemInput{
+		Key: map[string]dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(serviceId),
+			},
+			"version": {
+				S: aws.String(version),
+			},
+		},
+		TableName: aws.String(this.tableName),
+	}).Send()
+
+	if err != nil {
+		return nil, common.NewError(300, "dynamoDB error", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	entity := VersionEntity{}
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &entity); err != nil {
+		return nil, common.NewError(101, "unmarshal error", err)
+	}
+	return &entity, nil
+}
+
 func (this *versionRepositoryDaoImpl) CreateVersion(version VersionEntity) (*VersionEntity, error) {
 	if this == nil {
 		return nil, common.NewError(100, "nil pointer receiver", nil)
@@ -257,6 +389,7 @@ func (this *versionRepositoryDaoImpl) UpdateVersion(version VersionEntity) (*Ver
 	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &entity); err != nil {
 		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
 	}
+	this.publish(event.NewVersionUpdated(version.ID, version.Version, version))
 	return &entity, nil // return old data. Usually, This value is nothing.
 }
 
@@ -271,9 +404,10 @@ func (this *versionRepositoryDaoImpl) UploadVersion(version VersionEntity, bucke
 	}
 
 	input := &s3.PutObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
-		Body:   bytes.NewReader([]byte(contents)),
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader([]byte(contents)),
+		ContentType: contentType(key),
 	}
 
 	s3Result, err := this.s3Client.PutObjectRequest(input).Send()
@@ -316,5 +450,275 @@ func (this *versionRepositoryDaoImpl) UploadVersion(version VersionEntity, bucke
 	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &entity); err != nil {
 		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
 	}
+	this.publish(event.NewVersionUploaded(version.ID, version.Version, version))
 	return &entity, nil // return old data. Usually, This value is nothing.
 }
+
+// DeleteVersion deletes a version info and its swagger file.
+// It returns an empty entity if the version does not exist.
+func (this *versionRepositoryDaoImpl) DeleteVersion(serviceId string, version string, bucket string) (*VersionEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+	result, err := this.dynamoClient.DeleteItemRequest(&dynamodb.DeleteItemInput{
+		Key: map[string]dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(serviceId),
+			},
+			"version": {
+				S: aws.String(version),
+			},
+		},
+		TableName:    aws.String(this.tableName),
+		ReturnValues: dynamodb.ReturnValueAllOld,
+	}).Send()
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			return nil, common.NewError(300, "dynamodb delete error", aerr)
+		}
+		return nil, common.NewError(0, "unknown error", err)
+	}
+
+	entity := VersionEntity{}
+	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &entity); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	if entity.Path == "" {
+		return &entity, nil
+	}
+
+	for _, key := range []string{entity.Path, entity.Sourcepath, entity.Jsonpath, entity.Inventorypath, entity.Docspath} {
+		if key == "" {
+			continue
+		}
+		if _, err := this.s3Client.DeleteObjectRequest(&s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		}).Send(); err != nil {
+			return &entity, common.NewError(301, "s3 deleteobject error", err)
+		}
+	}
+	return &entity, nil
+}
+
+// UploadFile puts a file related to a version (e.g. the original tree of a bundled document)
+func (this *versionRepositoryDaoImpl) UploadFile(bucket string, key string, contents string) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	if _, err := this.s3Client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader([]byte(contents)),
+		ContentType: contentType(key),
+	}).Send(); err != nil {
+		return common.NewError(301, "s3 putobject error", err)
+	}
+	return nil
+}
+
+// DeleteFile deletes a file related to a version (e.g. cached documentation)
+func (this *versionRepositoryDaoImpl) DeleteFile(bucket string, key string) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	if _, err := this.s3Client.DeleteObjectRequest(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}).Send(); err != nil {
+		return common.NewError(301, "s3 deleteobject error", err)
+	}
+	return nil
+}
+
+// SetDocspath records the key of the rendered documentation of a version.
+// It returns error code 1001 if the version was deleted or its swagger file was replaced in the meantime.
+// No event is published, as the version itself is not changed.
+func (this *versionRepositoryDaoImpl) SetDocspath(version VersionEntity, key string) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	update := expression.Set(expression.Name("docspath"), expression.Value(key))
+	condition := expression.Name("path").Equal(expression.Value(version.Path))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return common.NewError(302, "expression build error", err)
+	}
+
+	if _, err := this.dynamoClient.UpdateItemRequest(&dynamodb.UpdateItemInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(this.tableName),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		Key: map[string]dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(version.ID),
+			},
+			"version": {
+				S: aws.String(version.Version),
+			},
+		},
+	}).Send(); err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case dynamodb.ErrCodeConditionalCheckFailedException:
+				return common.NewError(1001, "id and version do not exist or the swagger file was replaced", aerr)
+			default:
+				return common.NewError(300, "dynamodb update error", aerr)
+			}
+		}
+		return common.NewError(0, "unknown error", err)
+	}
+	return nil
+}
+
+// SetReview sets the review, the enable flag and the tag of after if the swagger file and the review
+// are still those of before. It returns error code 1001 if the version was replaced or reviewed concurrently.
+func (this *versionRepositoryDaoImpl) SetReview(before VersionEntity, after VersionEntity) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	if before.Review == nil || after.Review == nil {
+		return common.NewError(1001, "version is not under review", nil)
+	}
+
+	update := expression.Set(expression.Name("review"), expression.Value(*after.Review)).
+		Set(expression.Name("enable"), expression.Value(after.Enable)).
+		Set(expression.Name("tag"), expression.Value(after.Tag))
+	revision := expression.Name("review.revision").Equal(expression.Value(before.Review.Revision))
+	if before.Review.Revision == 0 {
+		// reviews started before revisions were recorded
+		revision = expression.Or(expression.AttributeNotExists(expression.Name("review.revision")), revision)
+	}
+	condition := expression.Name("path").Equal(expression.Value(before.Path)).
+		And(expression.Name("review.status").Equal(expression.Value(before.Review.Status))).
+		And(revision)
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return common.NewError(302, "expression build error", err)
+	}
+
+	if _, err := this.dynamoClient.UpdateItemRequest(&dynamodb.UpdateItemInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(this.tableName),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		Key: map[string]dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(before.ID),
+			},
+			"version": {
+				S: aws.String(before.Version),
+			},
+		},
+	}).Send(); err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case dynamodb.ErrCodeConditionalCheckFailedException:
+				return common.NewError(1001, "the version was replaced or reviewed concurrently", aerr)
+			default:
+				return common.NewError(300, "dynamodb update error", aerr)
+			}
+		}
+		return common.NewError(0, "unknown error", err)
+	}
+	this.publish(event.NewVersionUpdated(after.ID, after.Version, after))
+	return nil
+}
+
+// SetStatus sets the enable flag, the tag and lastupdated of after if the swagger file and the review
+// are still those of before. It returns error code 1001 if the version was deleted, replaced or reviewed concurrently.
+func (this *versionRepositoryDaoImpl) SetStatus(before VersionEntity, after VersionEntity) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	update := expression.Set(expression.Name("enable"), expression.Value(after.Enable)).
+		Set(expression.Name("tag"), expression.Value(after.Tag)).
+		Set(expression.Name("lastupdated"), expression.Value(after.Lastupdated))
+	reviewed := expression.AttributeNotExists(expression.Name("review"))
+	if before.Review != nil {
+		reviewed = expression.Name("review.revision").Equal(expression.Value(before.Review.Revision))
+		if before.Review.Revision == 0 {
+			// reviews started before revisions were recorded
+			reviewed = expression.Or(expression.AttributeNotExists(expression.Name("review.revision")), reviewed)
+		}
+		reviewed = expression.Name("review.status").Equal(expression.Value(before.Review.Status)).And(reviewed)
+	}
+	condition := expression.Name("path").Equal(expression.Value(before.Path)).
+		And(expression.Name("lastupdated").Equal(expression.Value(before.Lastupdated))).
+		And(reviewed)
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return common.NewError(302, "expression build error", err)
+	}
+
+	if _, err := this.dynamoClient.UpdateItemRequest(&dynamodb.UpdateItemInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(this.tableName),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		Key: map[string]dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(before.ID),
+			},
+			"version": {
+				S: aws.String(before.Version),
+			},
+		},
+	}).Send(); err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case dynamodb.ErrCodeConditionalCheckFailedException:
+				return common.NewError(1001, "the version was deleted, replaced or reviewed concurrently", aerr)
+			default:
+				return common.NewError(300, "dynamodb update error", aerr)
+			}
+		}
+		return common.NewError(0, "unknown error", err)
+	}
+	this.publish(event.NewVersionUpdated(after.ID, after.Version, after))
+	return nil
+}
+
+// DownloadVersion gets the contents of a swagger file
+func (this *versionRepositoryDaoImpl) DownloadVersion(bucket string, key string) (string, error) {
+	if this == nil {
+		return "", common.NewError(100, "nil pointer receiver", nil)
+	}
+	result, err := this.s3Client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}).Send()
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return "", common.NewError(1002, "swagger file does not exist", aerr)
+		}
+		return "", common.NewError(301, "s3 getobject error", err)
+	}
+	defer result.Body.Close()
+
+	contents, err := ioutil.ReadAll(result.Body)
+	if err != nil {
+		return "", common.NewError(301, "s3 getobject read error", err)
+	}
+	return string(contents), nil
+}
+
+// contentType returns the Content-Type of an object from the extension of its key
+func contentType(key string) *string {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".json":
+		return aws.String("application/json; charset=utf-8")
+	case ".yml", ".yaml":
+		return aws.String("application/x-yaml; charset=utf-8")
+	case ".html":
+		return aws.String("text/html; charset=utf-8")
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package webhookdb
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/expression"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// Events which webhooks can subscribe to
+const (
+	EventVersionUploaded = "version.uploaded"
+	EventVersionPromoted = "version.promoted"
+	EventVersionDisabled = "version.disabled"
+	EventVersionDeleted  = "version.deleted"
+	EventVersionReviewed = "version.reviewed"
+)
+
+// AllEvents lists all subscribable events
+var AllEvents = []string{
+	EventVersionUploaded,
+	EventVersionPromoted,
+	EventVersionDisabled,
+	EventVersionDeleted,
+	EventVersionReviewed,
+}
+
+// WebhookEntity provides Webhook DB Record Contents (subscription of a service)
+// If Events is empty, the webhook subscribes to all events.
+type WebhookEntity struct {
+	Serviceid   string   `json:"serviceid"`
+	Id          string   `json:"id"`
+	Url         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	Events      []string `json:"events"`
+	Lastupdated int64    `json:"lastupdated"`
+}
+
+// DeliveryEntity provides Delivery DB Record Contents (one record per delivery, including retries)
+type DeliveryEntity struct {
+	Webhookid  string `json:"webhookid"`
+	Deliveryid string `json:"deliveryid"`
+	Serviceid  string `json:"serviceid"`
+	Event      string `json:"event"`
+	Payload    string `json:"payload"`
+	Attempts   int    `json:"attempts"`
+	Statuscode int    `json:"statuscode"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// ValidateEvents returns false if events contains unknown event
+func ValidateEvents(events []string) bool {
+	for _, event := range events {
+		known := false
+		for _, e := range AllEvents {
+			if e == event {
+				known = true
+			}
+		}
+		if !known {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateUrl returns true if rawurl is an absolute http(s) url of a public host.
+// Hosts which are or resolve to internal addresses (see InternalIP) are rejected,
+// so that deliveries can not reach the endpoints inside the deployment.
+func ValidateUrl(rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		if ips, err = net.LookupIP(host); err != nil || len(ips) == 0 {
+			return false
+		}
+	}
+	for _, ip := range ips {
+		if InternalIP(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// internalNetworks are the loopback, link-local, private, shared (carrier-grade NAT)
+// and unspecified ranges, including the instance metadata endpoint (169.254.169.254)
+var internalNetworks = parseCIDRs(
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12", "192.168.0.0/16",
+	"::/128", "::1/128", "fc00::/7", "fe80::/10",
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// InternalIP returns true if ip is not routed on the internet: webhooks are never delivered to it
+func InternalIP(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, network := range internalNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return ip.IsMulticast()
+}
+
+// Subscribes returns true if the webhook subscribes to the event
+func (webhook WebhookEntity) Subscribes(event string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, e := range webhook.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryId returns the range key of a delivery record
+func DeliveryId(timestamp int64, id string) string {
+	return fmt.Sprintf("%013d#%s", timestamp, id)
+}
+
+// WebhookRepositoryDao provides an interface of Dao for webhook subscriptions and delivery log
+type WebhookRepositoryDao interface {
+	GetWebhooks(serviceId string) ([]WebhookEntity, error)
+	CreateWebhook(webhook WebhookEntity) (*WebhookEntity, error)
+	DeleteWebhook(serviceId string, webhookId string) (*WebhookEntity, error)
+	PutDelivery(delivery DeliveryEntity) error
+	GetDeliveries(webhookId string) ([]DeliveryEntity, error)
+}
+
+type webhookRepositoryDaoImpl struct {
+	tableName         string
+	deliveryTableName string
+	dynamoClient      *dynamodb.DynamoDB
+}
+
+// NewDaoDefaultConfig return DynamoDB Session
+func NewDaoDefaultConfig(tableName string, deliveryTableName string) (WebhookRepositoryDao, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	cfg.DisableEndpointHostPrefix = true
+
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+
+	return &webhookRepositoryDaoImpl{
+		dynamoClient:      dynamodb.New(cfg),
+		tableName:         tableName,
+		deliveryTableName: deliveryTableName,
+	}, nil
+}
+
+// NewDaoWithRegionAndEndpoint return DynamoDB Session
+// If you are using dynamodb local, use it.
+// example: dao, err := NewDaoWithRegionAndEndpoint("tablename", "deliverytablename", "ap-northeast-1", "http://localhost:8000")
+func NewDaoWithRegionAndEndpoint(tableName string, deliveryTableName string, region string, endpoint string) (WebhookRepositoryDao, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	cfg.EndpointResolver = aws.ResolveWithEndpointURL(endpoint)
+	cfg.Region = region
+	cfg.DisableEndpointHostPrefix = true
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+
+	return &webhookRepositoryDaoImpl{
+		dynamoClient:      dynamodb.New(cfg),
+		tableName:         tableName,
+		deliveryTableName: deliveryTableName,
+	}, nil
+}
+
+// GetWebhooks gets all webhooks of a service
+func (this *webhookRepositoryDaoImpl) GetWebhooks(serviceId string) ([]WebhookEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+	items, err := this.query(this.tableName, expression.Key("serviceid").Equal(expression.Value(serviceId)))
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []WebhookEntity{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &webhooks); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return webhooks, nil
+}
+
+// CreateWebhook creates a webhook subscription
+func (this *webhookRepositoryDaoImpl) CreateWebhook(webhook WebhookEntity) (*WebhookEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	item, err := dynamodbattribute.MarshalMap(webhook)
+	if err != nil {
+		return nil, common.NewError(301, "dynamoDB marhsallist error", err)
+	}
+	result, err := this.dynamoClient.PutItemRequest(&dynamodb.PutItemInput{
+		TableName:           aws.String(this.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(#id)"),
+		ExpressionAttributeNames: map[string]string{
+			"#id": "id",
+		},
+	}).Send()
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case dynamodb.ErrCodeConditionalCheckFailedException:
+				return nil, common.NewError(1000, "id already exists", aerr)
+			default:
+				return nil, common.NewError(300, "dynamodb put error", aerr)
+			}
+		}
+		return nil, common.NewError(0, "unknown error", err)
+	}
+	entity := WebhookEntity{}
+	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &entity); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return &entity, nil // return old data. Usually, This value is nothing.
+}
+
+// DeleteWebhook deletes a webhook subscription. The delivery log is kept.
+func (this *webhookRepositoryDaoImpl) DeleteWebhook(serviceId string, webhookId string) (*WebhookEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+	result, err := this.dynamoClient.DeleteItemRequest(&dynamodb.DeleteItemInput{
+		Key: map[string]dynamodb.AttributeValue{
+			"serviceid": {
+				S: aws.String(serviceId),
+			},
+			"id": {
+				S: aws.String(webhookId),
+			},
+		},
+		TableName:    aws.String(this.tableName),
+		ReturnValues: dynamodb.ReturnValueAllOld,
+	}).Send()
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			return nil, common.NewError(300, "dynamodb delete error", aerr)
+		}
+		return nil, common.NewError(0, "unknown error", err)
+	}
+
+	entity := WebhookEntity{}
+	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &entity); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return &entity, nil
+}
+
+// PutDelivery appends a delivery record
+func (this *webhookRepositoryDaoImpl) PutDelivery(delivery DeliveryEntity) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+
+	item, err := dynamodbattribute.MarshalMap(delivery)
+	if err != nil {
+		return common.NewError(301, "dynamoDB marhsallist error", err)
+	}
+	if _, err := this.dynamoClient.PutItemRequest(&dynamodb.PutItemInput{
+		TableName: aws.String(this.deliveryTableName),
+		Item:      item,
+	}).Send(); err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			return common.NewError(300, "dynamodb put error", aerr)
+		}
+		return common.NewError(0, "unknown error", err)
+	}
+	return nil
+}
+
+// GetDeliveries gets the delivery log of a webhook
+func (this *webhookRepositoryDaoImpl) GetDeliveries(webhookId string) ([]DeliveryEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+	items, err := this.query(this.deliveryTableName, expression.Key("webhookid").Equal(expression.Value(webhookId)))
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []DeliveryEntity{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(items, &deliveries); err != nil {
+		return nil, common.NewError(301, "dynamoDB unmarhsallist error", err)
+	}
+	return deliveries, nil
+}
+
+func (this *webhookRepositoryDaoImpl) query(tableName string, keyCond expression.KeyConditionBuilder) ([]map[string]dynamodb.AttributeValue, error) {
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, common.NewError(302, "expression build error", err)
+	}
+
+	req := this.dynamoClient.QueryRequest(&dynamodb.QueryInput{
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		TableName:                 aws.String(tableName),
+	})
+	p := req.Paginate()
+
+	var items []map[string]dynamodb.AttributeValue
+	for p.Next() {
+		page := p.CurrentPage()
+		items = append(items, page.Items...)
+	}
+	if err := p.Err(); err != nil {
+		return nil, common.NewError(300, "dynamodb query paginate error", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,137 @@
+package dependency
+
+import (
+	"fmt"
+	"strings"
+
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/semver"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// Reasons of warnings
+const (
+	ReasonBreaking   = "breaking"     // the version is in the range but breaks the previous version in it
+	ReasonOutOfRange = "out-of-range" // the version is newer than the versions in the range
+)
+
+// Warning is a consumer whose range is broken by an uploaded version of a provider
+type Warning struct {
+	Consumer     string           `json:"consumer"`
+	ConsumerName string           `json:"consumerName"`
+	Range        string           `json:"range"`
+	Reason       string           `json:"reason"`
+	Previous     string           `json:"previous,omitempty"` // the version of the range compared with
+	Message      string           `json:"message"`
+	Changes      []swagger.Change `json:"changes,omitempty"` // breaking changes from Previous affecting the consumer
+	Impacts      []Impact         `json:"impacts,omitempty"` // registered operations affected by Changes
+}
+
+// Loader loads a version of the provider
+type Loader func(version string) (swagger.Document, error)
+
+// checker caches the documents and diffs of the versions of a provider
+type checker struct {
+	uploaded swagger.Document
+	load     Loader
+	docs     map[string]swagger.Document
+	diffs    map[string][]swagger.Change
+}
+
+// breaking returns the previous version and the breaking changes of the uploaded version from it
+func (this *checker) breaking(previous string) (swagger.Document, []swagger.Change, error) {
+	if _, ok := this.docs[previous]; !ok {
+		doc, err := this.load(previous)
+		if err != nil {
+			return nil, nil, err
+		}
+		this.docs[previous] = doc
+		this.diffs[previous] = swagger.Diff(doc, this.uploaded).Breaking()
+	}
+	return this.docs[previous], this.diffs[previous], nil
+}
+
+// Check checks an uploaded version of provider against the ranges of its consumers.
+// versions are the other versions of the provider, before the upload. A version in a range
+// must not break the highest version below it in the range (or the replaced one if the same
+// version is uploaded again), and a version above the versions of a range is reported as its consumers
+// do not get it, with the changes breaking them if they upgrade.
+// Consumers which registered the operations they call are affected only by the changes of these operations.
+// Versions which are not semantic versions are not checked.
+func Check(services []servicedb.ServiceEntity, provider string, uploaded swagger.Document, versions []string, load Loader) ([]Warning, error) {
+	warnings := []Warning{}
+	version := uploaded.Version()
+	v, ok := semver.Parse(version)
+	if !ok {
+		return warnings, nil
+	}
+	c := &checker{uploaded: uploaded, load: load, docs: map[string]swagger.Document{}, diffs: map[string][]swagger.Change{}}
+	for _, service := range services {
+		for _, d := range service.Dependencies {
+			if d.ServiceId != provider {
+				continue
+			}
+			r, ok := semver.ParseRange(d.Range)
+			if !ok {
+				continue
+			}
+			warning := Warning{Consumer: service.Id, ConsumerName: service.Servicename, Range: d.Range}
+
+			if !r.Contains(v) {
+				highest := Resolve(d.Range, versions)
+				if highest == "" || semver.Compare(version, highest) < 0 {
+					continue
+				}
+				doc, breaking, err := c.breaking(highest)
+				if err != nil {
+					return nil, err
+				}
+				warning.Reason = ReasonOutOfRange
+				warning.Previous = highest
+				warning.Impacts, warning.Changes = affected(doc, d.Operations, breaking)
+				warning.Message = fmt.Sprintf("%s is out of the range %s of %s", version, d.Range, consumerName(service))
+				warnings = append(warnings, warning)
+				continue
+			}
+
+			// the highest version of the range up to the uploaded one
+			previous := ""
+			for _, other := range versions {
+				o, ok := semver.Parse(other)
+				if ok && r.Contains(o) && o.Compare(v) <= 0 && (previous == "" || semver.Compare(other, previous) > 0) {
+					previous = other
+				}
+			}
+			if previous == "" {
+				continue
+			}
+			doc, breaking, err := c.breaking(previous)
+			if err != nil {
+				return nil, err
+			}
+			warning.Impacts, warning.Changes = affected(doc, d.Operations, breaking)
+			if len(warning.Changes) == 0 {
+				continue
+			}
+			warning.Reason = ReasonBreaking
+			warning.Previous = previous
+			warning.Message = fmt.Sprintf("%s has %d breaking changes from %s in the range %s of %s", version, len(warning.Changes), previous, d.Range, consumerName(service))
+			if len(warning.Impacts) > 0 {
+				operations := []string{}
+				for _, impact := range warning.Impacts {
+					operations = append(operations, impact.Operation)
+				}
+				warning.Message += ", affecting " + strings.Join(operations, ", ")
+			}
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings, nil
+}
+
+func consumerName(service servicedb.ServiceEntity) string {
+	if service.Servicename != "" {
+		return service.Servicename
+	}
+	return service.Id
+}
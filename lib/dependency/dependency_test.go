@@ -0,0 +1,186 @@
+package dependency
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var services = []servicedb.ServiceEntity{
+	{Id: "pets", Servicename: "Pets"},
+	{Id: "web", Servicename: "Web", Dependencies: []servicedb.Dependency{
+		{ServiceId: "pets", Range: "^1.0.0"},
+		{ServiceId: "users", Range: "*"},
+	}},
+	{Id: "batch", Servicename: "Batch", Dependencies: []servicedb.Dependency{
+		{ServiceId: "pets", Range: "~2.1.0"},
+	}},
+}
+
+func document(t *testing.T, version string, paths ...string) swagger.Document {
+	contents := fmt.Sprintf("openapi: 3.0.0\ninfo: {title: pets, version: %s}\npaths:\n", version)
+	for _, p := range paths {
+		contents += fmt.Sprintf("  %s: {get: {responses: {'200': {description: ok}}}}\n", p)
+	}
+	doc, err := swagger.Parse(common.Yml, contents)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	return doc
+}
+
+func TestGraph(t *testing.T) {
+	graph := New(services, map[string][]string{"pets": {"1.0.0", "1.3.0", "2.0.0"}})
+	if len(graph.Nodes) != 3 || graph.Nodes[0].Id != "batch" || len(graph.Edges) != 3 {
+		t.Fatalf("invalid graph %+v", graph)
+	}
+	batch, web, users := graph.Edges[0], graph.Edges[1], graph.Edges[2]
+	if batch.Satisfied || batch.Resolved != "" {
+		t.Errorf("invalid edge %+v", batch)
+	}
+	if !web.Satisfied || web.Resolved != "1.3.0" || web.ProviderName != "Pets" {
+		t.Errorf("invalid edge %+v", web)
+	}
+	if users.Satisfied || users.ProviderName != "" {
+		t.Errorf("invalid edge %+v", users)
+	}
+
+	consumers := graph.Consumers("pets")
+	if len(consumers) != 2 || consumers[0].Consumer != "batch" || consumers[1].Consumer != "web" {
+		t.Errorf("invalid consumers %+v", consumers)
+	}
+	if providers := Providers(services); strings.Join(providers, ",") != "pets,users" {
+		t.Errorf("invalid providers %v", providers)
+	}
+
+	dot := graph.DOT()
+	for _, part := range []string{
+		"digraph dependencies {\n",
+		"  \"pets\" [label=\"Pets\"];\n",
+		"  \"users\" [label=\"users (missing)\", style=dashed];\n",
+		"  \"web\" -> \"pets\" [label=\"^1.0.0 → 1.3.0\"];\n",
+		"  \"batch\" -> \"pets\" [label=\"~2.1.0\", color=red, style=dashed];\n",
+	} {
+		if !strings.Contains(dot, part) {
+			t.Errorf("%q is missing\n%s", part, dot)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	docs := map[string]swagger.Document{
+		"1.0.0": document(t, "1.0.0", "/pets"),
+		"1.1.0": document(t, "1.1.0", "/pets", "/owners"),
+	}
+	loaded := []string{}
+	load := func(version string) (swagger.Document, error) {
+		loaded = append(loaded, version)
+		return docs[version], nil
+	}
+
+	// /owners is removed from 1.1.0
+	warnings, err := Check(services, "pets", document(t, "1.2.0", "/pets"), []string{"1.0.0", "1.1.0"}, load)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Consumer != "web" || warnings[0].Reason != ReasonBreaking || warnings[0].Previous != "1.1.0" || len(warnings[0].Changes) != 1 {
+		t.Fatalf("invalid warnings %+v", warnings)
+	}
+	if len(loaded) != 1 {
+		t.Errorf("invalid loads %v", loaded)
+	}
+
+	// compatible
+	if warnings, _ := Check(services, "pets", document(t, "1.2.0", "/pets", "/owners", "/stores"), []string{"1.0.0", "1.1.0"}, load); len(warnings) != 0 {
+		t.Errorf("invalid warnings %+v", warnings)
+	}
+
+	// 2.0.0 is above ^1.0.0, batch has no version in ~2.1.0 yet
+	warnings, _ = Check(services, "pets", document(t, "2.0.0"), []string{"1.0.0", "1.1.0"}, load)
+	if len(warnings) != 1 || warnings[0].Consumer != "web" || warnings[0].Reason != ReasonOutOfRange || warnings[0].Previous != "1.1.0" {
+		t.Fatalf("invalid warnings %+v", warnings)
+	}
+
+	// other providers and non semantic versions are not checked
+	for _, provider := range []string{"users", "stores"} {
+		if warnings, _ := Check(services, provider, document(t, "draft"), []string{"1.0.0"}, load); len(warnings) != 0 {
+			t.Errorf("invalid warnings %+v", warnings)
+		}
+	}
+}
+
+var store = `
+openapi: 3.0.0
+info: {title: store, version: %s}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+  /orders:
+    post:
+      operationId: createOrder
+      parameters: [{name: %s, in: query, required: true, schema: {type: string}}]
+      responses: {'201': {description: created}}
+components:
+  schemas:
+    Pet: {type: object, properties: {owner: {$ref: '#/components/schemas/Owner'}}}
+    Owner: {type: object, properties: {%s: {type: string}}}
+`
+
+func TestImpacts(t *testing.T) {
+	parse := func(version string, param string, property string) swagger.Document {
+		doc, err := swagger.Parse(common.Yml, fmt.Sprintf(store, version, param, property))
+		if err != nil {
+			t.Fatalf("failed test %#v", err)
+		}
+		return doc
+	}
+	consumers := []servicedb.ServiceEntity{
+		{Id: "web", Dependencies: []servicedb.Dependency{{ServiceId: "store", Range: "^1.0.0", Operations: []string{"get /pets"}}}},
+		{Id: "shop", Dependencies: []servicedb.Dependency{{ServiceId: "store", Range: "^1.0.0", Operations: []string{"createOrder"}}}},
+		{Id: "batch", Dependencies: []servicedb.Dependency{{ServiceId: "store", Range: "^1.0.0"}}},
+	}
+	load := func(version string) (swagger.Document, error) {
+		return parse("1.0.0", "id", "name"), nil
+	}
+
+	// the renamed property of Owner breaks GET /pets through Pet
+	warnings, err := Check(consumers, "store", parse("1.1.0", "id", "fullname"), []string{"1.0.0"}, load)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if len(warnings) != 2 || warnings[0].Consumer != "web" || warnings[1].Consumer != "batch" {
+		t.Fatalf("invalid warnings %+v", warnings)
+	}
+	if impacts := warnings[0].Impacts; len(impacts) != 1 || impacts[0].Operation != "get /pets" || len(impacts[0].Changes) != 1 || impacts[0].Changes[0].Name != "Owner.name" {
+		t.Errorf("invalid impacts %+v", impacts)
+	}
+	if !strings.HasSuffix(warnings[0].Message, "affecting get /pets") {
+		t.Errorf("invalid message %s", warnings[0].Message)
+	}
+	if len(warnings[1].Impacts) != 0 || len(warnings[1].Changes) != 1 {
+		t.Errorf("invalid warning %+v", warnings[1])
+	}
+
+	// the new required parameter breaks createOrder only
+	warnings, _ = Check(consumers, "store", parse("1.1.0", "token", "name"), []string{"1.0.0"}, load)
+	if len(warnings) != 2 || warnings[0].Consumer != "shop" || warnings[0].Impacts[0].Operation != "createOrder" {
+		t.Fatalf("invalid warnings %+v", warnings)
+	}
+
+	for operation, valid := range map[string]bool{"GET /pets": true, "listPets": true, "FETCH /pets": false, "GET pets": false, "/pets": false, "": false} {
+		if ValidOperation(operation) != valid {
+			t.Errorf("%q must be %t", operation, valid)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package dependency builds the graph of the services consuming other services
+// and checks uploaded versions against the version ranges of their consumers.
+package dependency
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/semver"
+)
+
+// Node is a service of the graph
+type Node struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Edge is a dependency of a consumer on a provider
+type Edge struct {
+	Consumer     string   `json:"consumer"`
+	ConsumerName string   `json:"consumerName"`
+	Provider     string   `json:"provider"`
+	ProviderName string   `json:"providerName"` // "" if the provider does not exist
+	Range        string   `json:"range"`
+	Resolved     string   `json:"resolved,omitempty"`   // the highest version of the provider in the range
+	Satisfied    bool     `json:"satisfied"`            // a version of the provider is in the range
+	Operations   []string `json:"operations,omitempty"` // operations called by the consumer
+}
+
+// Graph is the dependency graph of services. Nodes and edges are sorted by id.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// New builds the graph of services. versions are the versions of the providers
+// which can be consumed (enabled ones) by service id.
+func New(services []servicedb.ServiceEntity, versions map[string][]string) Graph {
+	names := map[string]string{}
+	graph := Graph{Nodes: []Node{}, Edges: []Edge{}}
+	for _, service := range services {
+		names[service.Id] = service.Servicename
+		graph.Nodes = append(graph.Nodes, Node{Id: service.Id, Name: service.Servicename})
+	}
+	for _, service := range services {
+		for _, d := range service.Dependencies {
+			edge := Edge{
+				Consumer:     service.Id,
+				ConsumerName: service.Servicename,
+				Provider:     d.ServiceId,
+				ProviderName: names[d.ServiceId],
+				Range:        d.Range,
+				Operations:   d.Operations,
+			}
+			edge.Resolved = Resolve(d.Range, versions[d.ServiceId])
+			edge.Satisfied = edge.Resolved != ""
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].Id < graph.Nodes[j].Id
+	})
+	sort.SliceStable(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.Consumer != b.Consumer {
+			return a.Consumer < b.Consumer
+		}
+		return a.Provider < b.Provider
+	})
+	return graph
+}
+
+// Resolve returns the highest version in the range, or "" if the range is invalid or contains none of them
+func Resolve(rng string, versions []string) string {
+	r, ok := semver.ParseRange(rng)
+	if !ok {
+		return ""
+	}
+	resolved := ""
+	for _, v := range versions {
+		if r.ContainsString(v) && (resolved == "" || semver.Compare(v, resolved) > 0) {
+			resolved = v
+		}
+	}
+	return resolved
+}
+
+// Providers returns the ids of the services consumed by services
+func Providers(services []servicedb.ServiceEntity) []string {
+	seen := map[string]bool{}
+	ids := []string{}
+	for _, service := range services {
+		for _, d := range service.Dependencies {
+			if !seen[d.ServiceId] {
+				seen[d.ServiceId] = true
+				ids = append(ids, d.ServiceId)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Consumers returns the edges of the consumers of provider
+func (graph Graph) Consumers(provider string) []Edge {
+	edges := []Edge{}
+	for _, edge := range graph.Edges {
+		if edge.Provider == provider {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// DOT renders the graph in the Graphviz DOT language. Unsatisfied dependencies are red and dashed.
+func (graph Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	known := map[string]bool{}
+	for _, node := range graph.Nodes {
+		known[node.Id] = true
+		label := node.Name
+		if label == "" {
+			label = node.Id
+		}
+		fmt.Fprintf(&b, "  %s [label=%s];\n", strconv.Quote(node.Id), strconv.Quote(label))
+	}
+	for _, edge := range graph.Edges {
+		if !known[edge.Provider] {
+			known[edge.Provider] = true
+			fmt.Fprintf(&b, "  %s [label=%s, style=dashed];\n", strconv.Quote(edge.Provider), strconv.Quote(edge.Provider+" (missing)"))
+		}
+	}
+	for _, edge := range graph.Edges {
+		label := edge.Range
+		attrs := ""
+		if edge.Satisfied {
+			label += " → " + edge.Resolved
+		} else {
+			attrs = ", color=red, style=dashed"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=%s%s];\n", strconv.Quote(edge.Consumer), strconv.Quote(edge.Provider), strconv.Quote(label), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package dependency
+
+import (
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// Impact is a registered operation of a consumer affected by breaking changes
+type Impact struct {
+	Operation string           `json:"operation"` // as registered: "METHOD /path" or an operationId
+	Changes   []swagger.Change `json:"changes"`
+}
+
+// ValidOperation returns true if operation is "METHOD /path" or an operationId
+func ValidOperation(operation string) bool {
+	if operation == "" || strings.TrimSpace(operation) != operation {
+		return false
+	}
+	fields := strings.Fields(operation)
+	switch len(fields) {
+	case 1:
+		return !strings.HasPrefix(operation, "/")
+	case 2:
+		for _, method := range swagger.Methods {
+			if strings.ToLower(fields[0]) == method {
+				return strings.HasPrefix(fields[1], "/")
+			}
+		}
+	}
+	return false
+}
+
+// affected returns the impacts of breaking changes of doc on the registered operations of a consumer,
+// and the changes affecting them. A consumer without registered operations is affected by all changes.
+// Schema changes affect the operations referencing the schema directly or through other schemas.
+func affected(doc swagger.Document, operations []string, changes []swagger.Change) ([]Impact, []swagger.Change) {
+	if len(operations) == 0 {
+		return nil, changes
+	}
+	ops := map[string]swagger.Operation{}
+	for _, op := range doc.Operations() {
+		ops[op.Key()] = op
+		if op.OperationId != "" {
+			ops[op.OperationId] = op
+		}
+	}
+
+	impacts := []Impact{}
+	seen := map[int]bool{}
+	all := []swagger.Change{}
+	for _, registered := range operations {
+		key := registered
+		if fields := strings.Fields(registered); len(fields) == 2 {
+			key = strings.ToUpper(fields[0]) + " " + fields[1]
+		}
+		op, ok := ops[key]
+		if !ok {
+			continue
+		}
+		var schemas map[string]bool
+		impact := Impact{Operation: registered, Changes: []swagger.Change{}}
+		for i, c := range changes {
+			hit := false
+			switch c.Target {
+			case swagger.TargetSchema, swagger.TargetProperty:
+				if schemas == nil {
+					schemas = referencedSchemas(doc, op)
+				}
+				hit = schemas[strings.SplitN(c.Name, ".", 2)[0]]
+			default:
+				hit = strings.ToUpper(c.Method)+" "+c.Path == op.Key()
+			}
+			if !hit {
+				continue
+			}
+			impact.Changes = append(impact.Changes, c)
+			if !seen[i] {
+				seen[i] = true
+				all = append(all, c)
+			}
+		}
+		if len(impact.Changes) > 0 {
+			impacts = append(impacts, impact)
+		}
+	}
+	return impacts, all
+}
+
+// referencedSchemas returns the names of the schemas referenced by an operation
+func referencedSchemas(doc swagger.Document, op swagger.Operation) map[string]bool {
+	prefix := doc.SchemaRefPrefix()
+	schemas := map[string]bool{}
+	visited := map[string]bool{}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok && !visited[ref] {
+				visited[ref] = true
+				if strings.HasPrefix(ref, prefix) {
+					schemas[strings.Replace(strings.Replace(strings.TrimPrefix(ref, prefix), "~1", "/", -1), "~0", "~", -1)] = true
+				}
+				walk(doc.Resolve(ref))
+			}
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(op.Raw)
+	for _, p := range op.Parameters {
+		walk(p.Raw)
+	}
+	return schemas
+}
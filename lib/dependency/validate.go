@@ -0,0 +1,33 @@
+package dependency
+
+import (
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/semver"
+)
+
+// Validate validates the dependencies of a service: providers are other services declared once,
+// with valid ranges and operations. Empty ranges must be replaced by "*" before.
+func Validate(serviceId string, dependencies []servicedb.Dependency) error {
+	seen := map[string]bool{}
+	for _, d := range dependencies {
+		switch {
+		case d.ServiceId == "":
+			return common.NewError(25001, "serviceId is required", nil)
+		case d.ServiceId == serviceId:
+			return common.NewError(25001, "a service can not depend on itself", nil)
+		case seen[d.ServiceId]:
+			return common.NewError(25001, "duplicated dependency on "+d.ServiceId, nil)
+		}
+		if _, ok := semver.ParseRange(d.Range); !ok {
+			return common.NewError(25001, "invalid range "+d.Range, nil)
+		}
+		for _, operation := range d.Operations {
+			if !ValidOperation(operation) {
+				return common.NewError(25001, "invalid operation "+operation+" (METHOD /path or operationId)", nil)
+			}
+		}
+		seen[d.ServiceId] = true
+	}
+	return nil
+}
@@ -0,0 +1,428 @@
+// Package docs renders a document as a self-contained HTML reference.
+// Pages embed their styles and use no scripts or external assets, so they work offline.
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/mock"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// Untagged is the group of operations without tags
+const Untagged = "default"
+
+var nonAnchorChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Page is the view of a document
+type Page struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []string
+	Groups      []Group
+	Schemas     []Schema
+}
+
+// Group is the operations of a tag
+type Group struct {
+	Name        string
+	Anchor      string
+	Description string
+	Operations  []Operation
+}
+
+// Operation is the view of an operation
+type Operation struct {
+	Anchor      string
+	Method      string // uppercase
+	Path        string
+	OperationId string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Security    []string // "name [scopes]" of the alternative requirements
+	Parameters  []Parameter
+	Bodies      []Body // the request body for each media type
+	Responses   []Response
+}
+
+// Parameter is the view of a parameter or a property
+type Parameter struct {
+	Name        string
+	In          string
+	Type        Type
+	Required    bool
+	Deprecated  bool
+	Description string
+}
+
+// Type is a schema type. Anchor links named schemas.
+type Type struct {
+	Label  string
+	Anchor string
+}
+
+// Body is a request or response body of a media type
+type Body struct {
+	MediaType string
+	Type      Type
+	Example   string
+}
+
+// Response is the view of a response
+type Response struct {
+	Code        string
+	Description string
+	Bodies      []Body
+}
+
+// Schema is the view of a named schema
+type Schema struct {
+	Name        string
+	Anchor      string
+	Type        Type
+	Description string
+	Properties  []Parameter
+	Example     string
+}
+
+type builder struct {
+	doc     swagger.Document
+	anchors map[string]bool
+	// anchors of named schemas
+	schemaAnchors map[string]string
+}
+
+// NewPage builds the view of a document. Operations are grouped by their first tag
+// in the order of the tags of the document, then by name.
+func NewPage(doc swagger.Document) Page {
+	b := &builder{doc: doc, anchors: map[string]bool{}, schemaAnchors: map[string]string{}}
+	info := swagger.Map(doc, "info")
+	page := Page{
+		Title:       doc.Title(),
+		Version:     doc.Version(),
+		Description: swagger.String(info, "description"),
+		Servers:     b.servers(),
+		Groups:      []Group{},
+		Schemas:     []Schema{},
+	}
+	if page.Title == "" {
+		page.Title = "API reference"
+	}
+
+	// schema anchors are reserved first, so that types can link them
+	schemas := doc.Schemas()
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.schemaAnchors[name] = b.anchor("schema-" + name)
+	}
+	for _, name := range names {
+		schema, _ := schemas[name].(map[string]interface{})
+		page.Schemas = append(page.Schemas, Schema{
+			Name:        name,
+			Anchor:      b.schemaAnchors[name],
+			Type:        b.typeOf(schema),
+			Description: swagger.String(schema, "description"),
+			Properties:  b.properties(schema),
+			Example:     b.example(nil, false, schema),
+		})
+	}
+
+	groups := map[string]*Group{}
+	order := []string{}
+	tagDescriptions := map[string]string{}
+	for _, t := range swagger.Slice(doc, "tags") {
+		tag, _ := t.(map[string]interface{})
+		if name := swagger.String(tag, "name"); name != "" {
+			tagDescriptions[name] = swagger.String(tag, "description")
+			order = append(order, name)
+		}
+	}
+	for _, op := range doc.Operations() {
+		name := Untagged
+		if len(op.Tags) > 0 {
+			name = op.Tags[0]
+		}
+		if _, ok := groups[name]; !ok {
+			groups[name] = &Group{Name: name, Description: tagDescriptions[name]}
+		}
+		groups[name].Operations = append(groups[name].Operations, b.operation(op))
+	}
+	var rest []string
+	for name := range groups {
+		if _, ok := tagDescriptions[name]; !ok {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range append(order, rest...) {
+		if group, ok := groups[name]; ok {
+			group.Anchor = b.anchor("tag-" + name)
+			page.Groups = append(page.Groups, *group)
+		}
+	}
+	return page
+}
+
+// anchor returns a unique anchor
+func (this *builder) anchor(name string) string {
+	base := strings.Trim(nonAnchorChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	anchor := base
+	for i := 2; this.anchors[anchor]; i++ {
+		anchor = fmt.Sprintf("%s-%d", base, i)
+	}
+	this.anchors[anchor] = true
+	return anchor
+}
+
+func (this *builder) servers() []string {
+	if this.doc.IsOpenAPI3() {
+		servers := []string{}
+		for _, s := range swagger.Slice(this.doc, "servers") {
+			server, _ := s.(map[string]interface{})
+			if u := swagger.String(server, "url"); u != "" {
+				servers = append(servers, u)
+			}
+		}
+		return servers
+	}
+	host := swagger.String(this.doc, "host")
+	if host == "" {
+		return []string{}
+	}
+	schemes := swagger.Strings(this.doc, "schemes")
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	servers := []string{}
+	for _, scheme := range schemes {
+		servers = append(servers, scheme+"://"+host+swagger.String(this.doc, "basePath"))
+	}
+	return servers
+}
+
+func (this *builder) operation(op swagger.Operation) Operation {
+	view := Operation{
+		Anchor:      this.anchor("operation-" + op.Method + "-" + op.Path),
+		Method:      strings.ToUpper(op.Method),
+		Path:        op.Path,
+		OperationId: op.OperationId,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  op.Deprecated,
+		Security:    []string{},
+		Parameters:  []Parameter{},
+		Bodies:      []Body{},
+		Responses:   []Response{},
+	}
+	for _, requirement := range op.Security {
+		names := make([]string, 0, len(requirement))
+		for name, scopes := range requirement {
+			if len(scopes) > 0 {
+				name += " [" + strings.Join(scopes, ", ") + "]"
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) > 0 {
+			view.Security = append(view.Security, strings.Join(names, " + "))
+		}
+	}
+
+	for _, p := range op.Parameters {
+		if p.In == "body" {
+			schema := swagger.Map(p.Raw, "schema")
+			for _, mediaType := range this.consumes(op) {
+				view.Bodies = append(view.Bodies, Body{MediaType: mediaType, Type: this.typeOf(schema), Example: this.example(nil, false, schema)})
+			}
+			continue
+		}
+		schema := swagger.Map(p.Raw, "schema")
+		if schema == nil {
+			// 2.0 parameters other than body have their type at the top level
+			schema = p.Raw
+		}
+		view.Parameters = append(view.Parameters, Parameter{
+			Name:        p.Name,
+			In:          p.In,
+			Type:        this.typeOf(schema),
+			Required:    p.Required,
+			Deprecated:  swagger.Bool(p.Raw, "deprecated"),
+			Description: swagger.String(p.Raw, "description"),
+		})
+	}
+	view.Bodies = append(view.Bodies, this.contentBodies(swagger.Map(op.RequestBody, "content"))...)
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	// ranges (2XX) follow the codes they cover and default comes last
+	sort.Strings(codes)
+	for _, code := range codes {
+		response, _ := this.doc.Deref(op.Responses[code]).(map[string]interface{})
+		r := Response{Code: code, Description: swagger.String(response, "description"), Bodies: []Body{}}
+		if this.doc.IsOpenAPI3() {
+			r.Bodies = this.contentBodies(swagger.Map(response, "content"))
+		} else if schema := swagger.Map(response, "schema"); schema != nil {
+			examples := swagger.Map(response, "examples")
+			for _, mediaType := range this.produces(op) {
+				example, ok := examples[mediaType]
+				r.Bodies = append(r.Bodies, Body{MediaType: mediaType, Type: this.typeOf(schema), Example: this.example(example, ok, schema)})
+			}
+		}
+		view.Responses = append(view.Responses, r)
+	}
+	return view
+}
+
+// contentBodies returns the bodies of a content object (3.0)
+func (this *builder) contentBodies(content map[string]interface{}) []Body {
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	bodies := []Body{}
+	for _, t := range types {
+		media, _ := content[t].(map[string]interface{})
+		schema := swagger.Map(media, "schema")
+		example, ok := media["example"]
+		if examples := swagger.Map(media, "examples"); !ok && len(examples) > 0 {
+			names := make([]string, 0, len(examples))
+			for name := range examples {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			e, _ := this.doc.Deref(examples[names[0]]).(map[string]interface{})
+			example, ok = e["value"]
+		}
+		bodies = append(bodies, Body{MediaType: t, Type: this.typeOf(schema), Example: this.example(example, ok, schema)})
+	}
+	return bodies
+}
+
+func (this *builder) consumes(op swagger.Operation) []string {
+	if types := swagger.Strings(op.Raw, "consumes"); len(types) > 0 {
+		return types
+	}
+	if types := swagger.Strings(this.doc, "consumes"); len(types) > 0 {
+		return types
+	}
+	return []string{"application/json"}
+}
+
+func (this *builder) produces(op swagger.Operation) []string {
+	if types := swagger.Strings(op.Raw, "produces"); len(types) > 0 {
+		return types
+	}
+	if types := swagger.Strings(this.doc, "produces"); len(types) > 0 {
+		return types
+	}
+	return []string{"application/json"}
+}
+
+// properties returns the properties of an object schema, including those of allOf
+func (this *builder) properties(schema map[string]interface{}) []Parameter {
+	params := []Parameter{}
+	for _, s := range swagger.Slice(schema, "allOf") {
+		sub, _ := s.(map[string]interface{})
+		// referenced schemas are documented on their own
+		if swagger.String(sub, "$ref") == "" {
+			params = append(params, this.properties(sub)...)
+		}
+	}
+	required := swagger.Strings(schema, "required")
+	properties := swagger.Map(schema, "properties")
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, _ := properties[name].(map[string]interface{})
+		params = append(params, Parameter{
+			Name:        name,
+			Type:        this.typeOf(property),
+			Required:    swagger.Contains(required, name),
+			Deprecated:  swagger.Bool(property, "deprecated"),
+			Description: swagger.String(property, "description"),
+		})
+	}
+	return params
+}
+
+// typeOf returns the label of a schema type, linking named schemas
+func (this *builder) typeOf(schema map[string]interface{}) Type {
+	if schema == nil {
+		return Type{}
+	}
+	if ref := swagger.String(schema, "$ref"); ref != "" {
+		prefix := this.doc.SchemaRefPrefix()
+		if strings.HasPrefix(ref, prefix) {
+			name := strings.TrimPrefix(ref, prefix)
+			return Type{Label: name, Anchor: this.schemaAnchors[name]}
+		}
+		return Type{Label: ref}
+	}
+	switch typ := swagger.String(schema, "type"); typ {
+	case "array":
+		item := this.typeOf(swagger.Map(schema, "items"))
+		if item.Label == "" {
+			return Type{Label: "array"}
+		}
+		return Type{Label: "array of " + item.Label, Anchor: item.Anchor}
+	case "":
+		for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+			if len(swagger.Slice(schema, key)) > 0 {
+				return Type{Label: key}
+			}
+		}
+		if swagger.Map(schema, "properties") != nil {
+			return Type{Label: "object"}
+		}
+		return Type{}
+	default:
+		if format := swagger.String(schema, "format"); format != "" {
+			typ += " (" + format + ")"
+		}
+		if enum := swagger.Slice(schema, "enum"); len(enum) > 0 {
+			values := make([]string, len(enum))
+			for i, e := range enum {
+				values[i] = fmt.Sprint(e)
+			}
+			typ += ": " + strings.Join(values, ", ")
+		}
+		return Type{Label: typ}
+	}
+}
+
+// example returns the example as indented JSON, or one synthesized from the schema
+func (this *builder) example(example interface{}, ok bool, schema map[string]interface{}) string {
+	if !ok {
+		if schema == nil {
+			return ""
+		}
+		example = mock.Example(this.doc, schema)
+	}
+	if s, isString := example.(string); isString && ok {
+		return s
+	}
+	if example == nil {
+		return ""
+	}
+	b, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,165 @@
+package docs
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+func parse(t *testing.T, contents string) swagger.Document {
+	doc, err := swagger.Parse(common.Yml, contents)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	return doc
+}
+
+var petstore = `
+openapi: 3.0.0
+info: {title: petstore, version: 1.0.0, description: "<b>pets</b>"}
+servers:
+  - url: https://api.example.com/v1
+tags:
+  - {name: stores}
+  - {name: pets, description: Everything about pets}
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      summary: List pets
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, format: int32}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+        default: {$ref: '#/components/responses/Error'}
+  /pets/{petId}:
+    delete:
+      tags: [pets]
+      deprecated: true
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: integer}}
+      responses: {'204': {description: deleted}}
+  /health:
+    get:
+      responses: {'200': {description: ok}}
+components:
+  responses:
+    Error:
+      description: error
+      content:
+        application/json:
+          example: {message: not found}
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, example: dog}
+        owner: {$ref: '#/components/schemas/pet'}
+    pet: {type: object}
+`
+
+func TestNewPage(t *testing.T) {
+	page := NewPage(parse(t, petstore))
+
+	// tags of the document first (without operations, stores is omitted), then the others
+	if len(page.Groups) != 2 || page.Groups[0].Name != "pets" || page.Groups[0].Description != "Everything about pets" || page.Groups[1].Name != Untagged {
+		t.Fatalf("invalid groups %+v", page.Groups)
+	}
+	list := page.Groups[0].Operations[0]
+	if list.Anchor != "operation-get-pets" || list.Parameters[0].Type.Label != "integer (int32)" {
+		t.Fatalf("invalid operation %+v", list)
+	}
+	if len(list.Responses) != 2 || list.Responses[1].Code != "default" || list.Responses[1].Bodies[0].Example != "{\n  \"message\": \"not found\"\n}" {
+		t.Fatalf("invalid responses %+v", list.Responses)
+	}
+	if typ := list.Responses[0].Bodies[0].Type; typ.Label != "array of Pet" || typ.Anchor != "schema-pet" {
+		t.Fatalf("invalid type %+v", typ)
+	}
+	if !page.Groups[0].Operations[1].Deprecated || page.Groups[0].Operations[1].Anchor != "operation-delete-pets-petid" {
+		t.Fatalf("invalid operation %+v", page.Groups[0].Operations[1])
+	}
+
+	// anchors are unique
+	if len(page.Schemas) != 2 || page.Schemas[0].Anchor != "schema-pet" || page.Schemas[1].Anchor != "schema-pet-2" {
+		t.Fatalf("invalid schemas %+v", page.Schemas)
+	}
+	if owner := page.Schemas[0].Properties[1]; owner.Type.Anchor != "schema-pet-2" || !page.Schemas[0].Properties[0].Required {
+		t.Fatalf("invalid properties %+v", page.Schemas[0].Properties)
+	}
+}
+
+func TestRender(t *testing.T) {
+	html, err := Render(parse(t, petstore))
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	for _, part := range []string{
+		`<title>petstore 1.0.0</title>`,
+		`&lt;b&gt;pets&lt;/b&gt;`,
+		`<a href="#operation-get-pets"><span class="method get">GET</span> /pets</a>`,
+		`<div class="operation" id="schema-pet">`,
+		`<a href="#schema-pet">array of Pet</a>`,
+	} {
+		if !strings.Contains(html, part) {
+			t.Errorf("%q is missing", part)
+		}
+	}
+	// no external assets
+	for _, part := range []string{"<script", "<link", "src=", "http://", "@import"} {
+		if strings.Contains(html, part) {
+			t.Errorf("%q must not be used", part)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	page := NewPage(parse(t, petstore))
+	md := page.Markdown()
+	for _, part := range []string{
+		"# petstore 1.0.0\n",
+		"- [pets](#tag-pets)\n",
+		"<a id=\"operation-get-pets\"></a>\n\n### `GET /pets`\n\n**List pets**\n",
+		"| `limit` | query | integer (int32) | no |  |\n",
+		"| `200` | ok | `application/json` [array of Pet](#schema-pet) |\n",
+		"Example `default` `application/json`:\n\n```json\n{\n  \"message\": \"not found\"\n}\n```\n",
+		"### `DELETE /pets/{petId}` (deprecated)\n",
+		"| `owner` | [pet](#schema-pet-2) | no |  |\n",
+	} {
+		if !strings.Contains(md, part) {
+			t.Errorf("%q is missing\n%s", part, md)
+		}
+	}
+
+	files := page.MarkdownFiles()
+	if len(files) != 4 || files["tag-pets.md"] == "" || files["tag-default.md"] == "" {
+		t.Fatalf("invalid files %v", files)
+	}
+	if !strings.Contains(files[IndexFile], "- [pets](tag-pets.md) (2 operations)\n") || !strings.Contains(files[IndexFile], "- [Schemas](schemas.md)\n") {
+		t.Errorf("invalid index\n%s", files[IndexFile])
+	}
+	if !strings.HasPrefix(files["tag-pets.md"], "# pets\n") || !strings.Contains(files["tag-pets.md"], "[array of Pet](schemas.md#schema-pet)") {
+		t.Errorf("schemas must be linked across files\n%s", files["tag-pets.md"])
+	}
+	if !strings.Contains(files[SchemasFile], "| `owner` | [pet](#schema-pet-2) | no |  |\n") {
+		t.Errorf("invalid schemas\n%s", files[SchemasFile])
+	}
+
+	data, err := swagger.WriteZip(files)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil || len(r.File) != 4 || r.File[0].Name != IndexFile {
+		t.Fatalf("invalid zip %v", err)
+	}
+}
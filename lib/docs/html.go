@@ -0,0 +1,171 @@
+package docs
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var htmlTemplate = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}{{with .Version}} {{.}}{{end}}</title>
+<style>
+body { font-family: sans-serif; margin: 0; color: #24292e; }
+nav { position: fixed; top: 0; bottom: 0; left: 0; width: 260px; overflow-y: auto; padding: 16px; background: #f6f8fa; border-right: 1px solid #e1e4e8; box-sizing: border-box; font-size: 90%; }
+nav ul { list-style: none; padding-left: 8px; }
+nav a { color: inherit; text-decoration: none; }
+main { margin-left: 260px; padding: 16px 32px; max-width: 960px; }
+a { color: #0366d6; }
+.description { white-space: pre-wrap; }
+.operation { border: 1px solid #e1e4e8; border-radius: 4px; padding: 8px 16px; margin: 16px 0; }
+.method { display: inline-block; min-width: 56px; text-align: center; color: #fff; background: #6a737d; border-radius: 3px; padding: 2px 4px; font-size: 80%; font-weight: bold; }
+.method.get { background: #2188ff; } .method.post { background: #28a745; } .method.put { background: #f66a0a; }
+.method.patch { background: #6f42c1; } .method.delete { background: #d73a49; }
+.path { font-family: monospace; font-size: 110%; }
+.deprecated { color: #fff; background: #6a737d; border-radius: 3px; padding: 0 4px; font-size: 80%; }
+.required { color: #d73a49; font-size: 80%; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0; }
+th, td { text-align: left; vertical-align: top; border-bottom: 1px solid #e1e4e8; padding: 4px 8px; }
+pre { background: #f6f8fa; padding: 8px; overflow-x: auto; }
+code { font-family: monospace; }
+</style>
+</head>
+<body>
+<nav>
+<strong><a href="#top">{{.Title}}</a></strong>
+<ul>
+{{- range .Groups}}
+<li><a href="#{{.Anchor}}">{{.Name}}</a>
+<ul>
+{{- range .Operations}}
+<li><a href="#{{.Anchor}}"><span class="method {{lower .Method}}">{{.Method}}</span> {{.Path}}</a></li>
+{{- end}}
+</ul>
+</li>
+{{- end}}
+{{- if .Schemas}}
+<li><a href="#schemas">Schemas</a>
+<ul>
+{{- range .Schemas}}
+<li><a href="#{{.Anchor}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+</li>
+{{- end}}
+</ul>
+</nav>
+<main>
+<h1 id="top">{{.Title}}{{with .Version}} <small>{{.}}</small>{{end}}</h1>
+{{- with .Description}}
+<p class="description">{{.}}</p>
+{{- end}}
+{{- with .Servers}}
+<p>Servers:{{range .}} <code>{{.}}</code>{{end}}</p>
+{{- end}}
+{{- range .Groups}}
+<section>
+<h2 id="{{.Anchor}}">{{.Name}}</h2>
+{{- with .Description}}
+<p class="description">{{.}}</p>
+{{- end}}
+{{- range .Operations}}
+<div class="operation" id="{{.Anchor}}">
+<h3><a href="#{{.Anchor}}" class="method {{lower .Method}}">{{.Method}}</a> <span class="path">{{.Path}}</span>{{if .Deprecated}} <span class="deprecated">deprecated</span>{{end}}</h3>
+{{- with .Summary}}
+<p><strong>{{.}}</strong></p>
+{{- end}}
+{{- with .Description}}
+<p class="description">{{.}}</p>
+{{- end}}
+{{- with .OperationId}}
+<p>operationId: <code>{{.}}</code></p>
+{{- end}}
+{{- with .Security}}
+<p>Security:{{range $i, $s := .}}{{if $i}} or{{end}} <code>{{$s}}</code>{{end}}</p>
+{{- end}}
+{{- with .Parameters}}
+<h4>Parameters</h4>
+<table>
+<tr><th>Name</th><th>In</th><th>Type</th><th>Description</th></tr>
+{{- range .}}
+{{template "parameter" .}}
+{{- end}}
+</table>
+{{- end}}
+{{- with .Bodies}}
+<h4>Request body</h4>
+{{- range .}}
+{{template "body" .}}
+{{- end}}
+{{- end}}
+{{- with .Responses}}
+<h4>Responses</h4>
+<table>
+<tr><th>Code</th><th>Description</th></tr>
+{{- range .}}
+<tr><td><code>{{.Code}}</code></td><td><span class="description">{{.Description}}</span>
+{{- range .Bodies}}
+{{template "body" .}}
+{{- end}}
+</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</div>
+{{- end}}
+</section>
+{{- end}}
+{{- if .Schemas}}
+<section>
+<h2 id="schemas">Schemas</h2>
+{{- range .Schemas}}
+<div class="operation" id="{{.Anchor}}">
+<h3><a href="#{{.Anchor}}">{{.Name}}</a> <small>{{template "type" .Type}}</small></h3>
+{{- with .Description}}
+<p class="description">{{.}}</p>
+{{- end}}
+{{- with .Properties}}
+<table>
+<tr><th>Name</th><th>Type</th><th>Description</th></tr>
+{{- range .}}
+<tr><td><code>{{.Name}}</code>{{if .Required}} <span class="required">required</span>{{end}}{{if .Deprecated}} <span class="deprecated">deprecated</span>{{end}}</td><td>{{template "type" .Type}}</td><td class="description">{{.Description}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with .Example}}
+<pre><code>{{.}}</code></pre>
+{{- end}}
+</div>
+{{- end}}
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+{{define "type"}}{{if .Anchor}}<a href="#{{.Anchor}}">{{.Label}}</a>{{else}}{{.Label}}{{end}}{{end}}
+{{- define "parameter"}}<tr><td><code>{{.Name}}</code>{{if .Required}} <span class="required">required</span>{{end}}{{if .Deprecated}} <span class="deprecated">deprecated</span>{{end}}</td><td>{{.In}}</td><td>{{template "type" .Type}}</td><td class="description">{{.Description}}</td></tr>{{end}}
+{{- define "body"}}<p><code>{{.MediaType}}</code> {{template "type" .Type}}</p>{{with .Example}}
+<pre><code>{{.}}</code></pre>{{end}}{{end}}
+`))
+
+// HTML renders the page
+func (page Page) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, page); err != nil {
+		return "", common.NewError(23001, "docs template error", err)
+	}
+	return buf.String(), nil
+}
+
+// Render renders a document as a self-contained HTML page
+func Render(doc swagger.Document) (string, error) {
+	return NewPage(doc).HTML()
+}
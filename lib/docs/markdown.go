@@ -0,0 +1,214 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Files of MarkdownFiles
+const (
+	IndexFile   = "README.md"
+	SchemasFile = "schemas.md"
+)
+
+var cellEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+type markdownWriter struct {
+	buf bytes.Buffer
+	// schemaFile prefixes the links to schemas ("" if they are in the same document)
+	schemaFile string
+}
+
+// Markdown renders the page as one document
+func (page Page) Markdown() string {
+	w := &markdownWriter{}
+	w.header(page)
+	if len(page.Groups) > 0 || len(page.Schemas) > 0 {
+		w.buf.WriteString("\n## Contents\n\n")
+		for _, group := range page.Groups {
+			fmt.Fprintf(&w.buf, "- [%s](#%s)\n", group.Name, group.Anchor)
+		}
+		if len(page.Schemas) > 0 {
+			w.buf.WriteString("- [Schemas](#schemas)\n")
+		}
+	}
+	for _, group := range page.Groups {
+		fmt.Fprintf(&w.buf, "\n<a id=\"%s\"></a>\n\n## %s\n", group.Anchor, group.Name)
+		w.group(group, "###")
+	}
+	if len(page.Schemas) > 0 {
+		w.buf.WriteString("\n<a id=\"schemas\"></a>\n\n## Schemas\n")
+		w.schemas(page.Schemas, "###")
+	}
+	return w.buf.String()
+}
+
+// MarkdownFiles renders one document per tag (named after its anchor), the schemas (SchemasFile)
+// and an index linking them (IndexFile)
+func (page Page) MarkdownFiles() map[string]string {
+	files := map[string]string{}
+	index := &markdownWriter{}
+	index.header(page)
+	if len(page.Groups) > 0 || len(page.Schemas) > 0 {
+		index.buf.WriteString("\n## Contents\n\n")
+	}
+	for _, group := range page.Groups {
+		name := group.Anchor + ".md"
+		fmt.Fprintf(&index.buf, "- [%s](%s) (%d operations)\n", group.Name, name, len(group.Operations))
+
+		w := &markdownWriter{schemaFile: SchemasFile}
+		fmt.Fprintf(&w.buf, "# %s\n", group.Name)
+		w.group(group, "##")
+		files[name] = w.buf.String()
+	}
+	if len(page.Schemas) > 0 {
+		fmt.Fprintf(&index.buf, "- [Schemas](%s)\n", SchemasFile)
+
+		w := &markdownWriter{}
+		w.buf.WriteString("# Schemas\n")
+		w.schemas(page.Schemas, "##")
+		files[SchemasFile] = w.buf.String()
+	}
+	files[IndexFile] = index.buf.String()
+	return files
+}
+
+func (this *markdownWriter) header(page Page) {
+	fmt.Fprintf(&this.buf, "# %s", page.Title)
+	if page.Version != "" {
+		fmt.Fprintf(&this.buf, " %s", page.Version)
+	}
+	this.buf.WriteString("\n")
+	if page.Description != "" {
+		fmt.Fprintf(&this.buf, "\n%s\n", page.Description)
+	}
+	if len(page.Servers) > 0 {
+		this.buf.WriteString("\nServers:\n\n")
+		for _, server := range page.Servers {
+			fmt.Fprintf(&this.buf, "- `%s`\n", server)
+		}
+	}
+}
+
+func (this *markdownWriter) group(group Group, level string) {
+	if group.Description != "" {
+		fmt.Fprintf(&this.buf, "\n%s\n", group.Description)
+	}
+	for _, op := range group.Operations {
+		fmt.Fprintf(&this.buf, "\n<a id=\"%s\"></a>\n\n%s `%s %s`", op.Anchor, level, op.Method, op.Path)
+		if op.Deprecated {
+			this.buf.WriteString(" (deprecated)")
+		}
+		this.buf.WriteString("\n")
+		if op.Summary != "" {
+			fmt.Fprintf(&this.buf, "\n**%s**\n", op.Summary)
+		}
+		if op.Description != "" {
+			fmt.Fprintf(&this.buf, "\n%s\n", op.Description)
+		}
+		if op.OperationId != "" {
+			fmt.Fprintf(&this.buf, "\noperationId: `%s`\n", op.OperationId)
+		}
+		if len(op.Security) > 0 {
+			fmt.Fprintf(&this.buf, "\nSecurity: `%s`\n", strings.Join(op.Security, "` or `"))
+		}
+
+		if len(op.Parameters) > 0 {
+			fmt.Fprintf(&this.buf, "\n%s# Parameters\n\n", level)
+			this.buf.WriteString("| Name | In | Type | Required | Description |\n| --- | --- | --- | --- | --- |\n")
+			for _, p := range op.Parameters {
+				fmt.Fprintf(&this.buf, "| `%s`%s | %s | %s | %s | %s |\n", p.Name, deprecated(p.Deprecated), p.In, this.typeLink(p.Type), yesNo(p.Required), cellEscaper.Replace(p.Description))
+			}
+		}
+		if len(op.Bodies) > 0 {
+			fmt.Fprintf(&this.buf, "\n%s# Request body\n", level)
+			for _, body := range op.Bodies {
+				this.body(body)
+			}
+		}
+		if len(op.Responses) > 0 {
+			fmt.Fprintf(&this.buf, "\n%s# Responses\n\n", level)
+			this.buf.WriteString("| Code | Description | Content |\n| --- | --- | --- |\n")
+			for _, r := range op.Responses {
+				contents := []string{}
+				for _, body := range r.Bodies {
+					contents = append(contents, strings.TrimSpace("`"+body.MediaType+"` "+this.typeLink(body.Type)))
+				}
+				fmt.Fprintf(&this.buf, "| `%s` | %s | %s |\n", r.Code, cellEscaper.Replace(r.Description), strings.Join(contents, "<br>"))
+			}
+			for _, r := range op.Responses {
+				for _, body := range r.Bodies {
+					if body.Example != "" {
+						fmt.Fprintf(&this.buf, "\nExample `%s` `%s`:\n", r.Code, body.MediaType)
+						codeBlock(&this.buf, body.Example)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (this *markdownWriter) body(body Body) {
+	fmt.Fprintf(&this.buf, "\n%s\n", strings.TrimSpace("`"+body.MediaType+"` "+this.typeLink(body.Type)))
+	if body.Example != "" {
+		codeBlock(&this.buf, body.Example)
+	}
+}
+
+func (this *markdownWriter) schemas(schemas []Schema, level string) {
+	for _, schema := range schemas {
+		fmt.Fprintf(&this.buf, "\n<a id=\"%s\"></a>\n\n%s %s\n", schema.Anchor, level, schema.Name)
+		if schema.Type.Label != "" {
+			fmt.Fprintf(&this.buf, "\nType: %s\n", this.typeLink(schema.Type))
+		}
+		if schema.Description != "" {
+			fmt.Fprintf(&this.buf, "\n%s\n", schema.Description)
+		}
+		if len(schema.Properties) > 0 {
+			this.buf.WriteString("\n| Name | Type | Required | Description |\n| --- | --- | --- | --- |\n")
+			for _, p := range schema.Properties {
+				fmt.Fprintf(&this.buf, "| `%s`%s | %s | %s | %s |\n", p.Name, deprecated(p.Deprecated), this.typeLink(p.Type), yesNo(p.Required), cellEscaper.Replace(p.Description))
+			}
+		}
+		if schema.Example != "" {
+			this.buf.WriteString("\nExample:\n")
+			codeBlock(&this.buf, schema.Example)
+		}
+	}
+}
+
+// typeLink returns the label of a type, linking named schemas
+func (this *markdownWriter) typeLink(typ Type) string {
+	label := cellEscaper.Replace(typ.Label)
+	if typ.Anchor == "" {
+		return label
+	}
+	return fmt.Sprintf("[%s](%s#%s)", label, this.schemaFile, typ.Anchor)
+}
+
+func codeBlock(buf *bytes.Buffer, code string) {
+	language := ""
+	if strings.HasPrefix(code, "{") || strings.HasPrefix(code, "[") {
+		language = "json"
+	}
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(buf, "\n%s%s\n%s\n%s\n", fence, language, code, fence)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func deprecated(b bool) string {
+	if b {
+		return " (deprecated)"
+	}
+	return ""
+}
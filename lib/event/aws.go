@@ -0,0 +1,119 @@
+package event
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// SNSPublisher publishes events to a SNS topic.
+// The event type is set to the "eventType" message attribute for subscription filters.
+type SNSPublisher struct {
+	topicArn  string
+	snsClient *sns.SNS
+}
+
+// NewSNSPublisher return SNS Publisher
+func NewSNSPublisher(topicArn string) (*SNSPublisher, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+	return &SNSPublisher{
+		topicArn:  topicArn,
+		snsClient: sns.New(cfg),
+	}, nil
+}
+
+// Publish publishes the event to the topic
+func (this *SNSPublisher) Publish(event Event) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	message, err := json.Marshal(event)
+	if err != nil {
+		return common.NewError(101, "marshal error", err)
+	}
+	if _, err := this.snsClient.PublishRequest(&sns.PublishInput{
+		TopicArn: aws.String(this.topicArn),
+		Message:  aws.String(string(message)),
+		MessageAttributes: map[string]sns.MessageAttributeValue{
+			"eventType": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(event.Type),
+			},
+		},
+	}).Send(); err != nil {
+		return common.NewError(310, "sns publish error", err)
+	}
+	return nil
+}
+
+// SQSPublisher sends events to a SQS queue
+type SQSPublisher struct {
+	queueUrl  string
+	sqsClient *sqs.SQS
+}
+
+// NewSQSPublisher return SQS Publisher
+func NewSQSPublisher(queueUrl string) (*SQSPublisher, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+	return &SQSPublisher{
+		queueUrl:  queueUrl,
+		sqsClient: sqs.New(cfg),
+	}, nil
+}
+
+// Publish sends the event to the queue
+func (this *SQSPublisher) Publish(event Event) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	message, err := json.Marshal(event)
+	if err != nil {
+		return common.NewError(101, "marshal error", err)
+	}
+	if _, err := this.sqsClient.SendMessageRequest(&sqs.SendMessageInput{
+		QueueUrl:    aws.String(this.queueUrl),
+		MessageBody: aws.String(string(message)),
+		MessageAttributes: map[string]sqs.MessageAttributeValue{
+			"eventType": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(event.Type),
+			},
+		},
+	}).Send(); err != nil {
+		return common.NewError(311, "sqs sendmessage error", err)
+	}
+	return nil
+}
+
+// NewPublisherFromEnv returns the publisher configured by environment variables.
+//
+//	EVENT_PUBLISHER=sns  (EVENT_TOPIC_ARN)
+//	EVENT_PUBLISHER=sqs  (EVENT_QUEUE_URL)
+//	EVENT_PUBLISHER=file (EVENT_FILE_PATH)
+//
+// Otherwise events are discarded.
+func NewPublisherFromEnv() (Publisher, error) {
+	switch os.Getenv("EVENT_PUBLISHER") {
+	case "sns":
+		return NewSNSPublisher(os.Getenv("EVENT_TOPIC_ARN"))
+	case "sqs":
+		return NewSQSPublisher(os.Getenv("EVENT_QUEUE_URL"))
+	case "file":
+		return NewFilePublisher(os.Getenv("EVENT_FILE_PATH")), nil
+	case "":
+		return NopPublisher{}, nil
+	default:
+		return nil, common.NewError(201, "unknown EVENT_PUBLISHER", nil)
+	}
+}
@@ -0,0 +1,66 @@
+package event
+
+import (
+	"time"
+)
+
+// Domain event types
+const (
+	TypeServiceCreated  = "ServiceCreated"
+	TypeServiceDeleted  = "ServiceDeleted"
+	TypeVersionUploaded = "VersionUploaded"
+	TypeVersionUpdated  = "VersionUpdated"
+)
+
+// Event is a domain event emitted by the dao layer.
+// Data is the entity after the mutation (the deleted entity for ServiceDeleted).
+type Event struct {
+	Type      string      `json:"type"`
+	Serviceid string      `json:"serviceid"`
+	Version   string      `json:"version,omitempty"`
+	Timestamp int64       `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Publisher publishes domain events
+type Publisher interface {
+	Publish(event Event) error
+}
+
+// NewServiceCreated returns a ServiceCreated event
+func NewServiceCreated(serviceId string, service interface{}) Event {
+	return newEvent(TypeServiceCreated, serviceId, "", service)
+}
+
+// NewServiceDeleted returns a ServiceDeleted event
+func NewServiceDeleted(serviceId string, service interface{}) Event {
+	return newEvent(TypeServiceDeleted, serviceId, "", service)
+}
+
+// NewVersionUploaded returns a VersionUploaded event
+func NewVersionUploaded(serviceId string, version string, entity interface{}) Event {
+	return newEvent(TypeVersionUploaded, serviceId, version, entity)
+}
+
+// NewVersionUpdated returns a VersionUpdated event
+func NewVersionUpdated(serviceId string, version string, entity interface{}) Event {
+	return newEvent(TypeVersionUpdated, serviceId, version, entity)
+}
+
+func newEvent(eventType string, serviceId string, version string, data interface{}) Event {
+	return Event{
+		Type:      eventType,
+		Serviceid: serviceId,
+		Version:   version,
+		Timestamp: time.Now().Unix() * 1000,
+		Data:      data,
+	}
+}
+
+// NopPublisher discards events. It is the default publisher of daos.
+type NopPublisher struct{}
+
+// Publish does nothing
+func (NopPublisher) Publish(event Event) error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package event
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChannelPublisher(t *testing.T) {
+	publisher := NewChannelPublisher(1)
+	if err := publisher.Publish(NewServiceCreated("id", map[string]string{"id": "id"})); err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if err := publisher.Publish(NewServiceDeleted("id", nil)); err == nil {
+		t.Fatalf("a full channel must fail")
+	}
+	event := <-publisher.C
+	if event.Type != TypeServiceCreated || event.Serviceid != "id" || event.Timestamp == 0 {
+		t.Fatalf("invalid event %+v", event)
+	}
+}
+
+func TestFilePublisher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.jsonl")
+
+	publisher := NewFilePublisher(path)
+	if err := publisher.Publish(NewVersionUploaded("id", "1.0.0", nil)); err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if err := publisher.Publish(NewServiceDeleted("id", nil)); err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	defer file.Close()
+	var types []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("invalid line %s", scanner.Text())
+		}
+		types = append(types, event.Type)
+	}
+	if len(types) != 2 || types[0] != TypeVersionUploaded || types[1] != TypeServiceDeleted {
+		t.Fatalf("invalid events %v", types)
+	}
+}
+
+func TestNewPublisherFromEnv(t *testing.T) {
+	os.Setenv("EVENT_PUBLISHER", "")
+	if publisher, err := NewPublisherFromEnv(); err != nil || publisher != (NopPublisher{}) {
+		t.Fatalf("failed test %#v", err)
+	}
+	os.Setenv("EVENT_PUBLISHER", "kafka")
+	if _, err := NewPublisherFromEnv(); err == nil {
+		t.Fatalf("unknown publisher must be error")
+	}
+	os.Unsetenv("EVENT_PUBLISHER")
+}
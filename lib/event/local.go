@@ -0,0 +1,69 @@
+package event
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// ChannelPublisher sends events to an in-process channel
+type ChannelPublisher struct {
+	C chan Event
+}
+
+// NewChannelPublisher returns a ChannelPublisher with a buffered channel.
+// Publish fails when the buffer is full.
+func NewChannelPublisher(buffer int) *ChannelPublisher {
+	return &ChannelPublisher{C: make(chan Event, buffer)}
+}
+
+// Publish sends the event to the channel. It returns an error instead of waiting for a full buffer,
+// so that a receiver which stopped does not block the dao.
+func (this *ChannelPublisher) Publish(event Event) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	select {
+	case this.C <- event:
+		return nil
+	default:
+		return common.NewError(402, "channel is full", nil)
+	}
+}
+
+// FilePublisher appends events to a file as JSON lines
+type FilePublisher struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewFilePublisher returns a FilePublisher. The file is created on the first event.
+func NewFilePublisher(path string) *FilePublisher {
+	return &FilePublisher{path: path}
+}
+
+// Publish appends the event to the file
+func (this *FilePublisher) Publish(event Event) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return common.NewError(101, "marshal error", err)
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	file, err := os.OpenFile(this.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return common.NewError(400, "file open error", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return common.NewError(401, "file write error", err)
+	}
+	return nil
+}
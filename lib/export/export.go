@@ -0,0 +1,380 @@
+// Package export renders the operations of a document as ready-to-send requests:
+// Postman v2.1 collections and .http files (REST Client).
+// Parameters and bodies are filled with the examples of the document.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/mock"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// BaseURL is the name of the variable holding the url of the first server
+const BaseURL = "baseUrl"
+
+// Body modes
+const (
+	modeRaw        = "raw"
+	modeURLEncoded = "urlencoded"
+	modeFormData   = "formdata"
+)
+
+var serverVariable = regexp.MustCompile(`\{([^{}]+)\}`)
+
+var invalidVariableChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// Variable is a variable of the collection
+type Variable struct {
+	Key   string
+	Value string
+}
+
+// param is a parameter of a request. Optional ones are kept disabled.
+type param struct {
+	Key      string
+	Value    string
+	Disabled bool
+}
+
+// request is the example request of an operation
+type request struct {
+	Name        string
+	Description string
+	Folder      string // the first tag, "" if untagged
+	OperationId string
+	Method      string // uppercase
+	Path        string // the path template
+	PathParams  []param
+	Query       []param
+	Headers     []param
+	ContentType string
+	BodyMode    string
+	Body        string  // raw bodies
+	Form        []param // urlencoded and formdata bodies
+}
+
+type builder struct {
+	doc       swagger.Document
+	variables []Variable
+}
+
+// baseVariables returns the base url as {{baseUrl}} and the server variables (3.0).
+// The base url of 2.0 documents is built from schemes, host and basePath.
+func (this *builder) baseVariables() []Variable {
+	if this.doc.IsOpenAPI3() {
+		servers := swagger.Slice(this.doc, "servers")
+		if len(servers) == 0 {
+			return []Variable{{Key: BaseURL, Value: "/"}}
+		}
+		server, _ := servers[0].(map[string]interface{})
+		variables := []Variable{}
+		defined := swagger.Map(server, "variables")
+		names := make([]string, 0, len(defined))
+		for name := range defined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v, _ := defined[name].(map[string]interface{})
+			variables = append(variables, Variable{Key: name, Value: fmt.Sprint(v["default"])})
+		}
+		base := serverVariable.ReplaceAllString(strings.TrimRight(swagger.String(server, "url"), "/"), "{{$1}}")
+		return append([]Variable{{Key: BaseURL, Value: base}}, variables...)
+	}
+
+	scheme := "https"
+	if schemes := swagger.Strings(this.doc, "schemes"); len(schemes) > 0 && !swagger.Contains(schemes, "https") {
+		scheme = schemes[0]
+	}
+	host := swagger.String(this.doc, "host")
+	if host == "" {
+		host = "localhost"
+	}
+	return []Variable{{Key: BaseURL, Value: scheme + "://" + host + strings.TrimRight(swagger.String(this.doc, "basePath"), "/")}}
+}
+
+// requests returns the requests of all operations, and the variables they use
+func requests(doc swagger.Document) ([]request, []Variable) {
+	b := &builder{doc: doc}
+	b.variables = b.baseVariables()
+	reqs := []request{}
+	for _, op := range doc.Operations() {
+		reqs = append(reqs, b.request(op))
+	}
+	return reqs, b.variables
+}
+
+func (this *builder) request(op swagger.Operation) request {
+	r := request{
+		Name:        op.Summary,
+		Description: op.Description,
+		OperationId: op.OperationId,
+		Method:      strings.ToUpper(op.Method),
+		Path:        op.Path,
+		PathParams:  []param{},
+		Query:       []param{},
+		Headers:     []param{},
+		Form:        []param{},
+	}
+	if r.Name == "" {
+		r.Name = op.OperationId
+	}
+	if r.Name == "" {
+		r.Name = op.Key()
+	}
+	if len(op.Tags) > 0 {
+		r.Folder = op.Tags[0]
+	}
+
+	var bodySchema map[string]interface{}
+	var formParams []swagger.Parameter
+	for _, p := range op.Parameters {
+		value := this.parameterExample(p)
+		switch p.In {
+		case "path":
+			r.PathParams = append(r.PathParams, param{Key: p.Name, Value: value})
+		case "query":
+			r.Query = append(r.Query, param{Key: p.Name, Value: value, Disabled: !p.Required})
+		case "header":
+			// described by requestBody, responses and security in 3.0
+			if this.doc.IsOpenAPI3() && (strings.EqualFold(p.Name, "Content-Type") || strings.EqualFold(p.Name, "Accept") || strings.EqualFold(p.Name, "Authorization")) {
+				continue
+			}
+			r.Headers = append(r.Headers, param{Key: p.Name, Value: value, Disabled: !p.Required})
+		case "body":
+			bodySchema = swagger.Map(p.Raw, "schema")
+		case "formData":
+			formParams = append(formParams, p)
+		}
+	}
+	this.security(op, &r)
+
+	if this.doc.IsOpenAPI3() {
+		this.requestBody(op, &r)
+		return r
+	}
+	consumes := swagger.Strings(op.Raw, "consumes")
+	if len(consumes) == 0 {
+		consumes = swagger.Strings(this.doc, "consumes")
+	}
+	if bodySchema != nil {
+		r.ContentType = "application/json"
+		if len(consumes) > 0 && !swagger.Contains(consumes, r.ContentType) {
+			r.ContentType = consumes[0]
+		}
+		r.BodyMode, r.Body = modeRaw, rawBody(r.ContentType, mock.Example(this.doc, bodySchema))
+	} else if len(formParams) > 0 {
+		r.ContentType, r.BodyMode = "application/x-www-form-urlencoded", modeURLEncoded
+		if swagger.Contains(consumes, "multipart/form-data") {
+			r.ContentType, r.BodyMode = "multipart/form-data", modeFormData
+		}
+		for _, p := range formParams {
+			if p.Type == "file" {
+				r.ContentType, r.BodyMode = "multipart/form-data", modeFormData
+			}
+			r.Form = append(r.Form, param{Key: p.Name, Value: this.parameterExample(p), Disabled: !p.Required})
+		}
+	}
+	return r
+}
+
+// requestBody selects a media type (json, then forms, then the first one) and its example
+func (this *builder) requestBody(op swagger.Operation, r *request) {
+	content := swagger.Map(op.RequestBody, "content")
+	if len(content) == 0 {
+		return
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	r.ContentType = types[0]
+	for _, preferred := range []string{"multipart/form-data", "application/x-www-form-urlencoded"} {
+		if swagger.Contains(types, preferred) {
+			r.ContentType = preferred
+		}
+	}
+	for _, t := range types {
+		if isJSON(t) {
+			r.ContentType = t
+			break
+		}
+	}
+	media, _ := content[r.ContentType].(map[string]interface{})
+	schema := swagger.Map(media, "schema")
+
+	example, ok := media["example"]
+	if examples := swagger.Map(media, "examples"); !ok && len(examples) > 0 {
+		names := make([]string, 0, len(examples))
+		for name := range examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		e, _ := this.doc.Deref(examples[names[0]]).(map[string]interface{})
+		example, ok = e["value"]
+	}
+	if !ok {
+		example = mock.Example(this.doc, schema)
+	}
+
+	if r.ContentType != "application/x-www-form-urlencoded" && r.ContentType != "multipart/form-data" {
+		r.BodyMode, r.Body = modeRaw, rawBody(r.ContentType, example)
+		return
+	}
+	r.BodyMode = modeURLEncoded
+	if r.ContentType == "multipart/form-data" {
+		r.BodyMode = modeFormData
+	}
+	schema, _ = this.doc.Deref(schema).(map[string]interface{})
+	required := swagger.Strings(schema, "required")
+	values, _ := example.(map[string]interface{})
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r.Form = append(r.Form, param{Key: name, Value: scalar(values[name]), Disabled: !swagger.Contains(required, name)})
+	}
+}
+
+// security adds the credentials of the first security requirement as variables
+func (this *builder) security(op swagger.Operation, r *request) {
+	if len(op.Security) == 0 {
+		return
+	}
+	schemes := swagger.Map(this.doc, "securityDefinitions")
+	if this.doc.IsOpenAPI3() {
+		schemes = swagger.Map(swagger.Map(this.doc, "components"), "securitySchemes")
+	}
+	names := make([]string, 0, len(op.Security[0]))
+	for name := range op.Security[0] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		scheme, _ := this.doc.Deref(schemes[name]).(map[string]interface{})
+		variable := invalidVariableChars.ReplaceAllString(name, "_")
+		value := "{{" + variable + "}}"
+		switch typ := swagger.String(scheme, "type"); {
+		case typ == "apiKey" && swagger.String(scheme, "in") == "header":
+			r.Headers = append(r.Headers, param{Key: swagger.String(scheme, "name"), Value: value})
+		case typ == "apiKey" && swagger.String(scheme, "in") == "query":
+			r.Query = append(r.Query, param{Key: swagger.String(scheme, "name"), Value: value})
+		case typ == "basic" || (typ == "http" && strings.EqualFold(swagger.String(scheme, "scheme"), "basic")):
+			r.Headers = append(r.Headers, param{Key: "Authorization", Value: "Basic " + value})
+		case typ == "http" || typ == "oauth2" || typ == "openIdConnect":
+			r.Headers = append(r.Headers, param{Key: "Authorization", Value: "Bearer " + value})
+		default:
+			continue
+		}
+		this.addVariable(Variable{Key: variable})
+	}
+}
+
+func (this *builder) addVariable(variable Variable) {
+	for _, v := range this.variables {
+		if v.Key == variable.Key {
+			return
+		}
+	}
+	this.variables = append(this.variables, variable)
+}
+
+// parameterExample returns the example of a parameter (example, examples or its schema) as a string
+func (this *builder) parameterExample(p swagger.Parameter) string {
+	if example, ok := p.Raw["example"]; ok {
+		return scalar(example)
+	}
+	if examples := swagger.Map(p.Raw, "examples"); len(examples) > 0 {
+		names := make([]string, 0, len(examples))
+		for name := range examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		e, _ := this.doc.Deref(examples[names[0]]).(map[string]interface{})
+		if value, ok := e["value"]; ok {
+			return scalar(value)
+		}
+	}
+	schema := swagger.Map(p.Raw, "schema")
+	if schema == nil {
+		// 2.0 parameters other than body have their type at the top level
+		schema = p.Raw
+	}
+	return scalar(mock.Example(this.doc, schema))
+}
+
+// rawBody formats an example as indented JSON, or as it is for other media types
+func rawBody(contentType string, example interface{}) string {
+	if example == nil {
+		return ""
+	}
+	if s, ok := example.(string); ok && !isJSON(contentType) {
+		return s
+	}
+	b, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// scalar formats a value of a parameter. Arrays are comma separated (csv / form style).
+func scalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = scalar(item)
+		}
+		return strings.Join(values, ",")
+	case map[string]interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return fmt.Sprint(value)
+}
+
+// rawURL returns {{baseUrl}}/path?query with the path parameters as :name (Postman) or their values
+func (r request) rawURL(pathParam func(p param) string) string {
+	path := r.Path
+	for _, p := range r.PathParams {
+		path = strings.Replace(path, "{"+p.Key+"}", pathParam(p), -1)
+	}
+	query := []string{}
+	for _, q := range r.Query {
+		if !q.Disabled {
+			query = append(query, url.QueryEscape(q.Key)+"="+escapeValue(q.Value))
+		}
+	}
+	raw := "{{" + BaseURL + "}}" + path
+	if len(query) > 0 {
+		raw += "?" + strings.Join(query, "&")
+	}
+	return raw
+}
+
+// escapeValue escapes a query value, keeping {{variables}}
+func escapeValue(value string) string {
+	if strings.HasPrefix(value, "{{") && strings.HasSuffix(value, "}}") {
+		return value
+	}
+	return url.QueryEscape(value)
+}
+
+func isJSON(mediaType string) bool {
+	mediaType = strings.ToLower(strings.SplitN(mediaType, ";", 2)[0])
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+func parse(t *testing.T, contents string) swagger.Document {
+	doc, err := swagger.Parse(common.Yml, contents)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	return doc
+}
+
+var petstore = `
+openapi: 3.0.0
+info: {title: petstore, version: 1.0.0}
+servers:
+  - url: https://{env}.example.com/v1
+    variables:
+      env: {default: api}
+security:
+  - api_key: []
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      summary: List pets
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, example: 10}}
+        - {name: kind, in: query, required: true, schema: {type: string, enum: [dog, cat]}}
+      responses: {'200': {description: ok}}
+    post:
+      tags: [pets]
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses: {'201': {description: created}}
+  /pets/{petId}:
+    get:
+      tags: [pets]
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: integer}, example: 1}
+      responses: {'200': {description: ok}}
+  /health:
+    get:
+      security: []
+      responses: {'200': {description: ok}}
+components:
+  securitySchemes:
+    api_key: {type: apiKey, in: header, name: X-API-KEY}
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string, example: dog}
+`
+
+func TestPostman(t *testing.T) {
+	collection := Postman(parse(t, petstore), "petstore 1.0.0")
+
+	variables := map[string]string{}
+	for _, v := range collection.Variable {
+		variables[v.Key] = v.Value
+	}
+	if variables[BaseURL] != "https://{{env}}.example.com/v1" || variables["env"] != "api" || len(variables) != 3 {
+		t.Fatalf("invalid variables %+v", collection.Variable)
+	}
+
+	// untagged requests are at the top level
+	if len(collection.Item) != 2 || collection.Item[0].Name != "GET /health" || collection.Item[1].Name != "pets" || len(collection.Item[1].Item) != 3 {
+		t.Fatalf("invalid items %+v", collection.Item)
+	}
+	if len(collection.Item[0].Request.Header) != 0 {
+		t.Fatalf("security must be overridden %+v", collection.Item[0].Request.Header)
+	}
+
+	list := collection.Item[1].Item[0].Request
+	if collection.Item[1].Item[0].Name != "List pets" || list.URL.Raw != "{{baseUrl}}/pets?kind=dog" || len(list.URL.Query) != 2 || !list.URL.Query[0].Disabled || list.URL.Query[0].Value != "10" {
+		t.Fatalf("invalid request %+v", list.URL)
+	}
+	if list.Header[0].Key != "X-API-KEY" || list.Header[0].Value != "{{api_key}}" {
+		t.Fatalf("invalid headers %+v", list.Header)
+	}
+
+	create := collection.Item[1].Item[1].Request
+	if create.Body == nil || create.Body.Mode != "raw" || create.Body.Raw != "{\n  \"name\": \"dog\"\n}" {
+		t.Fatalf("invalid body %+v", create.Body)
+	}
+
+	get := collection.Item[1].Item[2].Request
+	if get.URL.Raw != "{{baseUrl}}/pets/:petId" || strings.Join(get.URL.Path, "/") != "pets/:petId" || get.URL.Variable[0].Value != "1" {
+		t.Fatalf("invalid url %+v", get.URL)
+	}
+
+	s, err := collection.JSON()
+	var decoded map[string]interface{}
+	if err != nil || json.Unmarshal([]byte(s), &decoded) != nil || swagger.String(swagger.Map(decoded, "info"), "schema") != PostmanSchema {
+		t.Fatalf("invalid json %v %s", err, s)
+	}
+}
+
+var forms = `
+swagger: '2.0'
+info: {title: forms, version: 1.0.0}
+host: example.com
+basePath: /api/
+schemes: [http]
+paths:
+  /login:
+    post:
+      consumes: [application/x-www-form-urlencoded]
+      parameters:
+        - {name: user, in: formData, type: string, required: true, x-example: alice}
+        - {name: remember, in: formData, type: boolean}
+      responses: {'200': {description: ok}}
+  /files:
+    post:
+      parameters:
+        - {name: file, in: formData, type: file, required: true}
+      responses: {'200': {description: ok}}
+`
+
+func TestHTTPFile(t *testing.T) {
+	expected := `# petstore 1.0.0
+
+@baseUrl = https://{{env}}.example.com/v1
+@env = api
+@api_key =
+
+### GET /health
+GET {{baseUrl}}/health
+
+# ======== pets ========
+
+### List pets
+# @name listPets
+GET {{baseUrl}}/pets?kind=dog
+X-API-KEY: {{api_key}}
+
+### createPet
+# @name createPet
+POST {{baseUrl}}/pets
+X-API-KEY: {{api_key}}
+Content-Type: application/json
+
+{
+  "name": "dog"
+}
+
+### GET /pets/{petId}
+GET {{baseUrl}}/pets/1
+X-API-KEY: {{api_key}}
+`
+	if s := HTTPFile(parse(t, petstore), "petstore 1.0.0"); s != expected {
+		t.Fatalf("invalid http file\n%s", s)
+	}
+
+	s := HTTPFile(parse(t, forms), "forms")
+	for _, part := range []string{
+		"@baseUrl = http://example.com/api\n",
+		"POST {{baseUrl}}/login\nContent-Type: application/x-www-form-urlencoded\n\nuser=alice\n",
+		"Content-Type: multipart/form-data; boundary=boundary\n\n--boundary\nContent-Disposition: form-data; name=\"file\"\n\n\n--boundary--\n",
+	} {
+		if !strings.Contains(s, part) {
+			t.Fatalf("%q is missing\n%s", part, s)
+		}
+	}
+}
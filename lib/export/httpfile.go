@@ -0,0 +1,90 @@
+package export
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// multipartBoundary separates the fields of multipart/form-data bodies
+const multipartBoundary = "boundary"
+
+// HTTPFile returns a .http file (REST Client) with one request per operation.
+// The base url and the server variables are file variables, requests are grouped
+// by their first tag, and optional parameters are omitted.
+func HTTPFile(doc swagger.Document, name string) string {
+	reqs, variables := requests(doc)
+	var b strings.Builder
+	b.WriteString("# " + name + "\n\n")
+	for _, v := range variables {
+		b.WriteString(strings.TrimSpace("@"+v.Key+" = "+v.Value) + "\n")
+	}
+
+	// untagged requests come first, then the folders in the order of their first request
+	folders := []string{""}
+	grouped := map[string][]request{}
+	for _, r := range reqs {
+		if _, ok := grouped[r.Folder]; !ok && r.Folder != "" {
+			folders = append(folders, r.Folder)
+		}
+		grouped[r.Folder] = append(grouped[r.Folder], r)
+	}
+	for _, folder := range folders {
+		if len(grouped[folder]) == 0 {
+			continue
+		}
+		if folder != "" {
+			b.WriteString("\n# " + strings.Repeat("=", 8) + " " + folder + " " + strings.Repeat("=", 8) + "\n")
+		}
+		for _, r := range grouped[folder] {
+			writeHTTPRequest(&b, r)
+		}
+	}
+	return b.String()
+}
+
+func writeHTTPRequest(b *strings.Builder, r request) {
+	b.WriteString("\n### " + oneLine(r.Name) + "\n")
+	if r.OperationId != "" {
+		b.WriteString("# @name " + r.OperationId + "\n")
+	}
+	b.WriteString(r.Method + " " + r.rawURL(func(p param) string { return url.PathEscape(p.Value) }) + "\n")
+	for _, h := range r.Headers {
+		if !h.Disabled {
+			b.WriteString(h.Key + ": " + h.Value + "\n")
+		}
+	}
+
+	switch r.BodyMode {
+	case modeRaw:
+		b.WriteString("Content-Type: " + r.ContentType + "\n")
+		if r.Body != "" {
+			b.WriteString("\n" + r.Body + "\n")
+		}
+	case modeURLEncoded:
+		b.WriteString("Content-Type: " + r.ContentType + "\n")
+		fields := []string{}
+		for _, f := range r.Form {
+			if !f.Disabled {
+				fields = append(fields, url.QueryEscape(f.Key)+"="+escapeValue(f.Value))
+			}
+		}
+		b.WriteString("\n" + strings.Join(fields, "\n&") + "\n")
+	case modeFormData:
+		b.WriteString("Content-Type: multipart/form-data; boundary=" + multipartBoundary + "\n\n")
+		for _, f := range r.Form {
+			if !f.Disabled {
+				b.WriteString("--" + multipartBoundary + "\n")
+				b.WriteString("Content-Disposition: form-data; name=\"" + f.Key + "\"\n\n")
+				b.WriteString(f.Value + "\n")
+			}
+		}
+		b.WriteString("--" + multipartBoundary + "--\n")
+	}
+}
+
+// oneLine keeps the name on the separator line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,156 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// PostmanSchema is the schema of Postman v2.1 collections
+const PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// PostmanCollection is a Postman v2.1 collection
+type PostmanCollection struct {
+	Info     PostmanInfo       `json:"info"`
+	Item     []PostmanItem     `json:"item"`
+	Variable []PostmanKeyValue `json:"variable"`
+}
+
+// PostmanInfo is the info of a collection
+type PostmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// PostmanItem is a folder (Item) or a request (Request)
+type PostmanItem struct {
+	Name     string          `json:"name"`
+	Item     []PostmanItem   `json:"item,omitempty"`
+	Request  *PostmanRequest `json:"request,omitempty"`
+	Response []interface{}   `json:"response,omitempty"`
+}
+
+// PostmanRequest is a request of a collection
+type PostmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []PostmanKeyValue `json:"header"`
+	URL         PostmanURL        `json:"url"`
+	Body        *PostmanBody      `json:"body,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+// PostmanURL is the url of a request. Path parameters are :name with their values in Variable.
+type PostmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []PostmanKeyValue `json:"query,omitempty"`
+	Variable []PostmanKeyValue `json:"variable,omitempty"`
+}
+
+// PostmanBody is the body of a request
+type PostmanBody struct {
+	Mode       string                 `json:"mode"`
+	Raw        string                 `json:"raw,omitempty"`
+	URLEncoded []PostmanKeyValue      `json:"urlencoded,omitempty"`
+	FormData   []PostmanKeyValue      `json:"formdata,omitempty"`
+	Options    map[string]interface{} `json:"options,omitempty"`
+}
+
+// PostmanKeyValue is a header, a query parameter, a form field or a variable
+type PostmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// Postman returns a collection with one request per operation. Requests are in folders
+// by their first tag, and the base url and the server variables are collection variables.
+func Postman(doc swagger.Document, name string) PostmanCollection {
+	reqs, variables := requests(doc)
+	collection := PostmanCollection{
+		Info:     PostmanInfo{Name: name, Description: swagger.String(swagger.Map(doc, "info"), "description"), Schema: PostmanSchema},
+		Item:     []PostmanItem{},
+		Variable: []PostmanKeyValue{},
+	}
+	for _, v := range variables {
+		collection.Variable = append(collection.Variable, PostmanKeyValue{Key: v.Key, Value: v.Value, Type: "string"})
+	}
+
+	folders := map[string]int{}
+	for _, r := range reqs {
+		item := PostmanItem{Name: r.Name, Request: postmanRequest(r), Response: []interface{}{}}
+		if r.Folder == "" {
+			collection.Item = append(collection.Item, item)
+			continue
+		}
+		i, ok := folders[r.Folder]
+		if !ok {
+			i = len(collection.Item)
+			folders[r.Folder] = i
+			collection.Item = append(collection.Item, PostmanItem{Name: r.Folder, Item: []PostmanItem{}})
+		}
+		collection.Item[i].Item = append(collection.Item[i].Item, item)
+	}
+	return collection
+}
+
+// JSON returns the indented collection
+func (collection PostmanCollection) JSON() (string, error) {
+	b, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func postmanRequest(r request) *PostmanRequest {
+	req := &PostmanRequest{
+		Method:      r.Method,
+		Header:      keyValues(r.Headers),
+		Description: r.Description,
+		URL: PostmanURL{
+			Raw:      r.rawURL(func(p param) string { return ":" + p.Key }),
+			Host:     []string{"{{" + BaseURL + "}}"},
+			Path:     []string{},
+			Query:    keyValues(r.Query),
+			Variable: keyValues(r.PathParams),
+		},
+	}
+	for _, segment := range strings.Split(strings.Trim(r.Path, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		for _, p := range r.PathParams {
+			segment = strings.Replace(segment, "{"+p.Key+"}", ":"+p.Key, -1)
+		}
+		req.URL.Path = append(req.URL.Path, segment)
+	}
+	if r.ContentType != "" {
+		req.Header = append(req.Header, PostmanKeyValue{Key: "Content-Type", Value: r.ContentType, Type: "text"})
+	}
+
+	switch r.BodyMode {
+	case modeRaw:
+		req.Body = &PostmanBody{Mode: modeRaw, Raw: r.Body}
+		if isJSON(r.ContentType) {
+			req.Body.Options = map[string]interface{}{"raw": map[string]string{"language": "json"}}
+		}
+	case modeURLEncoded:
+		req.Body = &PostmanBody{Mode: modeURLEncoded, URLEncoded: keyValues(r.Form)}
+	case modeFormData:
+		req.Body = &PostmanBody{Mode: modeFormData, FormData: keyValues(r.Form)}
+	}
+	return req
+}
+
+func keyValues(params []param) []PostmanKeyValue {
+	values := []PostmanKeyValue{}
+	for _, p := range params {
+		values = append(values, PostmanKeyValue{Key: p.Key, Value: p.Value, Type: "text", Disabled: p.Disabled})
+	}
+	return values
+}
@@ -0,0 +1,347 @@
+// Package gateway merges the promoted versions of services into one OpenAPI document for an api gateway.
+package gateway
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// Kinds of conflicts
+const (
+	KindMissing     = "missing"     // the service has no promoted version
+	KindPrefix      = "prefix"      // two services have the same prefix
+	KindPath        = "path"        // a prefixed path is declared by two services
+	KindComponent   = "component"   // a component name is declared by two services with different contents
+	KindOperationId = "operationId" // an operationId is used by two services
+)
+
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Source is the promoted version of a service
+type Source struct {
+	ServiceId string
+	Name      string
+	Version   string
+	Prefix    string // paths of the service are prefixed with it
+	Doc       swagger.Document
+}
+
+// Conflict is a conflict between services. Resolved conflicts are namespaced names,
+// the others are left out of the merged document.
+type Conflict struct {
+	Kind      string `json:"kind"`
+	ServiceId string `json:"serviceId"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+	Resolved  bool   `json:"resolved"`
+}
+
+// Result is a merged document and its conflicts
+type Result struct {
+	Spec      swagger.Document `json:"spec"`
+	Conflicts []Conflict       `json:"conflicts"`
+}
+
+// Unresolved returns the conflicts which are not resolved
+func (result Result) Unresolved() []Conflict {
+	conflicts := []Conflict{}
+	for _, c := range result.Conflicts {
+		if !c.Resolved {
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts
+}
+
+// DefaultPrefix returns the prefix of a service named name ("Pet Store" -> "/pet-store")
+func DefaultPrefix(name string) string {
+	return "/" + strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+}
+
+// namespace returns the prefix of the renamed components of a source
+func namespace(source Source) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(source.Name, "_"), "_")
+	if name == "" {
+		return invalidNameChars.ReplaceAllString(source.ServiceId, "_")
+	}
+	return name
+}
+
+type merger struct {
+	paths      map[string]interface{}
+	components map[string]map[string]interface{}
+	tags       map[string]bool
+	// services of the operationIds and prefixes
+	operationIds map[string]string
+	prefixes     map[string]string
+	conflicts    []Conflict
+}
+
+// Merge merges the sources in order into an OpenAPI 3 document titled title.
+//
+//   - paths are prefixed with the prefix of their service and marked with x-gateway-service
+//   - components with the same name and contents are shared, others are renamed "<service>_<name>"
+//     and the references and security requirements of their service are rewritten
+//   - operationIds used by several services are renamed "<service>_<operationId>"
+//   - the security requirements of documents are copied to their operations
+//   - prefixed paths declared by several services are kept for the first one only
+func Merge(title string, sources []Source) Result {
+	m := &merger{
+		paths:        map[string]interface{}{},
+		components:   map[string]map[string]interface{}{},
+		tags:         map[string]bool{},
+		operationIds: map[string]string{},
+		prefixes:     map[string]string{},
+		conflicts:    []Conflict{},
+	}
+	versions := []string{}
+	sourceList := []interface{}{}
+	tags := []interface{}{}
+	for _, source := range sources {
+		doc := swagger.ConvertToOAS3(source.Doc)
+		upstream := ""
+		if servers := swagger.Slice(doc, "servers"); len(servers) > 0 {
+			server, _ := servers[0].(map[string]interface{})
+			upstream = swagger.String(server, "url")
+		}
+		sourceList = append(sourceList, map[string]interface{}{
+			"serviceId": source.ServiceId,
+			"name":      source.Name,
+			"version":   source.Version,
+			"prefix":    source.Prefix,
+			"upstream":  upstream,
+		})
+		versions = append(versions, source.Name+"@"+source.Version)
+		tags = append(tags, m.merge(source, doc)...)
+	}
+
+	spec := swagger.Document{
+		"openapi": swagger.OpenAPIVersion,
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": strings.Join(versions, ", "),
+		},
+		"paths":             m.paths,
+		"x-gateway-sources": sourceList,
+	}
+	if len(tags) > 0 {
+		spec["tags"] = tags
+	}
+	if len(m.components) > 0 {
+		components := map[string]interface{}{}
+		for section, items := range m.components {
+			components[section] = items
+		}
+		spec["components"] = components
+	}
+	return Result{Spec: spec, Conflicts: m.conflicts}
+}
+
+func (this *merger) conflict(kind string, source Source, name string, resolved bool, format string, args ...interface{}) {
+	this.conflicts = append(this.conflicts, Conflict{
+		Kind:      kind,
+		ServiceId: source.ServiceId,
+		Name:      name,
+		Message:   fmt.Sprintf(format, args...),
+		Resolved:  resolved,
+	})
+}
+
+// merge adds a document and returns its new tags
+func (this *merger) merge(source Source, doc swagger.Document) []interface{} {
+	prefix := strings.TrimSuffix(source.Prefix, "/")
+	if other, ok := this.prefixes[prefix]; ok {
+		this.conflict(KindPrefix, source, source.Prefix, false, "prefix %q is also used by %s", source.Prefix, other)
+	}
+	this.prefixes[prefix] = source.ServiceId
+
+	ns := namespace(source)
+	renames := this.renames(doc, ns)
+	doc = rewrite(doc, renames).(swagger.Document)
+	schemeNames := map[string]string{}
+	for from, to := range renames {
+		if strings.HasPrefix(from, "#/components/securitySchemes/") {
+			schemeNames[unescape(lastToken(from))] = unescape(lastToken(to))
+		}
+	}
+
+	components := swagger.Map(doc, "components")
+	for _, section := range sortedKeys(components) {
+		items := swagger.Map(components, section)
+		if this.components[section] == nil {
+			this.components[section] = map[string]interface{}{}
+		}
+		for _, name := range sortedKeys(items) {
+			ref := "#/components/" + section + "/" + swagger.EscapePointerToken(name)
+			target := name
+			if renamed, ok := renames[ref]; ok {
+				target = unescape(lastToken(renamed))
+			}
+			if existing, ok := this.components[section][target]; ok {
+				if !reflect.DeepEqual(existing, items[name]) {
+					this.conflict(KindComponent, source, section+"/"+name, false, "%s/%s is also declared by another service", section, target)
+				}
+				continue
+			}
+			if target != name {
+				this.conflict(KindComponent, source, section+"/"+name, true, "renamed to %s/%s", section, target)
+			}
+			this.components[section][target] = items[name]
+		}
+	}
+
+	globalSecurity, hasSecurity := doc["security"]
+	paths := swagger.Map(doc, "paths")
+	for _, p := range sortedKeys(paths) {
+		item, _ := paths[p].(map[string]interface{})
+		prefixed := prefix + p
+		if _, ok := this.paths[prefixed]; ok {
+			this.conflict(KindPath, source, prefixed, false, "path %s is also declared by another service", prefixed)
+			continue
+		}
+		merged := map[string]interface{}{"x-gateway-service": source.ServiceId}
+		for key, value := range item {
+			merged[key] = value
+		}
+		for _, method := range swagger.Methods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			op = this.operation(source, ns, op, schemeNames)
+			if _, ok := op["security"]; !ok && hasSecurity {
+				op["security"] = renameSecurity(globalSecurity, schemeNames)
+			}
+			merged[method] = op
+		}
+		this.paths[prefixed] = merged
+	}
+
+	tags := []interface{}{}
+	for _, t := range swagger.Slice(doc, "tags") {
+		tag, _ := t.(map[string]interface{})
+		name := swagger.String(tag, "name")
+		if name != "" && !this.tags[name] {
+			this.tags[name] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// renames returns the references of the components which conflict with the merged ones.
+// Components are compared after renaming, since a component referencing a renamed one differs too.
+func (this *merger) renames(doc swagger.Document, ns string) map[string]string {
+	renames := map[string]string{}
+	components := swagger.Map(doc, "components")
+	for changed := true; changed; {
+		changed = false
+		for section, value := range components {
+			items, _ := value.(map[string]interface{})
+			for name, component := range items {
+				ref := "#/components/" + section + "/" + swagger.EscapePointerToken(name)
+				if _, ok := renames[ref]; ok {
+					continue
+				}
+				existing, ok := this.components[section][name]
+				if ok && !reflect.DeepEqual(existing, rewrite(component, renames)) {
+					renames[ref] = "#/components/" + section + "/" + swagger.EscapePointerToken(ns+"_"+name)
+					changed = true
+				}
+			}
+		}
+	}
+	return renames
+}
+
+// operation copies an operation with a unique operationId and renamed security schemes
+func (this *merger) operation(source Source, ns string, raw map[string]interface{}, schemeNames map[string]string) map[string]interface{} {
+	op := map[string]interface{}{}
+	for key, value := range raw {
+		op[key] = value
+	}
+	if security, ok := op["security"]; ok {
+		op["security"] = renameSecurity(security, schemeNames)
+	}
+	id := swagger.String(op, "operationId")
+	if id == "" {
+		return op
+	}
+	if other, ok := this.operationIds[id]; ok && other != source.ServiceId {
+		renamed := ns + "_" + id
+		this.conflict(KindOperationId, source, id, true, "operationId %s is also used by %s, renamed to %s", id, other, renamed)
+		id = renamed
+		op["operationId"] = id
+	}
+	this.operationIds[id] = source.ServiceId
+	return op
+}
+
+// renameSecurity renames the schemes of security requirements
+func renameSecurity(value interface{}, schemeNames map[string]string) interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	renamed := []interface{}{}
+	for _, r := range list {
+		requirement, _ := r.(map[string]interface{})
+		copied := map[string]interface{}{}
+		for name, scopes := range requirement {
+			if to, ok := schemeNames[name]; ok {
+				name = to
+			}
+			copied[name] = scopes
+		}
+		renamed = append(renamed, copied)
+	}
+	return renamed
+}
+
+// rewrite copies value, replacing renamed references ($ref and discriminator mappings)
+func rewrite(value interface{}, renames map[string]string) interface{} {
+	switch v := value.(type) {
+	case swagger.Document:
+		return swagger.Document(rewrite(map[string]interface{}(v), renames).(map[string]interface{}))
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = rewrite(item, renames)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = rewrite(item, renames)
+		}
+		return copied
+	case string:
+		if renamed, ok := renames[v]; ok {
+			return renamed
+		}
+	}
+	return value
+}
+
+// lastToken returns the last token of a reference
+func lastToken(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func unescape(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
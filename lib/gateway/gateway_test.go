@@ -0,0 +1,127 @@
+package gateway
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var pets = `
+openapi: 3.0.0
+info: {title: pets, version: 1.2.0}
+servers: [{url: 'https://pets.internal/v1'}]
+security: [{token: []}]
+tags: [{name: pets}]
+paths:
+  /pets:
+    get:
+      operationId: list
+      tags: [pets]
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Item'}}
+        default: {$ref: '#/components/responses/Error'}
+components:
+  schemas:
+    Item: {type: object, properties: {name: {type: string}, owner: {$ref: '#/components/schemas/Owner'}}}
+    Owner: {type: object, properties: {name: {type: string}}}
+  responses:
+    Error: {description: error}
+  securitySchemes:
+    token: {type: http, scheme: bearer}
+`
+
+var stores = `
+swagger: '2.0'
+info: {title: stores, version: 2.0.0}
+host: stores.internal
+tags: [{name: pets}, {name: stores}]
+paths:
+  /stores:
+    get:
+      operationId: list
+      security: [{token: []}]
+      responses:
+        '200':
+          description: ok
+          schema: {type: array, items: {$ref: '#/definitions/Item'}}
+  /pets:
+    get:
+      responses: {'200': {description: ok}}
+definitions:
+  Item: {type: object, properties: {name: {type: string}, owner: {$ref: '#/definitions/Owner'}}}
+  Owner: {type: object, properties: {id: {type: integer}}}
+securityDefinitions:
+  token: {type: apiKey, in: header, name: X-Token}
+`
+
+func parse(t *testing.T, contents string) swagger.Document {
+	doc, err := swagger.Parse(common.Yml, contents)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	return doc
+}
+
+func TestMerge(t *testing.T) {
+	result := Merge("edge", []Source{
+		{ServiceId: "s1", Name: "pets", Version: "1.2.0", Prefix: DefaultPrefix("pets"), Doc: parse(t, pets)},
+		{ServiceId: "s2", Name: "stores", Version: "2.0.0", Prefix: "/pets", Doc: parse(t, stores)},
+	})
+	spec := result.Spec
+
+	if info := swagger.Map(spec, "info"); swagger.String(info, "version") != "pets@1.2.0, stores@2.0.0" {
+		t.Errorf("invalid info %v", info)
+	}
+	paths := swagger.Map(spec, "paths")
+	if len(paths) != 2 || paths["/pets/pets"] == nil || paths["/pets/stores"] == nil {
+		t.Fatalf("invalid paths %v", paths)
+	}
+	if service := swagger.String(swagger.Map(paths, "/pets/stores"), "x-gateway-service"); service != "s2" {
+		t.Errorf("invalid service %s", service)
+	}
+
+	// the conflicting schemas are renamed with the ones referencing them, Error is shared
+	schemas := swagger.Map(swagger.Map(spec, "components"), "schemas")
+	if len(schemas) != 4 || schemas["stores_Item"] == nil || schemas["stores_Owner"] == nil {
+		t.Fatalf("invalid schemas %v", schemas)
+	}
+	owner := swagger.Map(swagger.Map(swagger.Map(schemas, "stores_Item"), "properties"), "owner")
+	if ref := swagger.String(owner, "$ref"); ref != "#/components/schemas/stores_Owner" {
+		t.Errorf("invalid ref %s", ref)
+	}
+	stores := swagger.Map(swagger.Map(paths, "/pets/stores"), "get")
+	if swagger.String(stores, "operationId") != "stores_list" {
+		t.Errorf("invalid operation %v", stores)
+	}
+	if !reflect.DeepEqual(stores["security"], []interface{}{map[string]interface{}{"stores_token": []interface{}{}}}) {
+		t.Errorf("invalid security %v", stores["security"])
+	}
+	// the security of documents is copied to operations
+	list := swagger.Map(swagger.Map(paths, "/pets/pets"), "get")
+	if !reflect.DeepEqual(list["security"], []interface{}{map[string]interface{}{"token": []interface{}{}}}) {
+		t.Errorf("invalid security %v", list["security"])
+	}
+	if tags := swagger.Slice(spec, "tags"); len(tags) != 2 {
+		t.Errorf("invalid tags %v", tags)
+	}
+
+	unresolved := result.Unresolved()
+	if len(unresolved) != 2 || unresolved[0].Kind != KindPrefix || unresolved[1].Kind != KindPath || unresolved[1].Name != "/pets/pets" {
+		t.Errorf("invalid conflicts %+v", result.Conflicts)
+	}
+	if len(result.Conflicts) != 6 {
+		t.Errorf("invalid conflicts %+v", result.Conflicts)
+	}
+}
+
+func TestDefaultPrefix(t *testing.T) {
+	if prefix := DefaultPrefix("Pet Store (v2)"); prefix != "/pet-store-v2" {
+		t.Errorf("invalid prefix %s", prefix)
+	}
+}
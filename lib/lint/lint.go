@@ -0,0 +1,172 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"gopkg.in/yaml.v2"
+)
+
+// Severity is the severity of a rule
+type Severity string
+
+// Severities. Rules with SeverityOff are not run.
+const (
+	SeverityError Severity = "error"
+	SeverityWarn  Severity = "warn"
+	SeverityInfo  Severity = "info"
+	SeverityOff   Severity = "off"
+)
+
+// Finding is a violation of a rule
+type Finding struct {
+	Rule      string   `json:"rule"`
+	Severity  Severity `json:"severity"`
+	Message   string   `json:"message"`
+	Path      string   `json:"path"` // JSON pointer in the document
+	Operation string   `json:"operation,omitempty"`
+}
+
+// Result is the result of Lint
+type Result struct {
+	Items    []Finding `json:"Items"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+}
+
+// HasErrors returns true if a rule with SeverityError is violated
+func (result Result) HasErrors() bool {
+	return result.Errors > 0
+}
+
+// RuleConfig configures a rule in a ruleset. It is written as a severity ("warn")
+// or as a map of the severity and the options of the rule ({severity: warn, max: 5}).
+type RuleConfig struct {
+	Severity Severity
+	Options  map[string]interface{}
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (config *RuleConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var severity string
+	if err := unmarshal(&severity); err == nil {
+		config.Severity = Severity(severity)
+		return nil
+	}
+	var options map[string]interface{}
+	if err := unmarshal(&options); err != nil {
+		return err
+	}
+	if severity, ok := options["severity"].(string); ok {
+		config.Severity = Severity(severity)
+	}
+	delete(options, "severity")
+	config.Options = options
+	return nil
+}
+
+// Int returns an integer option or def
+func (config RuleConfig) Int(name string, def int) int {
+	if v, ok := config.Options[name].(int); ok {
+		return v
+	}
+	return def
+}
+
+// Ruleset is the lint configuration of a service.
+// Rules which are not listed run with their default severity.
+//
+//	enforce: true # reject uploads violating error rules
+//	rules:
+//	  operation-summary: off
+//	  inline-schema-properties:
+//	    severity: error
+//	    max: 5
+type Ruleset struct {
+	Enforce bool                  `yaml:"enforce"`
+	Rules   map[string]RuleConfig `yaml:"rules"`
+}
+
+// ParseRuleset parses and validates a YAML ruleset. An empty string is the default ruleset.
+func ParseRuleset(contents string) (*Ruleset, error) {
+	ruleset := &Ruleset{}
+	if err := yaml.UnmarshalStrict([]byte(contents), ruleset); err != nil {
+		return nil, common.NewError(21001, "invalid ruleset", err)
+	}
+	var invalid []string
+	for name, config := range ruleset.Rules {
+		if findRule(name) == nil {
+			invalid = append(invalid, "unknown rule "+name)
+			continue
+		}
+		switch config.Severity {
+		case SeverityError, SeverityWarn, SeverityInfo, SeverityOff:
+		case "":
+			config.Severity = findRule(name).Severity
+			ruleset.Rules[name] = config
+		default:
+			invalid = append(invalid, fmt.Sprintf("invalid severity %s of %s", config.Severity, name))
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, common.NewError(21001, "invalid ruleset: "+strings.Join(invalid, ", "), nil)
+	}
+	return ruleset, nil
+}
+
+// config returns the configuration of a rule in the ruleset
+func (ruleset *Ruleset) config(rule Rule) RuleConfig {
+	if ruleset != nil {
+		if config, ok := ruleset.Rules[rule.Name]; ok {
+			return config
+		}
+	}
+	return RuleConfig{Severity: rule.Severity}
+}
+
+// Lint runs the rules of the ruleset against doc. A nil ruleset runs the rules with their default severities.
+func Lint(doc swagger.Document, ruleset *Ruleset) Result {
+	result := Result{Items: []Finding{}}
+	ops := doc.Operations()
+	for _, rule := range Rules {
+		config := ruleset.config(rule)
+		if config.Severity == SeverityOff {
+			continue
+		}
+		for _, finding := range rule.check(doc, ops, config) {
+			finding.Rule = rule.Name
+			finding.Severity = config.Severity
+			result.Items = append(result.Items, finding)
+			switch config.Severity {
+			case SeverityError:
+				result.Errors++
+			case SeverityWarn:
+				result.Warnings++
+			}
+		}
+	}
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		return result.Items[i].Path < result.Items[j].Path
+	})
+	return result
+}
+
+// Summary returns a short description of the error findings, e.g. for an error message
+func (result Result) Summary(limit int) string {
+	var messages []string
+	for _, f := range result.Items {
+		if f.Severity != SeverityError {
+			continue
+		}
+		if len(messages) == limit {
+			messages = append(messages, "...")
+			break
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s (%s)", f.Rule, f.Message, f.Path))
+	}
+	return fmt.Sprintf("%d errors: %s", result.Errors, strings.Join(messages, ", "))
+}
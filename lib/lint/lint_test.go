@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var lintSource = `
+swagger: '2.0'
+info:
+  title: petstore
+  version: 1.0.0
+securityDefinitions:
+  key:
+    type: apiKey
+    name: X-API-Key
+    in: header
+security:
+  - key: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: list pets
+      tags: [pets]
+      responses:
+        '200':
+          description: ok
+          schema:
+            type: object
+            properties: {a: {}, b: {}, c: {}}
+        default:
+          description: error
+  /petOwners/{ownerId}:
+    get:
+      operationId: listPets
+      security:
+        - oauth: []
+      responses:
+        '200':
+          description: ok
+  /health:
+    get:
+      operationId: health
+      summary: health check
+      tags: [system]
+      security: []
+      responses:
+        '200':
+          description: ok
+        '503':
+          description: unavailable
+`
+
+func TestLint(t *testing.T) {
+	doc, err := swagger.Parse(common.Yml, lintSource)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+
+	result := Lint(doc, nil)
+	found := map[string]Finding{}
+	for _, f := range result.Items {
+		found[f.Rule+" "+f.Path] = f
+	}
+	for _, expected := range []string{
+		"operation-operationid-unique /paths/~1pets/get/operationId",
+		"operation-tags /paths/~1petOwners~1{ownerId}/get",
+		"operation-summary /paths/~1petOwners~1{ownerId}/get",
+		"paths-kebab-case /paths/~1petOwners~1{ownerId}",
+		"operation-error-responses /paths/~1petOwners~1{ownerId}/get",
+		"operation-security /paths/~1petOwners~1{ownerId}/get",
+	} {
+		if _, ok := found[expected]; !ok {
+			t.Errorf("%s is not found in %+v", expected, result.Items)
+		}
+	}
+	if len(result.Items) != 6 || result.Errors != 1 || result.Warnings != 5 {
+		t.Fatalf("invalid result %+v", result)
+	}
+
+	ruleset, err := ParseRuleset(`
+enforce: true
+rules:
+  paths-kebab-case: off
+  operation-summary: error
+  inline-schema-properties:
+    severity: error
+    max: 2
+`)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	result = Lint(doc, ruleset)
+	if !ruleset.Enforce || !result.HasErrors() || result.Errors != 3 || result.Warnings != 3 {
+		t.Fatalf("invalid result %+v", result)
+	}
+	if summary := result.Summary(1); !strings.HasPrefix(summary, "3 errors: ") || !strings.HasSuffix(summary, "...") {
+		t.Fatalf("invalid summary %s", summary)
+	}
+	if f := found["operation-security /paths/~1petOwners~1{ownerId}/get"]; f.Message != "security schemes are not defined: oauth" {
+		t.Fatalf("invalid message %s", f.Message)
+	}
+}
+
+func TestParseRuleset(t *testing.T) {
+	if ruleset, err := ParseRuleset(""); err != nil || ruleset.Enforce {
+		t.Fatalf("empty ruleset must be the default %#v", err)
+	}
+	for _, invalid := range []string{
+		"rules:\n  unknown-rule: error",
+		"rules:\n  operation-tags: fatal",
+		"enforce: [true]",
+		"unknown: true",
+	} {
+		if _, err := ParseRuleset(invalid); err == nil || err.(*common.Error).Code != 21001 {
+			t.Errorf("%s must be invalid %v", invalid, err)
+		}
+	}
+}
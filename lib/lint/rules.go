@@ -0,0 +1,242 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// Rule is a built-in lint rule
+type Rule struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Severity    Severity `json:"severity"` // default severity
+	check       func(doc swagger.Document, ops []swagger.Operation, config RuleConfig) []Finding
+}
+
+// Rules are the built-in rules in the order they run
+var Rules = []Rule{
+	{
+		Name:        "operation-operationid",
+		Description: "every operation has an operationId",
+		Severity:    SeverityError,
+		check: eachOperation(func(op swagger.Operation, _ RuleConfig) string {
+			if op.OperationId == "" {
+				return "operationId is missing"
+			}
+			return ""
+		}),
+	},
+	{
+		Name:        "operation-operationid-unique",
+		Description: "operationIds are unique in the document",
+		Severity:    SeverityError,
+		check:       uniqueOperationIds,
+	},
+	{
+		Name:        "operation-tags",
+		Description: "every operation has at least one tag",
+		Severity:    SeverityWarn,
+		check: eachOperation(func(op swagger.Operation, _ RuleConfig) string {
+			if len(op.Tags) == 0 {
+				return "operation has no tags"
+			}
+			return ""
+		}),
+	},
+	{
+		Name:        "operation-summary",
+		Description: "every operation has a summary",
+		Severity:    SeverityWarn,
+		check: eachOperation(func(op swagger.Operation, _ RuleConfig) string {
+			if strings.TrimSpace(op.Summary) == "" {
+				return "summary is missing"
+			}
+			return ""
+		}),
+	},
+	{
+		Name:        "paths-kebab-case",
+		Description: "path segments are kebab-case",
+		Severity:    SeverityWarn,
+		check:       kebabCasePaths,
+	},
+	{
+		Name:        "operation-error-responses",
+		Description: "every operation defines a 4xx, 5xx or default response",
+		Severity:    SeverityWarn,
+		check: eachOperation(func(op swagger.Operation, _ RuleConfig) string {
+			for code := range op.Responses {
+				if code == "default" || strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5") {
+					return ""
+				}
+			}
+			return "no error response is defined"
+		}),
+	},
+	{
+		Name:        "inline-schema-properties",
+		Description: "inline schemas have at most max (default 10) properties. larger schemas should be components",
+		Severity:    SeverityWarn,
+		check:       inlineSchemas,
+	},
+	{
+		Name:        "operation-security",
+		Description: "every operation declares security with defined schemes",
+		Severity:    SeverityWarn,
+		check:       security,
+	},
+}
+
+func findRule(name string) *Rule {
+	for i := range Rules {
+		if Rules[i].Name == name {
+			return &Rules[i]
+		}
+	}
+	return nil
+}
+
+func operationPointer(op swagger.Operation) string {
+	return "/paths/" + swagger.EscapePointerToken(op.Path) + "/" + op.Method
+}
+
+// eachOperation makes a check which reports a finding for each operation failing fn
+func eachOperation(fn func(op swagger.Operation, config RuleConfig) string) func(swagger.Document, []swagger.Operation, RuleConfig) []Finding {
+	return func(_ swagger.Document, ops []swagger.Operation, config RuleConfig) []Finding {
+		var findings []Finding
+		for _, op := range ops {
+			if message := fn(op, config); message != "" {
+				findings = append(findings, Finding{Message: message, Path: operationPointer(op), Operation: op.Key()})
+			}
+		}
+		return findings
+	}
+}
+
+func uniqueOperationIds(_ swagger.Document, ops []swagger.Operation, _ RuleConfig) []Finding {
+	var findings []Finding
+	first := map[string]swagger.Operation{}
+	for _, op := range ops {
+		if op.OperationId == "" {
+			continue
+		}
+		if other, ok := first[op.OperationId]; ok {
+			findings = append(findings, Finding{
+				Message:   fmt.Sprintf("operationId %s is also used by %s", op.OperationId, other.Key()),
+				Path:      operationPointer(op) + "/operationId",
+				Operation: op.Key(),
+			})
+			continue
+		}
+		first[op.OperationId] = op
+	}
+	return findings
+}
+
+var kebabCase = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func kebabCasePaths(doc swagger.Document, _ []swagger.Operation, _ RuleConfig) []Finding {
+	var findings []Finding
+	paths := swagger.Map(doc, "paths")
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, path := range names {
+		if strings.HasPrefix(path, "x-") {
+			continue
+		}
+		for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+			if segment == "" || strings.HasPrefix(segment, "{") {
+				continue
+			}
+			// file extensions (e.g. /openapi.json) are allowed
+			name := strings.SplitN(segment, ".", 2)[0]
+			if !kebabCase.MatchString(name) {
+				findings = append(findings, Finding{
+					Message: fmt.Sprintf("path segment %s is not kebab-case", segment),
+					Path:    "/paths/" + swagger.EscapePointerToken(path),
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+func inlineSchemas(_ swagger.Document, ops []swagger.Operation, config RuleConfig) []Finding {
+	max := config.Int("max", 10)
+	var findings []Finding
+	for _, op := range ops {
+		var walk func(value interface{}, pointer string)
+		walk = func(value interface{}, pointer string) {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				if _, isRef := v["$ref"]; isRef {
+					return
+				}
+				if properties := swagger.Map(v, "properties"); len(properties) > max {
+					findings = append(findings, Finding{
+						Message:   fmt.Sprintf("inline schema has %d properties (max %d)", len(properties), max),
+						Path:      pointer,
+						Operation: op.Key(),
+					})
+				}
+				for key, elm := range v {
+					if key == "example" || key == "examples" || key == "x-example" {
+						continue
+					}
+					walk(elm, pointer+"/"+swagger.EscapePointerToken(key))
+				}
+			case []interface{}:
+				for i, elm := range v {
+					walk(elm, pointer+"/"+strconv.Itoa(i))
+				}
+			}
+		}
+		walk(op.Raw, operationPointer(op))
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Path < findings[j].Path })
+	return findings
+}
+
+func security(doc swagger.Document, ops []swagger.Operation, _ RuleConfig) []Finding {
+	defined := swagger.Map(doc, "securityDefinitions")
+	if doc.IsOpenAPI3() {
+		defined = swagger.Map(swagger.Map(doc, "components"), "securitySchemes")
+	}
+	var findings []Finding
+	for _, op := range ops {
+		if _, explicit := op.Raw["security"]; explicit && len(op.Security) == 0 {
+			// "security: []" declares a public operation
+			continue
+		}
+		if len(op.Security) == 0 {
+			findings = append(findings, Finding{Message: "security is not declared", Path: operationPointer(op), Operation: op.Key()})
+			continue
+		}
+		var undefined []string
+		for _, requirement := range op.Security {
+			for name := range requirement {
+				if _, ok := defined[name]; !ok {
+					undefined = append(undefined, name)
+				}
+			}
+		}
+		if len(undefined) > 0 {
+			sort.Strings(undefined)
+			findings = append(findings, Finding{
+				Message:   "security schemes are not defined: " + strings.Join(undefined, ", "),
+				Path:      operationPointer(op),
+				Operation: op.Key(),
+			})
+		}
+	}
+	return findings
+}
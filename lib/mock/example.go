@@ -0,0 +1,123 @@
+package mock
+
+import (
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// string examples of well-known formats
+var formatExamples = map[string]string{
+	"date-time": "2020-01-01T00:00:00Z",
+	"date":      "2020-01-01",
+	"time":      "00:00:00",
+	"email":     "user@example.com",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "ZXhhbXBsZQ==",
+	"password":  "********",
+}
+
+// Example returns the example of a schema, or synthesizes one from its type.
+// It returns nil for an empty schema.
+func Example(doc swagger.Document, schema map[string]interface{}) interface{} {
+	s := &synthesizer{doc: doc, expanding: map[string]bool{}}
+	return s.example(schema, 0)
+}
+
+type synthesizer struct {
+	doc swagger.Document
+	// recursive schemas are expanded once
+	expanding map[string]bool
+}
+
+func (this *synthesizer) example(schema map[string]interface{}, depth int) interface{} {
+	if schema == nil || depth > maxDepth {
+		return nil
+	}
+	if ref := swagger.String(schema, "$ref"); ref != "" {
+		if this.expanding[ref] {
+			return nil
+		}
+		this.expanding[ref] = true
+		defer delete(this.expanding, ref)
+		resolved, _ := this.doc.Resolve(ref).(map[string]interface{})
+		return this.example(resolved, depth+1)
+	}
+	for _, key := range []string{"example", "x-example", "default"} {
+		if value, ok := schema[key]; ok {
+			return value
+		}
+	}
+	if enum := swagger.Slice(schema, "enum"); len(enum) > 0 {
+		return enum[0]
+	}
+
+	if allOf := swagger.Slice(schema, "allOf"); len(allOf) > 0 {
+		merged := map[string]interface{}{}
+		for _, s := range allOf {
+			sub, _ := s.(map[string]interface{})
+			if obj, ok := this.example(sub, depth+1).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		if obj, ok := this.objectExample(schema, depth).(map[string]interface{}); ok {
+			for k, v := range obj {
+				merged[k] = v
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alternatives := swagger.Slice(schema, key); len(alternatives) > 0 {
+			sub, _ := alternatives[0].(map[string]interface{})
+			return this.example(sub, depth+1)
+		}
+	}
+
+	switch swagger.String(schema, "type") {
+	case "array":
+		item := this.example(swagger.Map(schema, "items"), depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "string":
+		if s, ok := formatExamples[swagger.String(schema, "format")]; ok {
+			return s
+		}
+		return "string"
+	case "integer", "number":
+		if min, ok := number(schema["minimum"]); ok {
+			return min
+		}
+		return 0
+	case "boolean":
+		return true
+	case "object":
+		return this.objectExample(schema, depth)
+	}
+	if swagger.Map(schema, "properties") != nil {
+		return this.objectExample(schema, depth)
+	}
+	return nil
+}
+
+func (this *synthesizer) objectExample(schema map[string]interface{}, depth int) interface{} {
+	obj := map[string]interface{}{}
+	for name, p := range swagger.Map(schema, "properties") {
+		property, _ := p.(map[string]interface{})
+		// write only properties are not returned
+		if swagger.Bool(property, "writeOnly") {
+			continue
+		}
+		if value := this.example(property, depth+1); value != nil {
+			obj[name] = value
+		}
+	}
+	return obj
+}
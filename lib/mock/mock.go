@@ -0,0 +1,359 @@
+// Package mock responds to requests according to a stored document: operations are
+// routed by their path templates, requests are validated and responses are taken from
+// examples or synthesized from schemas.
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// PreferHeader selects the response of a mock, e.g. "code=404" or "code=200, example=cat"
+const PreferHeader = "Prefer"
+
+// Request is a request to a mock. Path is relative to the mock (e.g. "/pets/1").
+type Request struct {
+	Method  string
+	Path    string
+	Query   map[string]string
+	Headers map[string]string
+	Body    string
+}
+
+// NewRequest converts an http request whose body has been read.
+// Repeated headers and query parameters keep their first value.
+func NewRequest(r *http.Request, body []byte) Request {
+	headers := map[string]string{}
+	for name := range r.Header {
+		headers[name] = r.Header.Get(name)
+	}
+	query := map[string]string{}
+	for name, values := range r.URL.Query() {
+		query[name] = values[0]
+	}
+	return Request{Method: r.Method, Path: r.URL.Path, Query: query, Headers: headers, Body: string(body)}
+}
+
+// Response is a response of a mock
+type Response struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       string
+}
+
+// ErrorBody is the body of the errors of a mock. Violations are the reasons of a 400.
+type ErrorBody struct {
+	Error      common.ErrorElm `json:"error"`
+	Violations []Violation     `json:"violations,omitempty"`
+}
+
+type route struct {
+	operation swagger.Operation
+	pattern   *regexp.Regexp
+	params    []string
+	literals  int
+}
+
+// Mock serves the operations of a document
+type Mock struct {
+	doc      swagger.Document
+	routes   []route
+	prefixes []string
+}
+
+var templateParam = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// New returns the mock of a document
+func New(doc swagger.Document) *Mock {
+	mock := &Mock{doc: doc}
+	for _, op := range doc.Operations() {
+		r := route{operation: op}
+		pattern := "^"
+		last := 0
+		for _, loc := range templateParam.FindAllStringSubmatchIndex(op.Path, -1) {
+			pattern += regexp.QuoteMeta(op.Path[last:loc[0]]) + "([^/]+)"
+			r.params = append(r.params, op.Path[loc[2]:loc[3]])
+			last = loc[1]
+		}
+		pattern += regexp.QuoteMeta(op.Path[last:]) + "$"
+		r.pattern = regexp.MustCompile(pattern)
+		for _, seg := range strings.Split(op.Path, "/") {
+			if seg != "" && !strings.Contains(seg, "{") {
+				r.literals++
+			}
+		}
+		mock.routes = append(mock.routes, r)
+	}
+
+	// requests may include the basePath (2.0) or the path of a server url (3.0)
+	if basePath := strings.TrimRight(swagger.String(doc, "basePath"), "/"); basePath != "" {
+		mock.prefixes = append(mock.prefixes, basePath)
+	}
+	for _, s := range swagger.Slice(doc, "servers") {
+		server, _ := s.(map[string]interface{})
+		if u, err := url.Parse(swagger.String(server, "url")); err == nil {
+			if p := strings.TrimRight(u.Path, "/"); p != "" {
+				mock.prefixes = append(mock.prefixes, p)
+			}
+		}
+	}
+	return mock
+}
+
+// Route finds the operation of a request. It returns nil and methodMatched true
+// if the path matches an operation of another method.
+func (this *Mock) Route(method string, path string) (op *swagger.Operation, pathParams map[string]string, methodMatched bool) {
+	if path == "" {
+		path = "/"
+	}
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	candidates := []string{}
+	for _, prefix := range this.prefixes {
+		if strings.HasPrefix(path, prefix+"/") {
+			candidates = append(candidates, strings.TrimPrefix(path, prefix))
+		}
+	}
+	candidates = append(candidates, path)
+
+	for _, candidate := range candidates {
+		var best *route
+		var bestParams map[string]string
+		for i := range this.routes {
+			r := &this.routes[i]
+			m := r.pattern.FindStringSubmatch(candidate)
+			if m == nil {
+				continue
+			}
+			methodMatched = true
+			if !strings.EqualFold(r.operation.Method, method) {
+				continue
+			}
+			if best == nil || r.literals > best.literals {
+				best, bestParams = r, map[string]string{}
+				for j, name := range r.params {
+					value, err := url.PathUnescape(m[j+1])
+					if err != nil {
+						value = m[j+1]
+					}
+					bestParams[name] = value
+				}
+			}
+		}
+		if best != nil {
+			return &best.operation, bestParams, true
+		}
+	}
+	return nil, nil, methodMatched
+}
+
+// Serve responds to a request
+func (this *Mock) Serve(req Request) Response {
+	op, pathParams, methodMatched := this.Route(req.Method, req.Path)
+	if op == nil {
+		if methodMatched {
+			return errorResponse(405, 1405, "Method Not Allowed", nil)
+		}
+		return errorResponse(404, 1404, "No operation matches "+req.Path, nil)
+	}
+	if violations := this.Validate(*op, pathParams, req); len(violations) > 0 {
+		return errorResponse(400, 1301, "Request Validation Error", violations)
+	}
+
+	prefer := parsePrefer(header(req.Headers, PreferHeader))
+	code, raw := selectResponse(*op, prefer["code"])
+	if raw == nil {
+		return errorResponse(400, 1301, "Response "+prefer["code"]+" is not defined", nil)
+	}
+	statusCode := 200
+	if n, err := strconv.Atoi(code); err == nil {
+		statusCode = n
+	}
+	response, _ := this.doc.Deref(raw).(map[string]interface{})
+
+	headers := map[string]string{}
+	for name, h := range swagger.Map(response, "headers") {
+		// headers have a schema in 3.0 and a type in 2.0
+		h, _ := this.doc.Deref(h).(map[string]interface{})
+		schema := swagger.Map(h, "schema")
+		if schema == nil {
+			schema = h
+		}
+		if example := Example(this.doc, schema); example != nil {
+			headers[name] = scalarString(example)
+		}
+	}
+
+	mediaType, body, ok := this.responseBody(*op, response, header(req.Headers, "Accept"), prefer["example"])
+	if !ok {
+		return Response{StatusCode: statusCode, Headers: headers}
+	}
+	headers["Content-Type"] = mediaType
+	if s, isString := body.(string); isString && !isJSON(mediaType) {
+		return Response{StatusCode: statusCode, Headers: headers, Body: s}
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return errorResponse(500, 1500, "Internal Error", nil)
+	}
+	return Response{StatusCode: statusCode, Headers: headers, Body: string(b)}
+}
+
+// selectResponse returns the preferred response, the lowest 2xx, default or the lowest one
+func selectResponse(op swagger.Operation, preferred string) (string, interface{}) {
+	if preferred != "" {
+		if response, ok := op.Responses[preferred]; ok {
+			return preferred, response
+		}
+		return preferred, op.Responses["default"]
+	}
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			return code, op.Responses[code]
+		}
+	}
+	if response, ok := op.Responses["default"]; ok {
+		return "200", response
+	}
+	if len(codes) > 0 {
+		return codes[0], op.Responses[codes[0]]
+	}
+	// operations without responses return an empty 200
+	return "200", map[string]interface{}{}
+}
+
+// responseBody returns the media type and the body of a response. ok is false if it has no body.
+func (this *Mock) responseBody(op swagger.Operation, response map[string]interface{}, accept string, exampleName string) (string, interface{}, bool) {
+	if this.doc.IsOpenAPI3() {
+		content := swagger.Map(response, "content")
+		if len(content) == 0 {
+			return "", nil, false
+		}
+		types := make([]string, 0, len(content))
+		for t := range content {
+			types = append(types, t)
+		}
+		mediaType := selectMediaType(types, accept)
+		media, _ := content[mediaType].(map[string]interface{})
+		if example, ok := media["example"]; ok {
+			return mediaType, example, true
+		}
+		if examples := swagger.Map(media, "examples"); len(examples) > 0 {
+			names := make([]string, 0, len(examples))
+			for name := range examples {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			name := names[0]
+			if _, ok := examples[exampleName]; ok {
+				name = exampleName
+			}
+			example, _ := this.doc.Deref(examples[name]).(map[string]interface{})
+			return mediaType, example["value"], true
+		}
+		schema := swagger.Map(media, "schema")
+		if schema == nil {
+			return mediaType, nil, false
+		}
+		return mediaType, Example(this.doc, schema), true
+	}
+
+	schema := swagger.Map(response, "schema")
+	examples := swagger.Map(response, "examples")
+	if schema == nil && len(examples) == 0 {
+		return "", nil, false
+	}
+	produces := swagger.Strings(op.Raw, "produces")
+	if len(produces) == 0 {
+		produces = swagger.Strings(this.doc, "produces")
+	}
+	for t := range examples {
+		produces = append(produces, t)
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+	mediaType := selectMediaType(produces, accept)
+	if example, ok := examples[mediaType]; ok {
+		return mediaType, example, true
+	}
+	return mediaType, Example(this.doc, schema), true
+}
+
+// selectMediaType returns the first accepted type, application/json or the first type
+func selectMediaType(types []string, accept string) string {
+	sort.Strings(types)
+	for _, a := range strings.Split(accept, ",") {
+		a = strings.TrimSpace(strings.SplitN(a, ";", 2)[0])
+		if a == "" || a == "*/*" {
+			continue
+		}
+		for _, t := range types {
+			if t == a || (strings.HasSuffix(a, "/*") && strings.HasPrefix(t, strings.TrimSuffix(a, "*"))) {
+				return t
+			}
+		}
+	}
+	for _, t := range types {
+		if isJSON(t) {
+			return t
+		}
+	}
+	return types[0]
+}
+
+func isJSON(mediaType string) bool {
+	mediaType = strings.ToLower(strings.SplitN(mediaType, ";", 2)[0])
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// parsePrefer parses "code=404, example=cat"
+func parsePrefer(value string) map[string]string {
+	prefer := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) == 2 {
+			prefer[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return prefer
+}
+
+// header gets a header case-insensitively
+func header(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+func errorResponse(statusCode int, code int, message string, violations []Violation) Response {
+	body, _ := json.Marshal(ErrorBody{
+		Error: common.ErrorElm{
+			Code:    code,
+			Message: message,
+		},
+		Violations: violations,
+	})
+	return Response{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:       string(body),
+	}
+}
@@ -0,0 +1,223 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var petstore2 = `
+swagger: '2.0'
+info: {title: petstore, version: 1.0.0}
+basePath: /v1
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: query, type: integer, maximum: 100}
+      responses:
+        '200':
+          description: ok
+          schema: {type: array, items: {$ref: '#/definitions/Pet'}}
+    post:
+      parameters:
+        - {name: body, in: body, required: true, schema: {$ref: '#/definitions/Pet'}}
+      responses:
+        '201':
+          description: created
+          headers:
+            Location: {type: string, format: uri}
+        '400': {description: invalid}
+  /pets/mine:
+    get:
+      responses:
+        '200':
+          description: ok
+          examples:
+            application/json: [{id: 1, name: mine}]
+  /pets/{petId}:
+    get:
+      parameters:
+        - {name: petId, in: path, required: true, type: integer}
+      responses:
+        '200': {description: ok, schema: {$ref: '#/definitions/Pet'}}
+        '404': {description: not found}
+definitions:
+  Pet:
+    type: object
+    required: [name]
+    properties:
+      id: {type: integer, format: int64}
+      name: {type: string, example: doggie}
+      status: {type: string, enum: [available, sold]}
+      parent: {$ref: '#/definitions/Pet'}
+`
+
+var petstore3 = `
+openapi: 3.0.0
+info: {title: petstore, version: 2.0.0}
+servers: [{url: 'https://api.example.com/v2'}]
+paths:
+  /pets/{petId}:
+    get:
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: string}}
+        - {name: X-Request-Id, in: header, required: true, schema: {type: string}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              examples:
+                dog: {value: {name: dog}}
+                cat: {$ref: '#/components/examples/cat'}
+components:
+  examples:
+    cat: {value: {name: cat}}
+`
+
+func newMock(t *testing.T, contents string) *Mock {
+	doc, err := swagger.Parse(common.Yml, contents)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	return New(doc)
+}
+
+func decode(t *testing.T, response Response) interface{} {
+	var body interface{}
+	if err := json.Unmarshal([]byte(response.Body), &body); err != nil {
+		t.Fatalf("invalid body %s", response.Body)
+	}
+	return body
+}
+
+func TestRoute(t *testing.T) {
+	mock := newMock(t, petstore2)
+	cases := []struct {
+		method        string
+		path          string
+		expected      string
+		methodMatched bool
+	}{
+		{"GET", "/pets", "/pets", true},
+		{"GET", "/v1/pets/", "/pets", true},
+		{"GET", "/pets/mine", "/pets/mine", true},
+		{"GET", "/pets/1", "/pets/{petId}", true},
+		{"DELETE", "/pets/1", "", true},
+		{"GET", "/stores", "", false},
+	}
+	for _, c := range cases {
+		op, _, methodMatched := mock.Route(c.method, c.path)
+		path := ""
+		if op != nil {
+			path = op.Path
+		}
+		if path != c.expected || methodMatched != c.methodMatched {
+			t.Errorf("%s %s: expected %q but %q", c.method, c.path, c.expected, path)
+		}
+	}
+}
+
+func TestServeSwagger2(t *testing.T) {
+	mock := newMock(t, petstore2)
+
+	response := mock.Serve(Request{Method: "GET", Path: "/pets/1"})
+	pet, _ := decode(t, response).(map[string]interface{})
+	if response.StatusCode != 200 || pet["name"] != "doggie" || pet["status"] != "available" || pet["id"] != float64(0) {
+		t.Fatalf("invalid synthesized body %d %s", response.StatusCode, response.Body)
+	}
+	if parent, _ := pet["parent"].(map[string]interface{}); parent != nil {
+		t.Fatalf("recursive schemas must be expanded once %s", response.Body)
+	}
+
+	response = mock.Serve(Request{Method: "GET", Path: "/pets/mine"})
+	if response.StatusCode != 200 || response.Body != `[{"id":1,"name":"mine"}]` {
+		t.Fatalf("examples must be returned %s", response.Body)
+	}
+
+	response = mock.Serve(Request{Method: "GET", Path: "/pets/1", Headers: map[string]string{"prefer": "code=404"}})
+	if response.StatusCode != 404 || response.Body != "" {
+		t.Fatalf("preferred response must be returned %d %s", response.StatusCode, response.Body)
+	}
+
+	response = mock.Serve(Request{Method: "POST", Path: "/pets", Body: `{"name": "cat"}`})
+	if response.StatusCode != 201 || response.Headers["Location"] != "https://example.com" {
+		t.Fatalf("invalid response %+v", response)
+	}
+
+	response = mock.Serve(Request{Method: "DELETE", Path: "/pets"})
+	if response.StatusCode != 405 {
+		t.Fatalf("expected 405 but %d", response.StatusCode)
+	}
+	response = mock.Serve(Request{Method: "GET", Path: "/stores"})
+	if response.StatusCode != 404 {
+		t.Fatalf("expected 404 but %d", response.StatusCode)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	mock := newMock(t, petstore2)
+	cases := []struct {
+		request  Request
+		expected []string
+	}{
+		{Request{Method: "GET", Path: "/pets/abc"}, []string{"path petId must be a number"}},
+		{Request{Method: "GET", Path: "/pets", Query: map[string]string{"limit": "500"}}, []string{"query limit must be <= 100"}},
+		{Request{Method: "POST", Path: "/pets"}, []string{"body  is required"}},
+		{Request{Method: "POST", Path: "/pets", Body: `{"name": 1`}, []string{"body  is not valid JSON"}},
+		{Request{Method: "POST", Path: "/pets", Body: `{"id": 1.5, "status": "lost", "parent": {}}`}, []string{
+			"body /name is required",
+			"body /id must be an integer",
+			"body /parent/name is required",
+			"body /status must be one of [available sold]",
+		}},
+	}
+	for _, c := range cases {
+		response := mock.Serve(c.request)
+		var body ErrorBody
+		json.Unmarshal([]byte(response.Body), &body)
+		messages := []string{}
+		for _, v := range body.Violations {
+			messages = append(messages, v.In+" "+v.Name+" "+v.Message)
+		}
+		if response.StatusCode != 400 || strings.Join(messages, "\n") != strings.Join(c.expected, "\n") {
+			t.Errorf("%s %s: invalid violations %d %v", c.request.Method, c.request.Path, response.StatusCode, messages)
+		}
+	}
+}
+
+func TestServeOpenAPI3(t *testing.T) {
+	mock := newMock(t, petstore3)
+	headers := map[string]string{"X-Request-Id": "1"}
+
+	response := mock.Serve(Request{Method: "GET", Path: "/v2/pets/1", Headers: headers})
+	if response.StatusCode != 200 || response.Body != `{"name":"cat"}` || response.Headers["Content-Type"] != "application/json" {
+		t.Fatalf("the first example must be returned %+v", response)
+	}
+
+	headers["Prefer"] = "example=dog"
+	response = mock.Serve(Request{Method: "GET", Path: "/pets/1", Headers: headers})
+	if response.Body != `{"name":"dog"}` {
+		t.Fatalf("the preferred example must be returned %s", response.Body)
+	}
+
+	response = mock.Serve(Request{Method: "GET", Path: "/pets/1"})
+	if response.StatusCode != 400 || !strings.Contains(response.Body, `"name":"X-Request-Id"`) {
+		t.Fatalf("required headers must be validated %s", response.Body)
+	}
+}
+
+func TestNewRequest(t *testing.T) {
+	r := httptest.NewRequest("POST", "/v1/pets?limit=1&limit=2", nil)
+	r.Header.Add("X-Trace", "a")
+	r.Header.Add("X-Trace", "b")
+	req := NewRequest(r, []byte(`{"name": "dog"}`))
+	if req.Method != "POST" || req.Path != "/v1/pets" || req.Query["limit"] != "1" || req.Headers["X-Trace"] != "a" || req.Body != `{"name": "dog"}` {
+		t.Fatalf("invalid request %#v", req)
+	}
+}
@@ -0,0 +1,388 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// maxDepth limits $ref resolution and recursion into schemas
+const maxDepth = 32
+
+// Locations of violations
+const (
+	InPath     = "path"
+	InQuery    = "query"
+	InHeader   = "header"
+	InBody     = "body"
+	InResponse = "response"
+)
+
+// Violation is a mismatch between a request (or a response) and the document.
+// Name is the parameter name, or the JSON pointer of a body.
+type Violation struct {
+	In      string `json:"in"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// Validate validates the parameters and the body of a request to an operation
+func (this *Mock) Validate(op swagger.Operation, pathParams map[string]string, req Request) []Violation {
+	violations := []Violation{}
+	var bodySchema map[string]interface{}
+	bodyRequired := false
+	for _, param := range op.Parameters {
+		var value string
+		var present bool
+		switch param.In {
+		case InPath:
+			value, present = pathParams[param.Name]
+		case InQuery:
+			value, present = req.Query[param.Name]
+		case InHeader:
+			value = header(req.Headers, param.Name)
+			present = value != ""
+		case InBody:
+			bodySchema, bodyRequired = swagger.Map(param.Raw, "schema"), param.Required
+			continue
+		default:
+			continue
+		}
+		if !present {
+			if param.Required {
+				violations = append(violations, Violation{In: param.In, Name: param.Name, Message: "is required"})
+			}
+			continue
+		}
+		schema := swagger.Map(param.Raw, "schema")
+		if schema == nil {
+			schema = param.Raw
+		}
+		violations = append(violations, this.validateParameter(param.In, param.Name, schema, value)...)
+	}
+
+	if op.RequestBody != nil {
+		bodyRequired = swagger.Bool(op.RequestBody, "required")
+		content := swagger.Map(op.RequestBody, "content")
+		types := make([]string, 0, len(content))
+		for t := range content {
+			types = append(types, t)
+		}
+		if len(types) > 0 {
+			media, _ := content[selectMediaType(types, header(req.Headers, "Content-Type"))].(map[string]interface{})
+			bodySchema = swagger.Map(media, "schema")
+		}
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		if bodyRequired {
+			violations = append(violations, Violation{In: InBody, Name: "", Message: "is required"})
+		}
+		return violations
+	}
+	contentType := header(req.Headers, "Content-Type")
+	if bodySchema == nil || (contentType != "" && !isJSON(contentType)) {
+		return violations
+	}
+	var body interface{}
+	if err := json.Unmarshal([]byte(req.Body), &body); err != nil {
+		return append(violations, Violation{In: InBody, Name: "", Message: "is not valid JSON"})
+	}
+	return append(violations, ValidateValue(this.doc, InBody, bodySchema, body)...)
+}
+
+// ValidateResponse validates the status code, the content type and the JSON body of a response to an operation.
+// The names of the violations are "status", "content-type" or the JSON pointer of the body.
+func (this *Mock) ValidateResponse(op swagger.Operation, statusCode int, contentType string, body []byte) []Violation {
+	violations := []Violation{}
+	code := strconv.Itoa(statusCode)
+	raw, ok := op.Responses[code]
+	if !ok {
+		raw, ok = op.Responses[code[:1]+"XX"]
+	}
+	if !ok {
+		raw, ok = op.Responses["default"]
+	}
+	if !ok {
+		return append(violations, Violation{In: InResponse, Name: "status", Message: code + " is not defined"})
+	}
+	response, _ := this.doc.Deref(raw).(map[string]interface{})
+
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	var schema map[string]interface{}
+	var types []string
+	if this.doc.IsOpenAPI3() {
+		content := swagger.Map(response, "content")
+		for t := range content {
+			types = append(types, t)
+		}
+		if t, ok := matchMediaType(types, mediaType); ok {
+			media, _ := content[t].(map[string]interface{})
+			schema = swagger.Map(media, "schema")
+		}
+	} else {
+		schema = swagger.Map(response, "schema")
+		if schema != nil {
+			types = swagger.Strings(op.Raw, "produces")
+			if len(types) == 0 {
+				types = swagger.Strings(this.doc, "produces")
+			}
+		}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if schema != nil {
+			violations = append(violations, Violation{In: InResponse, Name: "", Message: "body is empty"})
+		}
+		return violations
+	}
+	if len(types) > 0 {
+		if _, ok := matchMediaType(types, mediaType); !ok {
+			sort.Strings(types)
+			return append(violations, Violation{In: InResponse, Name: "content-type", Message: fmt.Sprintf("%q is not one of %v", mediaType, types)})
+		}
+	}
+	if schema == nil || !isJSON(mediaType) {
+		return violations
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return append(violations, Violation{In: InResponse, Name: "", Message: "body is not valid JSON"})
+	}
+	return append(violations, ValidateValue(this.doc, InResponse, schema, value)...)
+}
+
+// matchMediaType finds mediaType in types, which may contain wildcards ("application/*", "*/*")
+func matchMediaType(types []string, mediaType string) (string, bool) {
+	sort.Strings(types)
+	for _, t := range types {
+		if strings.EqualFold(strings.SplitN(t, ";", 2)[0], mediaType) {
+			return t, true
+		}
+	}
+	for _, t := range types {
+		if t == "*/*" || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*"))) {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// validateParameter converts a raw parameter into its type and validates it
+func (this *Mock) validateParameter(in string, name string, schema map[string]interface{}, raw string) []Violation {
+	var value interface{} = raw
+	resolved, _ := this.doc.Deref(schema).(map[string]interface{})
+	switch swagger.String(resolved, "type") {
+	case "integer", "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return []Violation{{In: in, Name: name, Message: "must be a number"}}
+		}
+		value = n
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []Violation{{In: in, Name: name, Message: "must be a boolean"}}
+		}
+		value = b
+	case "array":
+		// collectionFormat csv (2.0) and style form (3.0) are the defaults
+		values := []interface{}{}
+		for _, v := range strings.Split(raw, ",") {
+			values = append(values, v)
+		}
+		// items stay strings, so only the shape is validated
+		value = values
+		schema = map[string]interface{}{"type": "array"}
+	}
+	violations := ValidateValue(this.doc, in, schema, value)
+	for i := range violations {
+		violations[i].Name = name + violations[i].Name
+	}
+	return violations
+}
+
+// ValidateValue validates a decoded JSON value against a schema. in is set to the violations.
+func ValidateValue(doc swagger.Document, in string, schema map[string]interface{}, value interface{}) []Violation {
+	v := &validator{doc: doc, in: in, violations: []Violation{}}
+	v.validate(schema, value, "", 0)
+	return v.violations
+}
+
+type validator struct {
+	doc        swagger.Document
+	in         string
+	violations []Violation
+}
+
+func (this *validator) add(pointer string, format string, args ...interface{}) {
+	this.violations = append(this.violations, Violation{In: this.in, Name: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+func (this *validator) validate(schema map[string]interface{}, value interface{}, pointer string, depth int) {
+	if schema == nil || depth > maxDepth {
+		return
+	}
+	if ref := swagger.String(schema, "$ref"); ref != "" {
+		resolved, _ := this.doc.Resolve(ref).(map[string]interface{})
+		this.validate(resolved, value, pointer, depth+1)
+		return
+	}
+	if value == nil {
+		if !swagger.Bool(schema, "nullable") && !swagger.Bool(schema, "x-nullable") && swagger.String(schema, "type") != "" {
+			this.add(pointer, "must not be null")
+		}
+		return
+	}
+
+	for _, s := range swagger.Slice(schema, "allOf") {
+		sub, _ := s.(map[string]interface{})
+		this.validate(sub, value, pointer, depth+1)
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		alternatives := swagger.Slice(schema, key)
+		if len(alternatives) == 0 {
+			continue
+		}
+		matched := false
+		for _, s := range alternatives {
+			sub, _ := s.(map[string]interface{})
+			if len(ValidateValue(this.doc, this.in, sub, value)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			this.add(pointer, "does not match any schema of %s", key)
+		}
+	}
+
+	if enum := swagger.Slice(schema, "enum"); len(enum) > 0 {
+		found := false
+		for _, e := range enum {
+			if scalarString(e) == scalarString(value) {
+				found = true
+			}
+		}
+		if !found {
+			this.add(pointer, "must be one of %v", enum)
+		}
+	}
+
+	typ := swagger.String(schema, "type")
+	if typ == "" && swagger.Map(schema, "properties") != nil {
+		typ = "object"
+	}
+	switch typ {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			this.add(pointer, "must be an object")
+			return
+		}
+		for _, name := range swagger.Strings(schema, "required") {
+			if _, ok := obj[name]; !ok {
+				this.add(pointer+"/"+swagger.EscapePointerToken(name), "is required")
+			}
+		}
+		properties := swagger.Map(schema, "properties")
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := pointer + "/" + swagger.EscapePointerToken(name)
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				this.validate(property, obj[name], child, depth+1)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					this.add(child, "is not allowed")
+				}
+			case map[string]interface{}:
+				this.validate(additional, obj[name], child, depth+1)
+			}
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			this.add(pointer, "must be an array")
+			return
+		}
+		if n, ok := number(schema["minItems"]); ok && float64(len(arr)) < n {
+			this.add(pointer, "must have at least %v items", n)
+		}
+		if n, ok := number(schema["maxItems"]); ok && float64(len(arr)) > n {
+			this.add(pointer, "must have at most %v items", n)
+		}
+		items := swagger.Map(schema, "items")
+		for i, item := range arr {
+			this.validate(items, item, pointer+"/"+strconv.Itoa(i), depth+1)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			this.add(pointer, "must be a string")
+			return
+		}
+		if n, ok := number(schema["minLength"]); ok && float64(len([]rune(s))) < n {
+			this.add(pointer, "must be at least %v characters", n)
+		}
+		if n, ok := number(schema["maxLength"]); ok && float64(len([]rune(s))) > n {
+			this.add(pointer, "must be at most %v characters", n)
+		}
+		if pattern := swagger.String(schema, "pattern"); pattern != "" {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(s) {
+				this.add(pointer, "must match %s", pattern)
+			}
+		}
+	case "integer", "number":
+		n, ok := number(value)
+		if !ok {
+			this.add(pointer, "must be a %s", typ)
+			return
+		}
+		if typ == "integer" && n != float64(int64(n)) {
+			this.add(pointer, "must be an integer")
+		}
+		if min, ok := number(schema["minimum"]); ok && n < min {
+			this.add(pointer, "must be >= %v", min)
+		}
+		if max, ok := number(schema["maximum"]); ok && n > max {
+			this.add(pointer, "must be <= %v", max)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			this.add(pointer, "must be a boolean")
+		}
+	}
+}
+
+// number converts the numbers of JSON and YAML documents
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func scalarString(value interface{}) string {
+	if n, ok := number(value); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
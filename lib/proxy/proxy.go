@@ -0,0 +1,270 @@
+// Package proxy forwards requests to an upstream and validates requests and responses
+// against a stored document (contract testing). Violations are logged, kept in a report
+// and optionally rejected. Bodies larger than MaxBodySize are passed through without being validated.
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/mock"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// DefaultReportPath is the path of the violation report
+const DefaultReportPath = "/__violations"
+
+// DefaultReportSize is the number of records kept in the report
+const DefaultReportSize = 1000
+
+// DefaultMaxBodySize is the max size of the request and response bodies which are validated (10MB)
+const DefaultMaxBodySize = 10 << 20
+
+// Record is an exchange with violations
+type Record struct {
+	Timestamp  int64            `json:"timestamp"` // unix time in milliseconds
+	Method     string           `json:"method"`
+	Path       string           `json:"path"`
+	Operation  string           `json:"operation,omitempty"` // "METHOD template" of the matched operation
+	StatusCode int              `json:"statuscode,omitempty"`
+	Rejected   bool             `json:"rejected"`
+	Violations []mock.Violation `json:"violations"`
+}
+
+// Report is the response of the report path. Total counts the records which are no longer kept.
+type Report struct {
+	Items []Record `json:"Items"`
+	Total int      `json:"Total"`
+}
+
+// Proxy is an http.Handler validating the traffic to Target
+type Proxy struct {
+	Target *url.URL
+	// Reject responds 400 to invalid requests and 502 to invalid responses instead of forwarding them
+	Reject bool
+	// ReportPath serves the report (GET) and clears it (DELETE). "" disables it.
+	ReportPath string
+	// ReportSize is the max number of records kept in the report
+	ReportSize int
+	// MaxBodySize is the max size of the bodies buffered for validation. Larger ones are passed through.
+	MaxBodySize int64
+	Logger      *log.Logger
+
+	mock    *mock.Mock
+	reverse *httputil.ReverseProxy
+
+	lock    sync.Mutex
+	records []Record
+	total   int
+}
+
+// New returns a proxy to target validating against doc
+func New(doc swagger.Document, target *url.URL) *Proxy {
+	reverse := httputil.NewSingleHostReverseProxy(target)
+	director := reverse.Director
+	reverse.Director = func(r *http.Request) {
+		director(r)
+		// bodies are validated, so they must not be compressed
+		r.Header.Del("Accept-Encoding")
+	}
+	reverse.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		w.(*bufferedWriter).err = err
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	return &Proxy{
+		Target:      target,
+		ReportPath:  DefaultReportPath,
+		ReportSize:  DefaultReportSize,
+		MaxBodySize: DefaultMaxBodySize,
+		Logger:      log.New(ioutil.Discard, "", 0),
+		mock:        mock.New(doc),
+		reverse:     reverse,
+	}
+}
+
+func (this *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if this.ReportPath != "" && r.URL.Path == this.ReportPath {
+		this.serveReport(w, r)
+		return
+	}
+
+	// the bytes read beyond the limit are kept, so that an oversized body is forwarded as it is
+	var read bytes.Buffer
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, ioutil.NopCloser(io.TeeReader(r.Body, &read)), this.MaxBodySize))
+	if err != nil && int64(len(body)) < this.MaxBodySize {
+		writeError(w, 400, common.ErrorElm{Code: 1301, Message: "Invalid Body"}, nil)
+		return
+	}
+	if err != nil {
+		this.Logger.Printf("request body too large, not validated: %s %s", r.Method, r.URL.Path)
+		r.Body = ioutil.NopCloser(io.MultiReader(&read, r.Body))
+		this.forward(w, r)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	record := Record{Timestamp: time.Now().UnixNano() / int64(time.Millisecond), Method: r.Method, Path: r.URL.Path}
+	op, pathParams, methodMatched := this.mock.Route(r.Method, r.URL.Path)
+	if op == nil {
+		message := "no operation matches the path"
+		if methodMatched {
+			message = "the method is not defined for the path"
+		}
+		record.Violations = []mock.Violation{{In: mock.InPath, Name: r.URL.Path, Message: message}}
+	} else {
+		record.Operation = op.Key()
+		record.Violations = this.mock.Validate(*op, pathParams, mock.NewRequest(r, body))
+	}
+
+	if len(record.Violations) > 0 && this.Reject {
+		record.Rejected = true
+		this.add(record)
+		writeError(w, 400, common.ErrorElm{Code: 1301, Message: "Request Validation Error"}, record.Violations)
+		return
+	}
+
+	// the response is validated before it is written, so that invalid ones can be rejected
+	recorder := &bufferedWriter{w: w, limit: this.MaxBodySize, header: http.Header{}}
+	this.reverse.ServeHTTP(recorder, r)
+	record.StatusCode = recorder.statusCode
+	if recorder.passed {
+		// the response has been written to w
+		this.Logger.Printf("response body too large, not validated: %s %s", r.Method, r.URL.Path)
+		if len(record.Violations) > 0 {
+			this.add(record)
+		}
+		return
+	}
+	if recorder.err != nil {
+		this.Logger.Printf("upstream error: %s %s: %v", r.Method, r.URL.Path, recorder.err)
+	} else if op != nil {
+		record.Violations = append(record.Violations, this.mock.ValidateResponse(*op, recorder.statusCode, recorder.header.Get("Content-Type"), recorder.body.Bytes())...)
+	}
+
+	if len(record.Violations) > 0 {
+		if this.Reject {
+			record.Rejected = true
+			this.add(record)
+			writeError(w, 502, common.ErrorElm{Code: 1500, Message: "Response Validation Error"}, record.Violations)
+			return
+		}
+		this.add(record)
+	}
+	recorder.flush()
+}
+
+// forward passes the exchange through without validating it
+func (this *Proxy) forward(w http.ResponseWriter, r *http.Request) {
+	recorder := &bufferedWriter{w: w, header: http.Header{}}
+	this.reverse.ServeHTTP(recorder, r)
+	if recorder.err != nil {
+		this.Logger.Printf("upstream error: %s %s: %v", r.Method, r.URL.Path, recorder.err)
+	}
+	if !recorder.passed {
+		recorder.flush()
+	}
+}
+
+// Records returns the records kept in the report
+func (this *Proxy) Records() []Record {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]Record{}, this.records...)
+}
+
+func (this *Proxy) add(record Record) {
+	for _, v := range record.Violations {
+		this.Logger.Printf("violation: %s %s: %s %s %s", record.Method, record.Path, v.In, v.Name, v.Message)
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.records = append(this.records, record)
+	if this.ReportSize > 0 && len(this.records) > this.ReportSize {
+		this.records = this.records[len(this.records)-this.ReportSize:]
+	}
+	this.total++
+}
+
+func (this *Proxy) serveReport(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		this.lock.Lock()
+		report := Report{Items: append([]Record{}, this.records...), Total: this.total}
+		this.lock.Unlock()
+		b, _ := json.Marshal(report)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(b)
+	case "DELETE":
+		this.lock.Lock()
+		this.records, this.total = nil, 0
+		this.lock.Unlock()
+		w.WriteHeader(204)
+	default:
+		writeError(w, 405, common.ErrorElm{Code: 1405, Message: "Method Not Allowed"}, nil)
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, elm common.ErrorElm, violations []mock.Violation) {
+	b, _ := json.Marshal(mock.ErrorBody{Error: elm, Violations: violations})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	w.Write(b)
+}
+
+// bufferedWriter keeps the response of the upstream.
+// A body larger than limit is no longer kept: it is passed through to w.
+type bufferedWriter struct {
+	w          http.ResponseWriter
+	limit      int64
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+	passed     bool  // the response is being written to w
+	err        error // the upstream is not reachable
+}
+
+func (this *bufferedWriter) Header() http.Header {
+	return this.header
+}
+
+func (this *bufferedWriter) WriteHeader(statusCode int) {
+	if this.statusCode == 0 {
+		this.statusCode = statusCode
+	}
+}
+
+func (this *bufferedWriter) Write(b []byte) (int, error) {
+	if this.statusCode == 0 {
+		this.statusCode = http.StatusOK
+	}
+	if !this.passed && int64(this.body.Len()+len(b)) > this.limit {
+		this.flush()
+	}
+	if this.passed {
+		return this.w.Write(b)
+	}
+	return this.body.Write(b)
+}
+
+// flush writes the response kept so far to w
+func (this *bufferedWriter) flush() {
+	if this.statusCode == 0 {
+		this.statusCode = http.StatusOK
+	}
+	for name, values := range this.header {
+		this.w.Header()[name] = values
+	}
+	this.w.WriteHeader(this.statusCode)
+	this.w.Write(this.body.Bytes())
+	this.body.Reset()
+	this.passed = true
+}
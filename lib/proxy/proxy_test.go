@@ -0,0 +1,154 @@
+package proxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var petstore = `
+openapi: 3.0.0
+info: {title: petstore, version: 1.0.0}
+paths:
+  /pets/{petId}:
+    get:
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: integer}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name: {type: string}
+        '404': {description: not found}
+`
+
+func newProxy(t *testing.T) (*Proxy, func()) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pets/1":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name": "dog"}`))
+		case "/pets/2":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name": 2}`))
+		case "/pets/3":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(`dog`))
+		case "/echo":
+			io.Copy(w, r.Body)
+		default:
+			w.WriteHeader(500)
+		}
+	}))
+	doc, err := swagger.Parse(common.Yml, petstore)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	target, _ := url.Parse(upstream.URL)
+	return New(doc, target), upstream.Close
+}
+
+func get(p *Proxy, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w
+}
+
+func TestProxy(t *testing.T) {
+	p, closer := newProxy(t)
+	defer closer()
+
+	if w := get(p, "/pets/1"); w.Code != 200 || w.Body.String() != `{"name": "dog"}` {
+		t.Fatalf("valid exchanges must be forwarded %d %s", w.Code, w.Body.String())
+	}
+	if len(p.Records()) != 0 {
+		t.Fatalf("valid exchanges must not be recorded %+v", p.Records())
+	}
+
+	// violations are only recorded without Reject
+	for _, path := range []string{"/pets/2", "/pets/3", "/pets/4", "/pets/abc", "/stores"} {
+		get(p, path)
+	}
+	expected := []string{
+		"response /name must be a string",
+		`response content-type "text/plain" is not one of [application/json]`,
+		"response status 500 is not defined",
+		"path petId must be a number",
+		"response status 500 is not defined",
+		"path /stores no operation matches the path",
+	}
+	records := p.Records()
+	messages := []string{}
+	for _, r := range records {
+		for _, v := range r.Violations {
+			messages = append(messages, v.In+" "+v.Name+" "+v.Message)
+		}
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("invalid violations\n%s", strings.Join(messages, "\n"))
+	}
+	if records[0].Operation != "GET /pets/{petId}" || records[0].StatusCode != 200 || records[0].Rejected {
+		t.Fatalf("invalid record %+v", records[0])
+	}
+
+	w := get(p, DefaultReportPath)
+	var report Report
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil || report.Total != 5 || len(report.Items) != 5 {
+		t.Fatalf("invalid report %s", w.Body.String())
+	}
+}
+
+func TestProxyReject(t *testing.T) {
+	p, closer := newProxy(t)
+	defer closer()
+	p.Reject = true
+	p.ReportSize = 1
+
+	if w := get(p, "/pets/abc"); w.Code != 400 || !strings.Contains(w.Body.String(), `"violations"`) {
+		t.Fatalf("invalid requests must be rejected %d %s", w.Code, w.Body.String())
+	}
+	if w := get(p, "/pets/2"); w.Code != 502 {
+		t.Fatalf("invalid responses must be rejected %d %s", w.Code, w.Body.String())
+	}
+	if records := p.Records(); len(records) != 1 || !records[0].Rejected || records[0].Path != "/pets/2" {
+		t.Fatalf("only the latest records must be kept %+v", records)
+	}
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("DELETE", DefaultReportPath, nil))
+	if w.Code != 204 || len(p.Records()) != 0 {
+		t.Fatalf("report must be cleared")
+	}
+}
+
+func TestProxyLargeBody(t *testing.T) {
+	p, closer := newProxy(t)
+	defer closer()
+	p.Reject = true
+	p.MaxBodySize = 8
+
+	if w := get(p, "/pets/2"); w.Code != 200 || w.Body.String() != `{"name": 2}` {
+		t.Fatalf("large responses must be passed through %d %s", w.Code, w.Body.String())
+	}
+	body := strings.Repeat("0123456789", 10)
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("POST", "/echo", strings.NewReader(body)))
+	if w.Code != 200 || w.Body.String() != body {
+		t.Fatalf("large requests must be passed through %d %s", w.Code, w.Body.String())
+	}
+	if len(p.Records()) != 0 {
+		t.Fatalf("large bodies must not be validated %+v", p.Records())
+	}
+}
@@ -0,0 +1,155 @@
+// Package review implements the review policy of services: versions uploaded to a service with
+// a policy are pending until reviewers approve them, and only approved versions are published.
+package review
+
+import (
+	"fmt"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+)
+
+// Statuses of a review
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Decisions of a reviewer
+const (
+	DecisionApprove = "approve"
+	DecisionReject  = "reject"
+)
+
+// MaxApprovals is the maximum number of approvals a policy can require
+const MaxApprovals = 10
+
+// Error codes
+const (
+	CodeInvalid    = 27001 // invalid policy or decision
+	CodeForbidden  = 27002 // the principal may not review the version
+	CodeNotPending = 27003 // the version is not pending
+	CodeUnapproved = 27004 // the version is not approved
+)
+
+// ValidatePolicy returns an error if the policy is invalid. A policy without approvals removes the policy,
+// and the reviewers of a policy must be listed: being granted the namespace does not make a caller a reviewer.
+func ValidatePolicy(policy servicedb.ReviewPolicy) error {
+	if policy.Approvals < 0 || policy.Approvals > MaxApprovals {
+		return common.NewError(CodeInvalid, fmt.Sprintf("approvals must be 0 to %d", MaxApprovals), nil)
+	}
+	seen := map[string]bool{}
+	for _, reviewer := range policy.Reviewers {
+		if reviewer == "" || seen[reviewer] {
+			return common.NewError(CodeInvalid, "reviewers must be unique principals", nil)
+		}
+		seen[reviewer] = true
+	}
+	if policy.Approvals > len(policy.Reviewers) {
+		return common.NewError(CodeInvalid, fmt.Sprintf("%d approvals require as many reviewers", policy.Approvals), nil)
+	}
+	return nil
+}
+
+// Published returns true if the version is not under review or approved
+func Published(version versiondb.VersionEntity) bool {
+	return version.Review == nil || version.Review.Status == StatusApproved
+}
+
+// Start puts an uploaded version under review: it is disabled and not tagged prod until approved.
+// The requested state is applied on approval.
+func Start(version *versiondb.VersionEntity, uploader string) {
+	version.Review = &versiondb.Review{
+		Status:    StatusPending,
+		Uploader:  uploader,
+		Enable:    version.Enable,
+		Tag:       version.Tag,
+		Decisions: []versiondb.ReviewDecision{},
+	}
+	version.Enable = false
+	if version.Tag == versiondb.PromotedTag {
+		version.Tag = ""
+	}
+}
+
+// Decide records the decision of a reviewer on a pending version. A rejection rejects the version, and the
+// version is approved once the approvals of distinct reviewers reach the policy. An approved version gets
+// the state requested on upload. A later decision of the same reviewer replaces the earlier one.
+func Decide(policy servicedb.ReviewPolicy, version *versiondb.VersionEntity, principal string, decision string, comment string, now int64) error {
+	if version.Review == nil || version.Review.Status != StatusPending {
+		return common.NewError(CodeNotPending, "version is not pending review", nil)
+	}
+	if decision != DecisionApprove && decision != DecisionReject {
+		return common.NewError(CodeInvalid, "decision must be approve or reject", nil)
+	}
+	if decision == DecisionReject && comment == "" {
+		return common.NewError(CodeInvalid, "a rejection requires a comment", nil)
+	}
+	if len(policy.Reviewers) == 0 {
+		return common.NewError(CodeForbidden, "the review policy of the service lists no reviewers", nil)
+	}
+	if !allowed(policy, principal) {
+		return common.NewError(CodeForbidden, principal+" is not a reviewer of the service", nil)
+	}
+	if decision == DecisionApprove && principal == version.Review.Uploader && !policy.SelfApproval {
+		return common.NewError(CodeForbidden, "the uploader can not approve the version", nil)
+	}
+
+	review := version.Review
+	decisions := []versiondb.ReviewDecision{}
+	for _, d := range review.Decisions {
+		if d.Principal != principal {
+			decisions = append(decisions, d)
+		}
+	}
+	review.Decisions = append(decisions, versiondb.ReviewDecision{
+		Principal: principal,
+		Decision:  decision,
+		Comment:   comment,
+		Timestamp: now,
+	})
+	review.Revision++
+
+	if decision == DecisionReject {
+		review.Status = StatusRejected
+		return nil
+	}
+	approvals := 0
+	for _, d := range review.Decisions {
+		if d.Decision == DecisionApprove {
+			approvals++
+		}
+	}
+	required := policy.Approvals
+	if required < 1 {
+		required = 1
+	}
+	if approvals >= required {
+		review.Status = StatusApproved
+		version.Enable = review.Enable
+		version.Tag = review.Tag
+	}
+	return nil
+}
+
+// CheckUpdate returns an error if an update enables or promotes a version which is not approved
+func CheckUpdate(before versiondb.VersionEntity, enable bool, tag string) error {
+	if Published(before) {
+		return nil
+	}
+	if enable || tag == versiondb.PromotedTag {
+		return common.NewError(CodeUnapproved, "version "+before.Version+" is "+before.Review.Status+": only approved versions can be enabled or tagged "+versiondb.PromotedTag, nil)
+	}
+	return nil
+}
+
+func allowed(policy servicedb.ReviewPolicy, principal string) bool {
+	for _, reviewer := range policy.Reviewers {
+		if reviewer == principal {
+			return true
+		}
+	}
+	return false
+}
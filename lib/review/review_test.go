@@ -0,0 +1,108 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+)
+
+func code(err error) int {
+	if err == nil {
+		return 0
+	}
+	return err.(*common.Error).Code
+}
+
+func TestPolicy(t *testing.T) {
+	for _, policy := range []servicedb.ReviewPolicy{
+		{Approvals: 0},
+		{Approvals: 2, Reviewers: []string{"alice", "bob"}},
+	} {
+		if err := ValidatePolicy(policy); err != nil {
+			t.Errorf("%+v must be valid %#v", policy, err)
+		}
+	}
+	for _, policy := range []servicedb.ReviewPolicy{
+		{Approvals: -1},
+		{Approvals: MaxApprovals + 1},
+		{Approvals: 1},
+		{Approvals: 3, Reviewers: []string{"alice", "bob"}},
+		{Approvals: 1, Reviewers: []string{"alice", "alice"}},
+	} {
+		if err := ValidatePolicy(policy); code(err) != CodeInvalid {
+			t.Errorf("%+v must be invalid", policy)
+		}
+	}
+}
+
+func TestReview(t *testing.T) {
+	version := versiondb.VersionEntity{ID: "s1", Version: "1.0.0", Enable: true, Tag: "prod"}
+	Start(&version, "carol")
+	if version.Enable || version.Tag != "" || Published(version) || version.Review.Status != StatusPending {
+		t.Fatalf("invalid pending version %+v", version)
+	}
+	if err := CheckUpdate(version, true, "latest"); code(err) != CodeUnapproved {
+		t.Errorf("a pending version can not be enabled %#v", err)
+	}
+	if err := CheckUpdate(version, false, "prod"); code(err) != CodeUnapproved {
+		t.Errorf("a pending version can not be promoted %#v", err)
+	}
+	if err := CheckUpdate(version, false, "beta"); err != nil {
+		t.Errorf("failed test %#v", err)
+	}
+
+	policy := servicedb.ReviewPolicy{Approvals: 2, Reviewers: []string{"alice", "bob", "carol"}}
+	if err := Decide(policy, &version, "mallory", DecisionApprove, "", 1); code(err) != CodeForbidden {
+		t.Errorf("mallory is not a reviewer %#v", err)
+	}
+	if err := Decide(policy, &version, "carol", DecisionApprove, "", 1); code(err) != CodeForbidden {
+		t.Errorf("the uploader can not approve %#v", err)
+	}
+	if err := Decide(policy, &version, "alice", "maybe", "", 1); code(err) != CodeInvalid {
+		t.Errorf("invalid decision %#v", err)
+	}
+	if err := Decide(policy, &version, "alice", DecisionReject, "", 1); code(err) != CodeInvalid {
+		t.Errorf("a rejection requires a comment %#v", err)
+	}
+
+	// the second approval of alice replaces the first one
+	for _, principal := range []string{"alice", "alice"} {
+		if err := Decide(policy, &version, principal, DecisionApprove, "lgtm", 2); err != nil {
+			t.Fatalf("failed test %#v", err)
+		}
+	}
+	if version.Review.Status != StatusPending || len(version.Review.Decisions) != 1 || version.Review.Revision != 2 {
+		t.Fatalf("one approval is missing %+v", version.Review)
+	}
+	if err := Decide(policy, &version, "bob", DecisionApprove, "", 3); err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if !Published(version) || !version.Enable || version.Tag != "prod" {
+		t.Fatalf("the requested state must be applied %+v", version)
+	}
+	if err := Decide(policy, &version, "bob", DecisionReject, "too late", 4); code(err) != CodeNotPending {
+		t.Errorf("an approved version is not pending %#v", err)
+	}
+
+	// a rejection is final, the version is uploaded again to be reviewed again
+	rejected := versiondb.VersionEntity{ID: "s1", Version: "1.1.0", Enable: true, Tag: "latest"}
+	Start(&rejected, "carol")
+	if err := Decide(servicedb.ReviewPolicy{}, &rejected, "dave", DecisionReject, "no reviewers", 5); code(err) != CodeForbidden {
+		t.Errorf("a policy without reviewers allows nobody %#v", err)
+	}
+	if err := Decide(servicedb.ReviewPolicy{Approvals: 1, Reviewers: []string{"dave"}}, &rejected, "dave", DecisionReject, "breaks clients", 5); err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if rejected.Review.Status != StatusRejected || rejected.Enable || rejected.Tag != "latest" || rejected.Review.Decisions[0].Comment != "breaks clients" {
+		t.Errorf("invalid rejected version %+v", rejected)
+	}
+
+	// self approval
+	self := versiondb.VersionEntity{ID: "s1", Version: "1.2.0", Enable: true}
+	Start(&self, "carol")
+	if err := Decide(servicedb.ReviewPolicy{Approvals: 1, Reviewers: []string{"carol"}, SelfApproval: true}, &self, "carol", DecisionApprove, "", 6); err != nil || !self.Enable {
+		t.Errorf("failed test %#v %+v", err, self)
+	}
+}
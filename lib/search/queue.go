@@ -0,0 +1,76 @@
+package search
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// Job asks the worker to bring the index of a version up to date with the stored version:
+// it is indexed while the version and its service exist, and removed from the index otherwise.
+// An empty Version stands for every version of the service.
+type Job struct {
+	Serviceid string `json:"serviceid"`
+	Version   string `json:"version,omitempty"`
+}
+
+// Queue queues index jobs
+type Queue interface {
+	Enqueue(job Job) error
+}
+
+// SQSQueue sends jobs to a SQS queue
+type SQSQueue struct {
+	queueUrl  string
+	sqsClient *sqs.SQS
+}
+
+// NewSQSQueue return SQS Queue
+func NewSQSQueue(queueUrl string) (*SQSQueue, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+	return &SQSQueue{
+		queueUrl:  queueUrl,
+		sqsClient: sqs.New(cfg),
+	}, nil
+}
+
+// Enqueue sends the job to the queue
+func (this *SQSQueue) Enqueue(job Job) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	message, err := json.Marshal(job)
+	if err != nil {
+		return common.NewError(101, "marshal error", err)
+	}
+	if _, err := this.sqsClient.SendMessageRequest(&sqs.SendMessageInput{
+		QueueUrl:    aws.String(this.queueUrl),
+		MessageBody: aws.String(string(message)),
+	}).Send(); err != nil {
+		return common.NewError(311, "sqs sendmessage error", err)
+	}
+	return nil
+}
+
+// NopQueue discards jobs
+type NopQueue struct{}
+
+// Enqueue does nothing
+func (NopQueue) Enqueue(job Job) error {
+	return nil
+}
+
+// NewQueueFromEnv returns the SQS queue of SEARCH_QUEUE_URL. Jobs are discarded if it is not set.
+func NewQueueFromEnv() (Queue, error) {
+	if url := os.Getenv("SEARCH_QUEUE_URL"); url != "" {
+		return NewSQSQueue(url)
+	}
+	return NopQueue{}, nil
+}
@@ -0,0 +1,160 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+// Kinds of entries
+const (
+	KindPath      = "path"
+	KindOperation = "operation"
+	KindSchema    = "schema"
+	KindProperty  = "property"
+)
+
+// MaxTextLength is the max length of the text of an entry. Longer descriptions are truncated.
+const MaxTextLength = 200
+
+// Entry is a searchable element of a document.
+// Name is the operationId, the schema name or "Schema.property".
+type Entry struct {
+	Kind   string `json:"kind"`
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+// Hit is an entry matching a query
+type Hit struct {
+	Entry
+	Score int `json:"score"`
+}
+
+// Extract extracts the path templates, operations, schemas and properties of a document
+func Extract(doc swagger.Document) []Entry {
+	entries := []Entry{}
+	paths := map[string]bool{}
+	for _, op := range doc.Operations() {
+		if !paths[op.Path] {
+			paths[op.Path] = true
+			entries = append(entries, Entry{Kind: KindPath, Path: op.Path})
+		}
+		entries = append(entries, Entry{
+			Kind:   KindOperation,
+			Method: strings.ToUpper(op.Method),
+			Path:   op.Path,
+			Name:   op.OperationId,
+			Text:   truncate(strings.TrimSpace(op.Summary + " " + op.Description)),
+		})
+	}
+
+	schemas := doc.Schemas()
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schema, _ := schemas[name].(map[string]interface{})
+		entries = append(entries, Entry{Kind: KindSchema, Name: name, Text: truncate(swagger.String(schema, "description"))})
+		properties := swagger.Map(schema, "properties")
+		props := make([]string, 0, len(properties))
+		for prop := range properties {
+			props = append(props, prop)
+		}
+		sort.Strings(props)
+		for _, prop := range props {
+			property, _ := properties[prop].(map[string]interface{})
+			entries = append(entries, Entry{Kind: KindProperty, Name: name + "." + prop, Text: truncate(swagger.String(property, "description"))})
+		}
+	}
+	return entries
+}
+
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= MaxTextLength {
+		return s
+	}
+	return string(runes[:MaxTextLength])
+}
+
+// Tokenize splits s into lowercase words. camelCase and snake_case words are split too.
+func Tokenize(s string) []string {
+	var tokens []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// Match returns the entries matching all words of query, sorted by score.
+// A word matches the prefix of a word of the entry. Whole names and paths score higher.
+func Match(entries []Entry, query string) []Hit {
+	words := Tokenize(query)
+	lowerQuery := strings.ToLower(strings.TrimSpace(query))
+	hits := []Hit{}
+	if len(words) == 0 {
+		return hits
+	}
+	for _, entry := range entries {
+		keyTokens := append(Tokenize(entry.Path), Tokenize(entry.Name)...)
+		textTokens := Tokenize(entry.Text)
+		score := 0
+		for _, word := range words {
+			switch {
+			case containsToken(keyTokens, word, true):
+				score += 3
+			case containsToken(keyTokens, word, false):
+				score += 2
+			case containsToken(textTokens, word, false):
+				score += 1
+			default:
+				score = 0
+			}
+			if score == 0 {
+				break
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		if lowerQuery == strings.ToLower(entry.Name) || lowerQuery == strings.ToLower(entry.Path) {
+			score += 10
+		}
+		hits = append(hits, Hit{Entry: entry, Score: score})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	return hits
+}
+
+func containsToken(tokens []string, word string, exact bool) bool {
+	for _, token := range tokens {
+		if token == word || (!exact && strings.HasPrefix(token, word)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var petstore = `
+swagger: '2.0'
+info: {title: petstore, version: 1.0.0}
+paths:
+  /pets/{petId}:
+    get: {operationId: getPetById, summary: Find a pet by ID, responses: {'200': {description: ok}}}
+    delete: {operationId: deletePet, responses: {'204': {description: deleted}}}
+  /stores:
+    get: {operationId: listStores, description: Returns all stores of the owner, responses: {'200': {description: ok}}}
+definitions:
+  Pet:
+    type: object
+    properties:
+      name: {type: string}
+      ownerId: {type: string, description: id of the owner}
+`
+
+func TestTokenize(t *testing.T) {
+	tokens := Tokenize("/pets/{petId} getHTTPResponse snake_case")
+	expected := []string{"pets", "pet", "id", "get", "http", "response", "snake", "case"}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Fatalf("invalid tokens %v", tokens)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	doc, err := swagger.Parse(common.Yml, petstore)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	entries := Extract(doc)
+	if len(entries) != 8 {
+		t.Fatalf("invalid entries %+v", entries)
+	}
+	if entries[2] != (Entry{Kind: KindOperation, Method: "DELETE", Path: "/pets/{petId}", Name: "deletePet"}) {
+		t.Fatalf("invalid operation %+v", entries[2])
+	}
+	if entries[7] != (Entry{Kind: KindProperty, Name: "Pet.ownerId", Text: "id of the owner"}) {
+		t.Fatalf("invalid property %+v", entries[7])
+	}
+}
+
+func TestMatch(t *testing.T) {
+	doc, _ := swagger.Parse(common.Yml, petstore)
+	entries := Extract(doc)
+
+	hits := Match(entries, "getPetById")
+	if len(hits) == 0 || hits[0].Name != "getPetById" {
+		t.Fatalf("exact name must come first %+v", hits)
+	}
+
+	hits = Match(entries, "owner")
+	names := []string{}
+	for _, hit := range hits {
+		names = append(names, hit.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Pet.ownerId", "listStores"}) {
+		t.Fatalf("names must match before texts %v", names)
+	}
+
+	if hits := Match(entries, "pet unknown"); len(hits) != 0 {
+		t.Fatalf("all words must match %+v", hits)
+	}
+	if hits := Match(entries, " / "); len(hits) != 0 {
+		t.Fatalf("empty query must not match %+v", hits)
+	}
+}
@@ -0,0 +1,229 @@
+package semver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// comparator compares versions with a bound ("<", "<=", ">", ">=" or "=")
+type comparator struct {
+	op    string
+	bound Version
+}
+
+func (c comparator) matches(v Version) bool {
+	n := v.Compare(c.bound)
+	switch c.op {
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	}
+	return n == 0
+}
+
+// Range is a range of semantic versions in the npm syntax:
+//
+//	*, 1.2.3, =1.2.3, 1.2, 1.x     exact or partial versions
+//	^1.2.3                         compatible versions (<2.0.0, <0.3.0 for ^0.2.3)
+//	~1.2.3                         patch versions (<1.3.0)
+//	>=1.2.0 <2.0.0                 comparators, all of them must match
+//	1.2.0 - 1.4                    inclusive ranges
+//	^1.0.0 || ^2.0.0               alternatives
+//
+// Pre-release versions match only if a comparator of the alternative has the same
+// MAJOR.MINOR.PATCH and a pre-release, as npm does.
+type Range struct {
+	raw  string
+	sets [][]comparator
+}
+
+// ParseRange parses a range. ok is false if s is not a range.
+func ParseRange(s string) (Range, bool) {
+	r := Range{raw: strings.TrimSpace(s)}
+	for _, alternative := range strings.Split(s, "||") {
+		set, ok := parseSet(alternative)
+		if !ok {
+			return Range{}, false
+		}
+		r.sets = append(r.sets, set)
+	}
+	return r, true
+}
+
+// String returns the range as it was parsed
+func (r Range) String() string {
+	return r.raw
+}
+
+// Contains returns true if v is in the range
+func (r Range) Contains(v Version) bool {
+	for _, set := range r.sets {
+		if containsVersion(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsString returns true if s is a semantic version in the range
+func (r Range) ContainsString(s string) bool {
+	v, ok := Parse(s)
+	return ok && r.Contains(v)
+}
+
+func containsVersion(set []comparator, v Version) bool {
+	for _, c := range set {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	if len(v.Prerelease) == 0 {
+		return true
+	}
+	for _, c := range set {
+		if len(c.bound.Prerelease) > 0 && c.bound.Major == v.Major && c.bound.Minor == v.Minor && c.bound.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSet(s string) ([]comparator, bool) {
+	fields := strings.Fields(s)
+	set := []comparator{}
+	for i := 0; i < len(fields); i++ {
+		// inclusive range "A - B"
+		if i+2 < len(fields) && fields[i+1] == "-" {
+			from, fromOk := parsePartial(fields[i])
+			to, toOk := parsePartial(fields[i+2])
+			if !fromOk || !toOk {
+				return nil, false
+			}
+			set = append(set, from.comparators(">=")...)
+			set = append(set, to.comparators("<=")...)
+			i += 2
+			continue
+		}
+		field := fields[i]
+		op := ""
+		for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+			if strings.HasPrefix(field, prefix) {
+				op, field = prefix, field[len(prefix):]
+				break
+			}
+		}
+		// ">= 1.2.0" is written with a space too
+		if op != "" && field == "" && i+1 < len(fields) {
+			i++
+			field = fields[i]
+		}
+		p, ok := parsePartial(field)
+		if !ok {
+			return nil, false
+		}
+		set = append(set, p.comparators(op)...)
+	}
+	return set, true
+}
+
+// partial is a version with wildcards. Numbers after the first wildcard are wildcards too.
+type partial struct {
+	version Version
+	known   int // number of known numbers, 0 to 3
+}
+
+func parsePartial(s string) (partial, bool) {
+	s = strings.TrimPrefix(s, "v")
+	if s == "" || s == "*" || s == "x" || s == "X" {
+		return partial{}, true
+	}
+	var p partial
+	core := s
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		core = s[:i]
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return partial{}, false
+	}
+	for _, part := range parts {
+		if part == "*" || part == "x" || part == "X" {
+			break
+		}
+		if _, err := strconv.ParseInt(part, 10, 64); err != nil {
+			return partial{}, false
+		}
+		p.known++
+	}
+	if p.known < 3 && core != s {
+		// a pre-release needs the whole version
+		return partial{}, false
+	}
+	version, ok := Parse(strings.Join(append(parts[:p.known:p.known], "0", "0", "0")[:3], ".") + s[len(core):])
+	if !ok {
+		return partial{}, false
+	}
+	p.version = version
+	return p, true
+}
+
+// next returns the lowest version above the known numbers (1.2 -> 1.3.0, 1 -> 2.0.0)
+func (p partial) next() Version {
+	switch p.known {
+	case 1:
+		return Version{Major: p.version.Major + 1}
+	case 2:
+		return Version{Major: p.version.Major, Minor: p.version.Minor + 1}
+	}
+	return Version{Major: p.version.Major, Minor: p.version.Minor, Patch: p.version.Patch + 1}
+}
+
+func (p partial) comparators(op string) []comparator {
+	v := p.version
+	if p.known == 0 {
+		// * matches any version, and nothing is above or below it
+		if op == ">" || op == "<" {
+			return []comparator{{"<", Version{}}, {">", Version{}}}
+		}
+		return nil
+	}
+	switch op {
+	case "", "=":
+		if p.known == 3 {
+			return []comparator{{"=", v}}
+		}
+		return []comparator{{">=", v}, {"<", p.next()}}
+	case "^":
+		upper := Version{Major: v.Major + 1}
+		switch {
+		case v.Major > 0 || p.known == 1:
+		case v.Minor > 0 || p.known == 2:
+			upper = Version{Minor: v.Minor + 1}
+		default:
+			upper = Version{Patch: v.Patch + 1}
+		}
+		return []comparator{{">=", v}, {"<", upper}}
+	case "~":
+		if p.known == 1 {
+			return []comparator{{">=", v}, {"<", p.next()}}
+		}
+		return []comparator{{">=", v}, {"<", Version{Major: v.Major, Minor: v.Minor + 1}}}
+	case ">":
+		if p.known == 3 {
+			return []comparator{{">", v}}
+		}
+		return []comparator{{">=", p.next()}}
+	case "<=":
+		if p.known == 3 {
+			return []comparator{{"<=", v}}
+		}
+		return []comparator{{"<", p.next()}}
+	}
+	// ">=" and "<" bound the partial version filled with zeros
+	return []comparator{{op, v}}
+}
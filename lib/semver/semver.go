@@ -0,0 +1,126 @@
+package semver
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version (MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD]).
+// A leading "v" is allowed and missing minor/patch numbers are 0.
+type Version struct {
+	Major      int64
+	Minor      int64
+	Patch      int64
+	Prerelease []string
+	Build      string
+}
+
+// Parse parses a semantic version. ok is false if s is not a version.
+func Parse(s string) (Version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	var v Version
+	if i := strings.Index(s, "+"); i >= 0 {
+		s, v.Build = s[:i], s[i+1:]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		if s[i+1:] == "" {
+			return Version{}, false
+		}
+		s, v.Prerelease = s[:i], strings.Split(s[i+1:], ".")
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return Version{}, false
+	}
+	numbers := []*int64{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 {
+			return Version{}, false
+		}
+		*numbers[i] = n
+	}
+	return v, true
+}
+
+// String returns MAJOR.MINOR.PATCH[-PRERELEASE]
+func (v Version) String() string {
+	s := strconv.FormatInt(v.Major, 10) + "." + strconv.FormatInt(v.Minor, 10) + "." + strconv.FormatInt(v.Patch, 10)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1. Build metadata is ignored.
+func (v Version) Compare(o Version) int {
+	for _, pair := range [][2]int64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if pair[0] != pair[1] {
+			return sign(pair[0] - pair[1])
+		}
+	}
+	// a pre-release version has lower precedence than the normal version
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		a, b := v.Prerelease[i], o.Prerelease[i]
+		an, aErr := strconv.ParseInt(a, 10, 64)
+		bn, bErr := strconv.ParseInt(b, 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		case a != b:
+			return strings.Compare(a, b)
+		}
+	}
+	return sign(int64(len(v.Prerelease) - len(o.Prerelease)))
+}
+
+func sign(n int64) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// Compare compares two version strings. Semantic versions are lower than other strings,
+// which are compared as strings.
+func Compare(a string, b string) int {
+	av, aOk := Parse(a)
+	bv, bOk := Parse(b)
+	switch {
+	case aOk && bOk:
+		if c := av.Compare(bv); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aOk:
+		return -1
+	case bOk:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// Sort sorts version strings in ascending order
+func Sort(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Compare(versions[i], versions[j]) < 0
+	})
+}
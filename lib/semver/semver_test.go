@@ -0,0 +1,72 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	v, ok := Parse("v1.2.3-rc.1+build.5")
+	if !ok || v.Major != 1 || v.Minor != 2 || v.Patch != 3 || v.String() != "1.2.3-rc.1" || v.Build != "build.5" {
+		t.Fatalf("invalid version %+v", v)
+	}
+	if v, ok := Parse("2.1"); !ok || v.String() != "2.1.0" {
+		t.Fatalf("invalid version %+v", v)
+	}
+	for _, invalid := range []string{"", "latest", "1.2.3.4", "1.-2.0", "1.2.3-"} {
+		if _, ok := Parse(invalid); ok {
+			t.Errorf("%s must be invalid", invalid)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"1.10.0", "draft", "1.2.0", "1.0.0", "1.0.0-beta.11", "1.0.0-alpha", "1.0.0-beta.2", "1.0.0-alpha.1", "v0.9"}
+	Sort(versions)
+	expected := []string{"v0.9", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0", "1.2.0", "1.10.0", "draft"}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Fatalf("invalid order %v", versions)
+	}
+}
+
+func TestRange(t *testing.T) {
+	for _, c := range []struct {
+		rng string
+		in  []string
+		out []string
+	}{
+		{"*", []string{"0.0.1", "3.2.1"}, []string{"1.0.0-rc.1", "draft"}},
+		{"1.2.3", []string{"1.2.3", "v1.2.3+build"}, []string{"1.2.4"}},
+		{"1.x", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0", "2.0.0-rc.1", "1.3.0-beta"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0"}},
+		{">=1.2.0 <2.0.0", []string{"1.2.0", "1.99.0"}, []string{"1.1.9", "2.0.0"}},
+		{">= 1.2", []string{"1.2.0", "5.0.0"}, []string{"1.1.0"}},
+		{">1.2 <=1.4", []string{"1.3.0", "1.4.9"}, []string{"1.2.9", "1.5.0"}},
+		{"1.2.0 - 1.4", []string{"1.2.0", "1.4.2"}, []string{"1.5.0"}},
+		{"^1.0.0 || ^3.0.0", []string{"1.1.0", "3.0.1"}, []string{"2.0.0"}},
+		{"^1.2.3-beta.1", []string{"1.2.3-beta.2", "1.2.3", "1.4.0"}, []string{"1.2.3-alpha", "1.2.4-beta"}},
+	} {
+		r, ok := ParseRange(c.rng)
+		if !ok {
+			t.Fatalf("%s must be valid", c.rng)
+		}
+		for _, v := range c.in {
+			if !r.ContainsString(v) {
+				t.Errorf("%s must contain %s", c.rng, v)
+			}
+		}
+		for _, v := range c.out {
+			if r.ContainsString(v) {
+				t.Errorf("%s must not contain %s", c.rng, v)
+			}
+		}
+	}
+	for _, invalid := range []string{"latest", "^1.2.3.4", ">=a", "1.x-beta"} {
+		if _, ok := ParseRange(invalid); ok {
+			t.Errorf("%s must be invalid", invalid)
+		}
+	}
+}
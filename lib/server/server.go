@@ -0,0 +1,221 @@
+// Package server serves the lambda handlers over plain http, translating requests
+// the same way API Gateway proxy integration does. It is used for tests and local runs.
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// HandlerFunc is the signature of the lambda handlers in src/
+type HandlerFunc func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// Route binds a handler to a method and a path as written in serverless.yml
+// (e.g. "versions/{id}/versions/{version}", "mock/{id}/{proxy+}")
+type Route struct {
+	Method  string
+	Path    string
+	Handler HandlerFunc
+}
+
+// Server is an http.Handler dispatching requests to lambda handlers
+type Server struct {
+	routes []Route
+	// Authorizer is set to requestContext.authorizer of every request (e.g. principalId)
+	Authorizer map[string]interface{}
+	// Stage is stripped from the request path if not empty (e.g. "dev")
+	Stage string
+}
+
+// New returns a server serving routes
+func New(routes ...Route) *Server {
+	return &Server{routes: routes}
+}
+
+// Handle adds a route
+func (this *Server) Handle(method string, path string, handler HandlerFunc) {
+	this.routes = append(this.routes, Route{Method: method, Path: path, Handler: handler})
+}
+
+func (this *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	if this.Stage != "" {
+		path = strings.TrimPrefix(path, "/"+this.Stage)
+	}
+
+	route, pathParams, methodMatched := this.match(r.Method, path)
+	if route == nil {
+		if methodMatched {
+			writeError(w, 405, 1405, "Method Not Allowed")
+			return
+		}
+		writeError(w, 404, 1404, "Not Found")
+		return
+	}
+
+	request, err := NewRequest(r, route.Path, pathParams)
+	if err != nil {
+		writeError(w, 400, 1400, "Invalid Request")
+		return
+	}
+	request.Path = path
+	request.RequestContext.Stage = this.Stage
+	request.RequestContext.Authorizer = this.Authorizer
+
+	response, err := route.Handler(r.Context(), request)
+	if err != nil {
+		// API Gateway returns 502 when the function fails
+		writeError(w, 502, 1500, "Internal server error")
+		return
+	}
+	WriteResponse(w, response)
+}
+
+// match finds the route for method and path. Routes with more literal segments win
+// like API Gateway. methodMatched is true if the path matched with another method.
+func (this *Server) match(method string, path string) (*Route, map[string]string, bool) {
+	var best *Route
+	var bestParams map[string]string
+	bestLiterals := -1
+	pathMatched := false
+	for i := range this.routes {
+		route := &this.routes[i]
+		params, literals, ok := Match(route.Path, path)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if !strings.EqualFold(route.Method, method) && route.Method != "ANY" {
+			continue
+		}
+		if literals > bestLiterals {
+			best, bestParams, bestLiterals = route, params, literals
+		}
+	}
+	return best, bestParams, pathMatched
+}
+
+// Match matches path against a route pattern. "{name}" matches a segment and "{name+}"
+// matches the rest of the path. It returns the path parameters and the number of literal segments.
+func Match(pattern string, path string) (map[string]string, int, bool) {
+	patternSegments := splitPath(pattern)
+	pathSegments := splitPath(path)
+	params := map[string]string{}
+	literals := 0
+	for i, seg := range patternSegments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "+}") {
+			if i >= len(pathSegments) {
+				return nil, 0, false
+			}
+			params[seg[1:len(seg)-2]] = strings.Join(pathSegments[i:], "/")
+			return params, literals, true
+		}
+		if i >= len(pathSegments) {
+			return nil, 0, false
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params[seg[1:len(seg)-1]] = pathSegments[i]
+			continue
+		}
+		if seg != pathSegments[i] {
+			return nil, 0, false
+		}
+		literals++
+	}
+	if len(patternSegments) != len(pathSegments) {
+		return nil, 0, false
+	}
+	return params, literals, true
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, "/")
+}
+
+// NewRequest converts an http request into an API Gateway proxy request
+func NewRequest(r *http.Request, resource string, pathParams map[string]string) (events.APIGatewayProxyRequest, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return events.APIGatewayProxyRequest{}, err
+	}
+
+	headers := map[string]string{}
+	for name := range r.Header {
+		headers[name] = r.Header.Get(name)
+	}
+	var query map[string]string
+	if values := r.URL.Query(); len(values) > 0 {
+		query = map[string]string{}
+		for name := range values {
+			query[name] = values.Get(name)
+		}
+	}
+	sourceIP := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		sourceIP = host
+	}
+
+	return events.APIGatewayProxyRequest{
+		Resource:              "/" + strings.Trim(resource, "/"),
+		Path:                  r.URL.Path,
+		HTTPMethod:            r.Method,
+		Headers:               headers,
+		QueryStringParameters: query,
+		PathParameters:        pathParams,
+		Body:                  string(body),
+		RequestContext: events.APIGatewayProxyRequestContext{
+			RequestID:    uuid.New().String(),
+			ResourcePath: "/" + strings.Trim(resource, "/"),
+			HTTPMethod:   r.Method,
+			Identity: events.APIGatewayRequestIdentity{
+				APIKey:    r.Header.Get("x-api-key"),
+				SourceIP:  sourceIP,
+				UserAgent: r.UserAgent(),
+			},
+		},
+	}, nil
+}
+
+// WriteResponse writes an API Gateway proxy response
+func WriteResponse(w http.ResponseWriter, response events.APIGatewayProxyResponse) {
+	for name, value := range response.Headers {
+		w.Header().Set(name, value)
+	}
+	body := []byte(response.Body)
+	if response.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(response.Body)
+		if err != nil {
+			writeError(w, 502, 1500, "Internal server error")
+			return
+		}
+		body = decoded
+	}
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = 200
+	}
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code int, message string) {
+	response, _ := common.CreateErrorResponse(statusCode, common.ErrorBody{
+		Error: common.ErrorElm{
+			Code:    code,
+			Message: message,
+		},
+	})
+	WriteResponse(w, response)
+}
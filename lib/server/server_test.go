@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		ok      bool
+		params  map[string]string
+	}{
+		{"services", "/services", true, map[string]string{}},
+		{"services/{id}", "/services/abc", true, map[string]string{"id": "abc"}},
+		{"services/{id}", "/services", false, nil},
+		{"services/{id}", "/services/abc/webhooks", false, nil},
+		{"versions/{id}/versions/{version}", "/versions/a/versions/1.0.0", true, map[string]string{"id": "a", "version": "1.0.0"}},
+		{"mock/{id}/{proxy+}", "/mock/a/pets/1", true, map[string]string{"id": "a", "proxy": "pets/1"}},
+		{"mock/{id}/{proxy+}", "/mock/a", false, nil},
+	}
+	for _, c := range cases {
+		params, _, ok := Match(c.pattern, c.path)
+		if ok != c.ok {
+			t.Fatalf("%s %s: expected %t", c.pattern, c.path, c.ok)
+		}
+		for k, v := range c.params {
+			if params[k] != v {
+				t.Fatalf("%s %s: invalid params %v", c.pattern, c.path, params)
+			}
+		}
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	echo := func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return common.CreateResponse(200, request)
+	}
+	download := func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return common.CreateResponse(200, "download")
+	}
+	ts := httptest.NewServer(New(
+		Route{Method: "GET", Path: "versions/{id}/versions/{version}", Handler: echo},
+		Route{Method: "GET", Path: "versions/{id}/versions/{version}/download", Handler: download},
+	))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/versions/a/versions/1.0.0?x=1")
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	for _, expected := range []string{`"resource":"/versions/{id}/versions/{version}"`, `"version":"1.0.0"`, `"queryStringParameters":{"x":"1"}`, `"sourceIp":"127.0.0.1"`} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("%s is not found in %s", expected, body)
+		}
+	}
+
+	resp, _ = ts.Client().Get(ts.URL + "/versions/a/versions/1.0.0/download")
+	body, _ = ioutil.ReadAll(resp.Body)
+	if string(body) != `"download"` {
+		t.Fatalf("invalid route %s", body)
+	}
+
+	resp, _ = ts.Client().Post(ts.URL+"/versions/a/versions/1.0.0", "application/json", nil)
+	if resp.StatusCode != 405 {
+		t.Fatalf("405 is expected %d", resp.StatusCode)
+	}
+	resp, _ = ts.Client().Get(ts.URL + "/unknown")
+	if resp.StatusCode != 404 {
+		t.Fatalf("404 is expected %d", resp.StatusCode)
+	}
+}
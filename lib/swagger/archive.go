@@ -0,0 +1,197 @@
+package swagger
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// Limits of archives
+const (
+	MaxArchiveFiles = 1000
+	MaxArchiveSize  = 10 * 1024 * 1024 // total uncompressed size in bytes
+)
+
+// ReadArchive extracts the yaml/json files of a zip, tar or tar.gz archive.
+// Other files, directories and hidden files (e.g. __MACOSX/, .git/) are skipped.
+// If all files are in one top-level directory, the directory is stripped.
+func ReadArchive(data []byte) (map[string]string, error) {
+	var files map[string]string
+	var err error
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		files, err = readZip(data)
+	case bytes.HasPrefix(data, []byte("\x1f\x8b")):
+		gz, gzErr := gzip.NewReader(bytes.NewReader(data))
+		if gzErr != nil {
+			return nil, common.NewError(20013, "invalid gzip archive", gzErr)
+		}
+		defer gz.Close()
+		files, err = readTar(gz)
+	default:
+		files, err = readTar(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stripTopDir(files), nil
+}
+
+func stripTopDir(files map[string]string) map[string]string {
+	top := ""
+	for name := range files {
+		i := strings.Index(name, "/")
+		if i < 0 || (top != "" && name[:i] != top) {
+			return files
+		}
+		top = name[:i]
+	}
+	if top == "" {
+		return files
+	}
+	stripped := make(map[string]string, len(files))
+	for name, contents := range files {
+		stripped[strings.TrimPrefix(name, top+"/")] = contents
+	}
+	return stripped
+}
+
+func readZip(data []byte) (map[string]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, common.NewError(20013, "invalid zip archive", err)
+	}
+	files := map[string]string{}
+	total := int64(0)
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() || !specFile(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, common.NewError(20013, "invalid zip archive", err)
+		}
+		contents, err := readLimited(rc, &total)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := addFile(files, f.Name, contents); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func readTar(r io.Reader) (map[string]string, error) {
+	reader := tar.NewReader(r)
+	files := map[string]string{}
+	total := int64(0)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, common.NewError(20013, "invalid tar archive", err)
+		}
+		if header.Typeflag != tar.TypeReg || !specFile(header.Name) {
+			continue
+		}
+		contents, err := readLimited(reader, &total)
+		if err != nil {
+			return nil, err
+		}
+		if err := addFile(files, header.Name, contents); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func readLimited(r io.Reader, total *int64) (string, error) {
+	contents, err := ioutil.ReadAll(io.LimitReader(r, MaxArchiveSize-*total+1))
+	if err != nil {
+		return "", common.NewError(20013, "invalid archive", err)
+	}
+	*total += int64(len(contents))
+	if *total > MaxArchiveSize {
+		return "", common.NewError(20014, "archive is too large", nil)
+	}
+	return string(contents), nil
+}
+
+func addFile(files map[string]string, name string, contents string) error {
+	if len(files) >= MaxArchiveFiles {
+		return common.NewError(20014, "archive has too many files", nil)
+	}
+	files[cleanPath(name)] = contents
+	return nil
+}
+
+func specFile(name string) bool {
+	for _, seg := range strings.Split(cleanPath(name), "/") {
+		if strings.HasPrefix(seg, ".") || seg == "__MACOSX" {
+			return false
+		}
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yml", ".yaml", ".json":
+		return true
+	}
+	return false
+}
+
+// DefaultEntry guesses the entry file of a tree: a root file named openapi or swagger,
+// or the only root yaml/json file. It returns "" if ambiguous.
+func DefaultEntry(files map[string]string) string {
+	roots := []string{}
+	for name := range files {
+		if !strings.Contains(cleanPath(name), "/") {
+			roots = append(roots, cleanPath(name))
+		}
+	}
+	for _, candidate := range []string{"openapi.yaml", "openapi.yml", "openapi.json", "swagger.yaml", "swagger.yml", "swagger.json"} {
+		for _, name := range roots {
+			if strings.ToLower(name) == candidate {
+				return name
+			}
+		}
+	}
+	if len(roots) == 1 {
+		return roots[0]
+	}
+	return ""
+}
+
+// WriteZip archives files (e.g. generated documents). Entries are sorted by name.
+func WriteZip(files map[string]string) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range names {
+		f, err := w.Create(name)
+		if err == nil {
+			_, err = f.Write([]byte(files[name]))
+		}
+		if err != nil {
+			return nil, common.NewError(20015, "zip write error", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, common.NewError(20015, "zip write error", err)
+	}
+	return buf.Bytes(), nil
+}
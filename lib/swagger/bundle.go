@@ -0,0 +1,182 @@
+package swagger
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+// FileFormat returns the format of a file from its extension (json or yaml)
+func FileFormat(name string) common.Format {
+	if strings.ToLower(path.Ext(name)) == ".json" {
+		return common.Json
+	}
+	return common.Yml
+}
+
+// SourceTree is the original tree of a multi-file document
+type SourceTree struct {
+	Entry string            `json:"entry"`
+	Files map[string]string `json:"files"`
+}
+
+// Bundle bundles the tree into one document
+func (tree SourceTree) Bundle() (Document, error) {
+	return Bundle(tree.Files, tree.Entry)
+}
+
+// Bundle resolves the external $refs of entry into one document.
+// files maps slash separated paths (relative to the root of the tree) to their contents.
+//
+// A referenced value is inlined at the first place it is used, and the following refs to
+// the same value become local refs to that place, so recursive schemas spanning files are
+// kept as local refs. Local refs of the entry file are not changed.
+// Missing files or pointers and $refs which only point to each other are errors.
+func Bundle(files map[string]string, entry string) (Document, error) {
+	b := &bundler{
+		files:   map[string]string{},
+		parsed:  map[string]interface{}{},
+		inlined: map[string]string{},
+		entry:   cleanPath(entry),
+	}
+	for name, contents := range files {
+		b.files[cleanPath(name)] = contents
+	}
+	root, err := b.file(b.entry)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, common.NewError(20011, "entry file "+entry+" does not exist", nil)
+	}
+
+	bundled, err := b.walk(root, b.entry, "", []string{})
+	if err != nil {
+		return nil, err
+	}
+	if len(b.missing) > 0 {
+		sort.Strings(b.missing)
+		return nil, common.NewError(20011, "missing $ref targets: "+strings.Join(b.missing, ", "), nil)
+	}
+	doc, ok := bundled.(map[string]interface{})
+	if !ok {
+		return nil, common.NewError(20003, "Swagger document must be an object", nil)
+	}
+	return Document(doc), nil
+}
+
+type bundler struct {
+	files  map[string]string
+	parsed map[string]interface{}
+	// inlined maps "file#pointer" to the JSON pointer of the bundled document where it was inlined
+	inlined map[string]string
+	entry   string
+	missing []string
+}
+
+// file parses a file of the tree. It returns nil if the file does not exist.
+func (this *bundler) file(name string) (interface{}, error) {
+	if parsed, ok := this.parsed[name]; ok {
+		return parsed, nil
+	}
+	contents, ok := this.files[name]
+	if !ok {
+		return nil, nil
+	}
+	parsed, err := ParseValue(FileFormat(name), contents)
+	if err != nil {
+		return nil, common.NewError(20001, "failed to parse "+name, err)
+	}
+	this.parsed[name] = parsed
+	return parsed, nil
+}
+
+// walk copies value of file into the bundled document at pointer out, resolving $refs
+func (this *bundler) walk(value interface{}, file string, out string, stack []string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			return this.ref(ref, file, out, stack)
+		}
+		m := make(map[string]interface{}, len(v))
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			elm, err := this.walk(v[key], file, out+"/"+EscapePointerToken(key), stack)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = elm
+		}
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, elm := range v {
+			walked, err := this.walk(elm, file, out+"/"+strconv.Itoa(i), stack)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = walked
+		}
+		return s, nil
+	default:
+		return v, nil
+	}
+}
+
+func (this *bundler) ref(ref string, file string, out string, stack []string) (interface{}, error) {
+	targetFile, pointer := splitRef(ref)
+	if targetFile == "" {
+		targetFile = file
+	} else {
+		if strings.Contains(targetFile, "://") {
+			// remote refs are left as they are
+			return map[string]interface{}{"$ref": ref}, nil
+		}
+		targetFile = cleanPath(path.Join(path.Dir(file), targetFile))
+	}
+	if targetFile == this.entry {
+		// refs into the entry file are local refs of the bundled document
+		return map[string]interface{}{"$ref": "#" + pointer}, nil
+	}
+
+	key := targetFile + "#" + pointer
+	if location, ok := this.inlined[key]; ok {
+		if location == out {
+			return nil, common.NewError(20012, "$ref cycle: "+strings.Join(append(stack, key), " -> "), nil)
+		}
+		return map[string]interface{}{"$ref": "#" + location}, nil
+	}
+
+	root, err := this.file(targetFile)
+	if err != nil {
+		return nil, err
+	}
+	target, ok := Pointer(root, pointer)
+	if root == nil || !ok {
+		this.missing = append(this.missing, key+" (from "+file+")")
+		return map[string]interface{}{"$ref": ref}, nil
+	}
+
+	this.inlined[key] = out
+	return this.walk(target, targetFile, out, append(stack, key))
+}
+
+// splitRef splits "file.yaml#/pointer" into the file and the pointer
+func splitRef(ref string) (string, string) {
+	i := strings.Index(ref, "#")
+	if i < 0 {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.Replace(name, "\\", "/", -1)), "/")
+}
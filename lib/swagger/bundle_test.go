@@ -0,0 +1,175 @@
+package swagger
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+var splitFiles = map[string]string{
+	"openapi.yaml": `
+swagger: '2.0'
+info:
+  title: petstore
+  version: 1.0.0
+paths:
+  /pets:
+    $ref: paths/pets.yaml
+  /pets/{petId}:
+    $ref: 'paths/pets.yaml#/x-item'
+definitions:
+  Error:
+    type: object
+`,
+	"paths/pets.yaml": `
+get:
+  parameters:
+    - $ref: ../parameters.json#/limit
+  responses:
+    '200':
+      description: ok
+      schema:
+        type: array
+        items:
+          $ref: ../schemas/pet.yaml
+    default:
+      description: error
+      schema:
+        $ref: '../openapi.yaml#/definitions/Error'
+x-item:
+  get:
+    responses:
+      '200':
+        description: ok
+        schema:
+          $ref: ../schemas/pet.yaml
+`,
+	"parameters.json": `{"limit": {"name": "limit", "in": "query", "type": "integer"}}`,
+	"schemas/pet.yaml": `
+type: object
+properties:
+  name:
+    type: string
+  children:
+    type: array
+    items:
+      $ref: '#'
+  owner:
+    $ref: owner.yaml
+`,
+	"schemas/owner.yaml": `
+type: object
+properties:
+  name:
+    type: string
+`,
+}
+
+func TestBundle(t *testing.T) {
+	doc, err := Bundle(splitFiles, "openapi.yaml")
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+
+	ops := doc.Operations()
+	if len(ops) != 2 || ops[0].Key() != "GET /pets" || ops[0].Parameters[0].Name != "limit" {
+		t.Fatalf("invalid operations %+v", ops)
+	}
+	petPointer := "/paths/~1pets/get/responses/200/schema/items"
+	pet, ok := Pointer(map[string]interface{}(doc), petPointer)
+	if !ok || String(Map(Map(pet.(map[string]interface{}), "properties"), "owner"), "type") != "object" {
+		t.Fatalf("schemas are not inlined %v", pet)
+	}
+	if ref, _ := Pointer(map[string]interface{}(doc), petPointer+"/properties/children/items/$ref"); ref != "#"+petPointer {
+		t.Fatalf("recursive ref must be a local ref %v", ref)
+	}
+	if ref, _ := Pointer(map[string]interface{}(doc), "/paths/~1pets~1{petId}/get/responses/200/schema/$ref"); ref != "#"+petPointer {
+		t.Fatalf("second ref must point to the first place %v", ref)
+	}
+	if ref, _ := Pointer(map[string]interface{}(doc), "/paths/~1pets/get/responses/default/schema/$ref"); ref != "#/definitions/Error" {
+		t.Fatalf("ref to the entry must be local %v", ref)
+	}
+
+	// the result must be a valid single-file document
+	contents, err := doc.Marshal(common.Yml)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if _, err := common.ValidateSwagger(common.Yml, contents); err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+}
+
+func TestBundleErrors(t *testing.T) {
+	files := map[string]string{
+		"openapi.yaml": `
+swagger: '2.0'
+paths:
+  /a:
+    $ref: a.yaml
+  /b:
+    $ref: missing.yaml
+  /c:
+    $ref: 'b.yaml#/nothing'
+`,
+		"a.yaml": `{"$ref": "b.yaml"}`,
+		"b.yaml": `{"$ref": "a.yaml"}`,
+	}
+	_, err := Bundle(files, "openapi.yaml")
+	if err == nil || err.(*common.Error).Code != 20012 || !strings.Contains(err.Error(), "a.yaml# -> b.yaml# -> a.yaml#") {
+		t.Fatalf("cycle must be detected %v", err)
+	}
+
+	delete(files, "a.yaml")
+	_, err = Bundle(files, "openapi.yaml")
+	if err == nil || err.(*common.Error).Code != 20011 {
+		t.Fatalf("missing targets must be detected %v", err)
+	}
+	for _, missing := range []string{"a.yaml#", "missing.yaml#", "b.yaml#/nothing"} {
+		if !strings.Contains(err.Error(), missing) {
+			t.Fatalf("%s is not reported %v", missing, err)
+		}
+	}
+
+	if _, err := Bundle(files, "unknown.yaml"); err == nil {
+		t.Fatalf("missing entry must be an error")
+	}
+}
+
+func TestReadArchive(t *testing.T) {
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	for name, contents := range map[string]string{"spec/openapi.yaml": "swagger: '2.0'", "spec/schemas/pet.yaml": "type: object", "spec/README.md": "#", "__MACOSX/spec/._openapi.yaml": "x"} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(contents))
+	}
+	zw.Close()
+
+	files, err := ReadArchive(zipped.Bytes())
+	if err != nil || len(files) != 2 || files["schemas/pet.yaml"] != "type: object" {
+		t.Fatalf("invalid files %v %v", files, err)
+	}
+	if DefaultEntry(files) != "openapi.yaml" {
+		t.Fatalf("invalid entry %s", DefaultEntry(files))
+	}
+
+	var tgz bytes.Buffer
+	gw := gzip.NewWriter(&tgz)
+	tw := tar.NewWriter(gw)
+	for name, contents := range map[string]string{"api.json": "{}", "paths/a.json": "{}"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(contents))
+	}
+	tw.Close()
+	gw.Close()
+
+	files, err = ReadArchive(tgz.Bytes())
+	if err != nil || len(files) != 2 || DefaultEntry(files) != "api.json" {
+		t.Fatalf("invalid files %v %v", files, err)
+	}
+}
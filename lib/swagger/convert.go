@@ -0,0 +1,541 @@
+package swagger
+
+import (
+	"sort"
+	"strings"
+)
+
+// OpenAPIVersion is the version of converted documents
+const OpenAPIVersion = "3.0.3"
+
+// parameter fields which move into "schema" in OpenAPI 3
+var schemaFields = []string{"type", "format", "items", "enum", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "multipleOf"}
+
+// ConvertToOAS3 converts a swagger 2.0 document into an OpenAPI 3.0 document.
+// OpenAPI 3 documents are returned as they are.
+//
+//   - definitions, parameters, responses and securityDefinitions move into components
+//   - host, basePath and schemes become servers
+//   - body and formData parameters become requestBody, with consumes as the content types
+//   - response schemas, headers and examples get content maps of produces
+func ConvertToOAS3(doc Document) Document {
+	if doc.IsOpenAPI3() {
+		return doc
+	}
+	c := &converter{
+		doc:      doc,
+		consumes: mediaTypes(Strings(doc, "consumes")),
+		produces: mediaTypes(Strings(doc, "produces")),
+	}
+
+	out := Document{"openapi": OpenAPIVersion}
+	for key, value := range doc {
+		switch key {
+		case "swagger", "host", "basePath", "schemes", "consumes", "produces", "paths",
+			"definitions", "parameters", "responses", "securityDefinitions":
+		default:
+			out[key] = c.refs(value)
+		}
+	}
+	if servers := c.servers(); len(servers) > 0 {
+		out["servers"] = servers
+	}
+
+	components := map[string]interface{}{}
+	if schemas := Map(doc, "definitions"); len(schemas) > 0 {
+		converted := map[string]interface{}{}
+		for name, schema := range schemas {
+			converted[name] = c.schema(schema)
+		}
+		components["schemas"] = converted
+	}
+	parameters, requestBodies := map[string]interface{}{}, map[string]interface{}{}
+	for name, p := range Map(doc, "parameters") {
+		param, _ := p.(map[string]interface{})
+		switch String(param, "in") {
+		case "body":
+			requestBodies[name] = c.bodyRequest(param, c.consumes)
+		case "formData":
+			requestBodies[name] = c.formRequest([]map[string]interface{}{param}, c.consumes)
+		default:
+			parameters[name] = c.parameter(param)
+		}
+	}
+	if len(parameters) > 0 {
+		components["parameters"] = parameters
+	}
+	if len(requestBodies) > 0 {
+		components["requestBodies"] = requestBodies
+	}
+	if responses := Map(doc, "responses"); len(responses) > 0 {
+		converted := map[string]interface{}{}
+		for name, response := range responses {
+			converted[name] = c.response(response, c.produces)
+		}
+		components["responses"] = converted
+	}
+	if schemes := Map(doc, "securityDefinitions"); len(schemes) > 0 {
+		converted := map[string]interface{}{}
+		for name, scheme := range schemes {
+			s, _ := scheme.(map[string]interface{})
+			converted[name] = securityScheme(s)
+		}
+		components["securitySchemes"] = converted
+	}
+	if len(components) > 0 {
+		out["components"] = components
+	}
+
+	paths := map[string]interface{}{}
+	for path, item := range Map(doc, "paths") {
+		pathItem, _ := item.(map[string]interface{})
+		paths[path] = c.pathItem(pathItem)
+	}
+	out["paths"] = paths
+	return out
+}
+
+type converter struct {
+	doc      Document
+	consumes []string
+	produces []string
+}
+
+func mediaTypes(types []string) []string {
+	if len(types) == 0 {
+		return []string{"application/json"}
+	}
+	return types
+}
+
+func (this *converter) servers() []interface{} {
+	host := String(this.doc, "host")
+	basePath := String(this.doc, "basePath")
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+	schemes := Strings(this.doc, "schemes")
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	servers := []interface{}{}
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+func (this *converter) pathItem(item map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	var bodyParams []interface{}
+	var pathParams []interface{}
+	for _, p := range Slice(item, "parameters") {
+		if this.isBodyOrForm(p) {
+			bodyParams = append(bodyParams, p)
+		} else {
+			pathParams = append(pathParams, this.parameterOrRef(p))
+		}
+	}
+	if len(pathParams) > 0 {
+		out["parameters"] = pathParams
+	}
+
+	for key, value := range item {
+		if key == "parameters" {
+			continue
+		}
+		op, isOperation := value.(map[string]interface{})
+		if !isOperation || !isMethod(key) {
+			out[key] = this.refs(value)
+			continue
+		}
+		out[key] = this.operation(op, bodyParams)
+	}
+	return out
+}
+
+func isMethod(key string) bool {
+	for _, m := range Methods {
+		if m == key {
+			return true
+		}
+	}
+	return false
+}
+
+// operation converts an operation. inherited are the body/formData parameters of the path item.
+func (this *converter) operation(op map[string]interface{}, inherited []interface{}) map[string]interface{} {
+	consumes := this.consumes
+	if c := Strings(op, "consumes"); len(c) > 0 {
+		consumes = c
+	}
+	produces := this.produces
+	if p := Strings(op, "produces"); len(p) > 0 {
+		produces = p
+	}
+
+	out := map[string]interface{}{}
+	for key, value := range op {
+		switch key {
+		case "consumes", "produces", "parameters", "responses", "schemes":
+		default:
+			out[key] = this.refs(value)
+		}
+	}
+
+	params := []interface{}{}
+	var body map[string]interface{}
+	var bodyRef string
+	var forms []map[string]interface{}
+	for _, p := range append(append([]interface{}{}, inherited...), Slice(op, "parameters")...) {
+		param, _ := p.(map[string]interface{})
+		ref := String(param, "$ref")
+		if ref != "" {
+			param, _ = this.doc.Resolve(ref).(map[string]interface{})
+		}
+		switch String(param, "in") {
+		case "body":
+			body, bodyRef = param, ref
+		case "formData":
+			forms = append(forms, param)
+		default:
+			params = append(params, this.parameterOrRef(p))
+		}
+	}
+	if len(params) > 0 {
+		out["parameters"] = params
+	}
+	if bodyRef != "" {
+		out["requestBody"] = map[string]interface{}{"$ref": "#/components/requestBodies/" + strings.TrimPrefix(bodyRef, "#/parameters/")}
+	} else if body != nil {
+		out["requestBody"] = this.bodyRequest(body, consumes)
+	} else if len(forms) > 0 {
+		out["requestBody"] = this.formRequest(forms, consumes)
+	}
+
+	if responses := Map(op, "responses"); responses != nil {
+		converted := map[string]interface{}{}
+		for code, response := range responses {
+			converted[code] = this.response(response, produces)
+		}
+		out["responses"] = converted
+	}
+	return out
+}
+
+func (this *converter) isBodyOrForm(p interface{}) bool {
+	param, _ := p.(map[string]interface{})
+	if ref := String(param, "$ref"); ref != "" {
+		param, _ = this.doc.Resolve(ref).(map[string]interface{})
+	}
+	in := String(param, "in")
+	return in == "body" || in == "formData"
+}
+
+func (this *converter) parameterOrRef(p interface{}) interface{} {
+	param, _ := p.(map[string]interface{})
+	if ref := String(param, "$ref"); ref != "" {
+		return map[string]interface{}{"$ref": convertRef(ref)}
+	}
+	return this.parameter(param)
+}
+
+// parameter converts a non-body parameter. Type related fields move into schema.
+func (this *converter) parameter(param map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	schema := map[string]interface{}{}
+	for key, value := range param {
+		switch {
+		case key == "collectionFormat":
+			switch value {
+			case "csv":
+				out["style"], out["explode"] = "form", false
+				if String(param, "in") == "path" || String(param, "in") == "header" {
+					out["style"] = "simple"
+				}
+			case "ssv":
+				out["style"] = "spaceDelimited"
+			case "pipes":
+				out["style"] = "pipeDelimited"
+			case "multi":
+				out["style"], out["explode"] = "form", true
+			}
+		case key == "x-example":
+			out["example"] = value
+		case Contains(schemaFields, key):
+			schema[key] = this.schema(value)
+		default:
+			out[key] = this.refs(value)
+		}
+	}
+	if items, ok := schema["items"]; ok {
+		schema["items"] = this.itemsSchema(items)
+	}
+	if len(schema) > 0 {
+		out["schema"] = schema
+	}
+	return out
+}
+
+// itemsSchema drops collectionFormat of nested items which is not a schema keyword
+func (this *converter) itemsSchema(items interface{}) interface{} {
+	m, ok := items.(map[string]interface{})
+	if !ok {
+		return items
+	}
+	out := map[string]interface{}{}
+	for key, value := range m {
+		if key == "collectionFormat" {
+			continue
+		}
+		if key == "items" {
+			value = this.itemsSchema(value)
+		}
+		out[key] = value
+	}
+	return out
+}
+
+func (this *converter) bodyRequest(param map[string]interface{}, consumes []string) map[string]interface{} {
+	content := map[string]interface{}{}
+	for _, mediaType := range consumes {
+		content[mediaType] = map[string]interface{}{"schema": this.schema(param["schema"])}
+	}
+	out := map[string]interface{}{"content": content}
+	if description := String(param, "description"); description != "" {
+		out["description"] = description
+	}
+	if Bool(param, "required") {
+		out["required"] = true
+	}
+	copyExtensions(param, out)
+	return out
+}
+
+func (this *converter) formRequest(params []map[string]interface{}, consumes []string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []interface{}{}
+	mediaType := "application/x-www-form-urlencoded"
+	for _, param := range params {
+		name := String(param, "name")
+		converted := this.parameter(param)
+		schema, _ := converted["schema"].(map[string]interface{})
+		if schema == nil {
+			schema = map[string]interface{}{}
+		}
+		if String(schema, "type") == "file" {
+			schema["type"], schema["format"] = "string", "binary"
+			mediaType = "multipart/form-data"
+		}
+		if description := String(param, "description"); description != "" {
+			schema["description"] = description
+		}
+		properties[name] = schema
+		if Bool(param, "required") {
+			required = append(required, name)
+		}
+	}
+	for _, c := range consumes {
+		if c == "multipart/form-data" {
+			mediaType = c
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return map[string]interface{}{
+		"content": map[string]interface{}{
+			mediaType: map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+func (this *converter) response(value interface{}, produces []string) interface{} {
+	response, _ := value.(map[string]interface{})
+	if ref := String(response, "$ref"); ref != "" {
+		return map[string]interface{}{"$ref": convertRef(ref)}
+	}
+	out := map[string]interface{}{}
+	for key, v := range response {
+		switch key {
+		case "schema", "examples":
+		case "headers":
+			headers := map[string]interface{}{}
+			for name, h := range Map(response, "headers") {
+				header, _ := h.(map[string]interface{})
+				converted := this.parameter(header)
+				delete(converted, "in")
+				delete(converted, "name")
+				headers[name] = converted
+			}
+			out[key] = headers
+		default:
+			out[key] = this.refs(v)
+		}
+	}
+	if _, ok := out["description"]; !ok {
+		out["description"] = ""
+	}
+	schema, hasSchema := response["schema"]
+	examples := Map(response, "examples")
+	if hasSchema || len(examples) > 0 {
+		content := map[string]interface{}{}
+		mediaTypes := produces
+		for mediaType := range examples {
+			if !Contains(mediaTypes, mediaType) {
+				mediaTypes = append(append([]string{}, mediaTypes...), mediaType)
+			}
+		}
+		for _, mediaType := range mediaTypes {
+			media := map[string]interface{}{}
+			if hasSchema {
+				media["schema"] = this.schema(schema)
+			}
+			if example, ok := examples[mediaType]; ok {
+				media["example"] = example
+			}
+			content[mediaType] = media
+		}
+		out["content"] = content
+	}
+	return out
+}
+
+// schema converts a schema: $refs, x-nullable, type file and discriminator
+func (this *converter) schema(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for key, elm := range v {
+			switch key {
+			case "$ref":
+				if ref, ok := elm.(string); ok {
+					out[key] = convertRef(ref)
+					continue
+				}
+				out[key] = elm
+			case "x-nullable":
+				out["nullable"] = elm
+			case "discriminator":
+				if name, ok := elm.(string); ok {
+					out[key] = map[string]interface{}{"propertyName": name}
+					continue
+				}
+				out[key] = this.schema(elm)
+			case "type":
+				if elm == "file" {
+					out["type"], out["format"] = "string", "binary"
+					continue
+				}
+				out[key] = this.schema(elm)
+			case "example", "enum", "default", "x-example":
+				out[key] = elm
+			default:
+				out[key] = this.schema(elm)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elm := range v {
+			out[i] = this.schema(elm)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// refs rewrites $refs of a value which is not a schema (e.g. extensions, path item $refs)
+func (this *converter) refs(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for key, elm := range v {
+			if ref, ok := elm.(string); ok && key == "$ref" {
+				out[key] = convertRef(ref)
+				continue
+			}
+			out[key] = this.refs(elm)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elm := range v {
+			out[i] = this.refs(elm)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func convertRef(ref string) string {
+	for from, to := range map[string]string{
+		"#/definitions/":         "#/components/schemas/",
+		"#/parameters/":          "#/components/parameters/",
+		"#/responses/":           "#/components/responses/",
+		"#/securityDefinitions/": "#/components/securitySchemes/",
+	} {
+		if strings.HasPrefix(ref, from) {
+			return to + strings.TrimPrefix(ref, from)
+		}
+	}
+	return ref
+}
+
+func securityScheme(scheme map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	copyExtensions(scheme, out)
+	if description := String(scheme, "description"); description != "" {
+		out["description"] = description
+	}
+	switch String(scheme, "type") {
+	case "basic":
+		out["type"], out["scheme"] = "http", "basic"
+	case "apiKey":
+		out["type"], out["name"], out["in"] = "apiKey", String(scheme, "name"), String(scheme, "in")
+	case "oauth2":
+		flow := map[string]interface{}{"scopes": map[string]interface{}{}}
+		if scopes := Map(scheme, "scopes"); scopes != nil {
+			flow["scopes"] = scopes
+		}
+		if url := String(scheme, "authorizationUrl"); url != "" {
+			flow["authorizationUrl"] = url
+		}
+		if url := String(scheme, "tokenUrl"); url != "" {
+			flow["tokenUrl"] = url
+		}
+		flowName := map[string]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}[String(scheme, "flow")]
+		out["type"] = "oauth2"
+		out["flows"] = map[string]interface{}{flowName: flow}
+	default:
+		for key, value := range scheme {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func copyExtensions(from map[string]interface{}, to map[string]interface{}) {
+	keys := []string{}
+	for key := range from {
+		if strings.HasPrefix(key, "x-") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		to[key] = from[key]
+	}
+}
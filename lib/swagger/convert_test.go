@@ -0,0 +1,158 @@
+package swagger
+
+import (
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+var convertSource = `
+swagger: '2.0'
+info:
+  title: petstore
+  version: 1.0.0
+host: api.example.com
+basePath: /v1
+schemes: [https, http]
+consumes: [application/json]
+produces: [application/json, application/xml]
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: tags
+          in: query
+          type: array
+          items:
+            type: string
+          collectionFormat: multi
+      responses:
+        '200':
+          description: ok
+          headers:
+            X-Total:
+              type: integer
+          schema:
+            type: array
+            items:
+              $ref: '#/definitions/Pet'
+    post:
+      parameters:
+        - name: pet
+          in: body
+          required: true
+          schema:
+            $ref: '#/definitions/Pet'
+      responses:
+        default:
+          $ref: '#/responses/Error'
+  /pets/{petId}/photo:
+    parameters:
+      - $ref: '#/parameters/petId'
+    post:
+      consumes: [multipart/form-data]
+      parameters:
+        - name: file
+          in: formData
+          type: file
+          required: true
+        - name: caption
+          in: formData
+          type: string
+      responses:
+        '204':
+          description: uploaded
+parameters:
+  petId:
+    name: petId
+    in: path
+    required: true
+    type: string
+responses:
+  Error:
+    description: error
+    schema:
+      $ref: '#/definitions/Error'
+definitions:
+  Pet:
+    type: object
+    discriminator: kind
+    properties:
+      kind:
+        type: string
+      owner:
+        $ref: '#/definitions/Owner'
+      nickname:
+        type: string
+        x-nullable: true
+  Owner:
+    type: object
+  Error:
+    type: object
+securityDefinitions:
+  basic:
+    type: basic
+  key:
+    type: apiKey
+    name: X-API-Key
+    in: header
+  oauth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://example.com/authorize
+    tokenUrl: https://example.com/token
+    scopes:
+      read: read pets
+`
+
+func TestConvertToOAS3(t *testing.T) {
+	doc, err := Parse(common.Yml, convertSource)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	converted := ConvertToOAS3(doc)
+	if !converted.IsOpenAPI3() || converted["swagger"] != nil || converted["definitions"] != nil {
+		t.Fatalf("invalid document %v", converted)
+	}
+	root := map[string]interface{}(converted)
+	for pointer, expected := range map[string]interface{}{
+		"/servers/0/url": "https://api.example.com/v1",
+		"/servers/1/url": "http://api.example.com/v1",
+		"/paths/~1pets/get/parameters/0/schema/items/type":                                                          "string",
+		"/paths/~1pets/get/parameters/0/explode":                                                                    true,
+		"/paths/~1pets/get/responses/200/headers/X-Total/schema/type":                                               "integer",
+		"/paths/~1pets/get/responses/200/content/application~1xml/schema/items/$ref":                                "#/components/schemas/Pet",
+		"/paths/~1pets/post/requestBody/required":                                                                   true,
+		"/paths/~1pets/post/requestBody/content/application~1json/schema/$ref":                                      "#/components/schemas/Pet",
+		"/paths/~1pets/post/responses/default/$ref":                                                                 "#/components/responses/Error",
+		"/paths/~1pets~1{petId}~1photo/parameters/0/$ref":                                                           "#/components/parameters/petId",
+		"/paths/~1pets~1{petId}~1photo/post/requestBody/content/multipart~1form-data/schema/required/0":             "file",
+		"/paths/~1pets~1{petId}~1photo/post/requestBody/content/multipart~1form-data/schema/properties/file/format": "binary",
+		"/components/parameters/petId/schema/type":                                                                  "string",
+		"/components/responses/Error/content/application~1json/schema/$ref":                                         "#/components/schemas/Error",
+		"/components/schemas/Pet/discriminator/propertyName":                                                        "kind",
+		"/components/schemas/Pet/properties/owner/$ref":                                                             "#/components/schemas/Owner",
+		"/components/schemas/Pet/properties/nickname/nullable":                                                      true,
+		"/components/securitySchemes/basic/scheme":                                                                  "basic",
+		"/components/securitySchemes/key/in":                                                                        "header",
+		"/components/securitySchemes/oauth/flows/authorizationCode/tokenUrl":                                        "https://example.com/token",
+		"/components/securitySchemes/oauth/flows/authorizationCode/scopes/read":                                     "read pets",
+	} {
+		if value, _ := Pointer(root, pointer); value != expected {
+			t.Errorf("%s: expected %v but %v", pointer, expected, value)
+		}
+	}
+	if _, ok := Pointer(root, "/paths/~1pets/get/parameters/0/type"); ok {
+		t.Errorf("type must move into schema")
+	}
+
+	// the converted document must be parsed as the same operations
+	ops := converted.Operations()
+	if len(ops) != 3 || ops[2].Key() != "POST /pets/{petId}/photo" || ops[2].Parameters[0].Name != "petId" {
+		t.Fatalf("invalid operations %+v", ops)
+	}
+
+	if again := ConvertToOAS3(converted); again["openapi"] != converted["openapi"] || len(again) != len(converted) {
+		t.Fatalf("OpenAPI 3 documents must not be converted")
+	}
+}
@@ -0,0 +1,260 @@
+package swagger
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Kinds of changes
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Targets of changes
+const (
+	TargetEndpoint    = "endpoint"
+	TargetParameter   = "parameter"
+	TargetRequestBody = "requestBody"
+	TargetResponse    = "response"
+	TargetSchema      = "schema"
+	TargetProperty    = "property"
+)
+
+// Change is a difference between two documents.
+// Method/Path/Tags are set for endpoint level changes, and Name is the parameter name,
+// the response status code, the schema name or "schema.property".
+type Change struct {
+	Kind     string   `json:"kind"`
+	Target   string   `json:"target"`
+	Method   string   `json:"method,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Breaking bool     `json:"breaking"`
+	Message  string   `json:"message"`
+}
+
+// DiffResult is the result of Diff
+type DiffResult struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Changes []Change `json:"changes"`
+}
+
+// Breaking returns breaking changes
+func (result DiffResult) Breaking() []Change {
+	changes := []Change{}
+	for _, c := range result.Changes {
+		if c.Breaking {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// HasBreaking returns true if the result contains breaking changes
+func (result DiffResult) HasBreaking() bool {
+	return len(result.Breaking()) > 0
+}
+
+// Diff compares two documents from the point of view of api clients.
+// Removing endpoints, parameters, responses, schemas or properties, adding required
+// parameters/properties and changing types are breaking changes.
+func Diff(from Document, to Document) DiffResult {
+	result := DiffResult{
+		From:    from.Version(),
+		To:      to.Version(),
+		Changes: []Change{},
+	}
+
+	fromOps := map[string]Operation{}
+	for _, op := range from.Operations() {
+		fromOps[op.Key()] = op
+	}
+	toOps := map[string]Operation{}
+	for _, op := range to.Operations() {
+		toOps[op.Key()] = op
+	}
+
+	for _, op := range from.Operations() {
+		if _, ok := toOps[op.Key()]; !ok {
+			result.Changes = append(result.Changes, endpointChange(op, ChangeRemoved, true, fmt.Sprintf("%s was removed", op.Key())))
+		}
+	}
+	for _, op := range to.Operations() {
+		old, ok := fromOps[op.Key()]
+		if !ok {
+			result.Changes = append(result.Changes, endpointChange(op, ChangeAdded, false, fmt.Sprintf("%s was added", op.Key())))
+			continue
+		}
+		result.Changes = append(result.Changes, diffOperation(old, op)...)
+	}
+
+	result.Changes = append(result.Changes, diffSchemas(from.Schemas(), to.Schemas())...)
+	return result
+}
+
+func endpointChange(op Operation, kind string, breaking bool, message string) Change {
+	return Change{
+		Kind:     kind,
+		Target:   TargetEndpoint,
+		Method:   op.Method,
+		Path:     op.Path,
+		Tags:     op.Tags,
+		Breaking: breaking,
+		Message:  message,
+	}
+}
+
+func diffOperation(from Operation, to Operation) []Change {
+	changes := []Change{}
+	add := func(kind string, target string, name string, breaking bool, message string) {
+		changes = append(changes, Change{
+			Kind:     kind,
+			Target:   target,
+			Method:   to.Method,
+			Path:     to.Path,
+			Name:     name,
+			Tags:     to.Tags,
+			Breaking: breaking,
+			Message:  message,
+		})
+	}
+
+	if !from.Deprecated && to.Deprecated {
+		add(ChangeChanged, TargetEndpoint, "", false, fmt.Sprintf("%s was deprecated", to.Key()))
+	}
+
+	fromParams := map[string]Parameter{}
+	for _, p := range from.Parameters {
+		fromParams[p.In+":"+p.Name] = p
+	}
+	toParams := map[string]Parameter{}
+	for _, p := range to.Parameters {
+		toParams[p.In+":"+p.Name] = p
+	}
+	for _, p := range from.Parameters {
+		if _, ok := toParams[p.In+":"+p.Name]; !ok {
+			add(ChangeRemoved, TargetParameter, p.Name, true, fmt.Sprintf("%s parameter %q was removed from %s", p.In, p.Name, to.Key()))
+		}
+	}
+	for _, p := range to.Parameters {
+		old, ok := fromParams[p.In+":"+p.Name]
+		if !ok {
+			if p.Required {
+				add(ChangeAdded, TargetParameter, p.Name, true, fmt.Sprintf("required %s parameter %q was added to %s", p.In, p.Name, to.Key()))
+			} else {
+				add(ChangeAdded, TargetParameter, p.Name, false, fmt.Sprintf("optional %s parameter %q was added to %s", p.In, p.Name, to.Key()))
+			}
+			continue
+		}
+		if !old.Required && p.Required {
+			add(ChangeChanged, TargetParameter, p.Name, true, fmt.Sprintf("%s parameter %q of %s became required", p.In, p.Name, to.Key()))
+		}
+		if old.Type != p.Type {
+			add(ChangeChanged, TargetParameter, p.Name, true, fmt.Sprintf("type of %s parameter %q of %s changed from %q to %q", p.In, p.Name, to.Key(), old.Type, p.Type))
+		}
+	}
+
+	if from.RequestBody == nil && to.RequestBody != nil {
+		required := Bool(to.RequestBody, "required")
+		add(ChangeAdded, TargetRequestBody, "", required, fmt.Sprintf("request body was added to %s", to.Key()))
+	} else if from.RequestBody != nil && to.RequestBody == nil {
+		add(ChangeRemoved, TargetRequestBody, "", true, fmt.Sprintf("request body was removed from %s", to.Key()))
+	} else if from.RequestBody != nil && !Bool(from.RequestBody, "required") && Bool(to.RequestBody, "required") {
+		add(ChangeChanged, TargetRequestBody, "", true, fmt.Sprintf("request body of %s became required", to.Key()))
+	}
+
+	for _, code := range sortedKeys(from.Responses) {
+		if _, ok := to.Responses[code]; !ok {
+			add(ChangeRemoved, TargetResponse, code, true, fmt.Sprintf("response %s was removed from %s", code, to.Key()))
+		}
+	}
+	for _, code := range sortedKeys(to.Responses) {
+		if _, ok := from.Responses[code]; !ok {
+			add(ChangeAdded, TargetResponse, code, false, fmt.Sprintf("response %s was added to %s", code, to.Key()))
+		}
+	}
+	return changes
+}
+
+func diffSchemas(from map[string]interface{}, to map[string]interface{}) []Change {
+	changes := []Change{}
+	for _, name := range sortedKeys(from) {
+		if _, ok := to[name]; !ok {
+			changes = append(changes, Change{Kind: ChangeRemoved, Target: TargetSchema, Name: name, Breaking: true, Message: fmt.Sprintf("schema %s was removed", name)})
+		}
+	}
+	for _, name := range sortedKeys(to) {
+		oldSchema, ok := from[name].(map[string]interface{})
+		if !ok {
+			if _, exists := from[name]; !exists {
+				changes = append(changes, Change{Kind: ChangeAdded, Target: TargetSchema, Name: name, Message: fmt.Sprintf("schema %s was added", name)})
+			}
+			continue
+		}
+		newSchema, _ := to[name].(map[string]interface{})
+		if oldType, newType := String(oldSchema, "type"), String(newSchema, "type"); oldType != newType {
+			changes = append(changes, Change{Kind: ChangeChanged, Target: TargetSchema, Name: name, Breaking: true, Message: fmt.Sprintf("type of schema %s changed from %q to %q", name, oldType, newType)})
+		}
+
+		oldProps, newProps := Map(oldSchema, "properties"), Map(newSchema, "properties")
+		oldRequired, newRequired := stringSet(Strings(oldSchema, "required")), stringSet(Strings(newSchema, "required"))
+		for _, prop := range sortedKeys(oldProps) {
+			if _, ok := newProps[prop]; !ok {
+				changes = append(changes, Change{Kind: ChangeRemoved, Target: TargetProperty, Name: name + "." + prop, Breaking: true, Message: fmt.Sprintf("property %s of schema %s was removed", prop, name)})
+			}
+		}
+		for _, prop := range sortedKeys(newProps) {
+			old, ok := oldProps[prop]
+			if !ok {
+				required := newRequired[prop]
+				qualifier := "optional"
+				if required {
+					qualifier = "required"
+				}
+				changes = append(changes, Change{Kind: ChangeAdded, Target: TargetProperty, Name: name + "." + prop, Breaking: required, Message: fmt.Sprintf("%s property %s was added to schema %s", qualifier, prop, name)})
+				continue
+			}
+			oldType, newType := schemaType(old), schemaType(newProps[prop])
+			if oldType != newType {
+				changes = append(changes, Change{Kind: ChangeChanged, Target: TargetProperty, Name: name + "." + prop, Breaking: true, Message: fmt.Sprintf("type of property %s of schema %s changed from %q to %q", prop, name, oldType, newType)})
+			}
+			if !oldRequired[prop] && newRequired[prop] {
+				changes = append(changes, Change{Kind: ChangeChanged, Target: TargetProperty, Name: name + "." + prop, Breaking: true, Message: fmt.Sprintf("property %s of schema %s became required", prop, name)})
+			}
+		}
+	}
+	return changes
+}
+
+func schemaType(value interface{}) string {
+	schema, _ := value.(map[string]interface{})
+	if ref := String(schema, "$ref"); ref != "" {
+		return ref
+	}
+	typ := String(schema, "type")
+	if typ == "array" {
+		return "array<" + schemaType(schema["items"]) + ">"
+	}
+	return typ
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringSet(strs []string) map[string]bool {
+	set := map[string]bool{}
+	for _, s := range strs {
+		set[s] = true
+	}
+	return set
+}
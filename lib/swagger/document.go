@@ -0,0 +1,186 @@
+package swagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	"gopkg.in/yaml.v2"
+)
+
+// Document is a generic swagger(OpenAPI) document.
+// YAML documents are normalized so that every object is map[string]interface{}.
+type Document map[string]interface{}
+
+// DetectFormat guesses the format of contents. JSON documents start with '{'.
+func DetectFormat(contents string) common.Format {
+	if strings.HasPrefix(strings.TrimSpace(contents), "{") {
+		return common.Json
+	}
+	return common.Yml
+}
+
+// Parse parses a swagger document
+func Parse(format common.Format, contents string) (Document, error) {
+	value, err := ParseValue(format, contents)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, common.NewError(20003, "Swagger document must be an object", nil)
+	}
+	return Document(doc), nil
+}
+
+// ParseValue parses a normalized value of any type (e.g. a fragment of a split document)
+func ParseValue(format common.Format, contents string) (interface{}, error) {
+	var raw interface{}
+	if format == common.Yml {
+		if err := yaml.Unmarshal([]byte(contents), &raw); err != nil {
+			return nil, common.NewError(20001, "Swagger(YML) Unmarshal Error", err)
+		}
+	} else {
+		if err := json.Unmarshal([]byte(contents), &raw); err != nil {
+			return nil, common.NewError(20001, "Swagger(JSON) Unmarshal Error", err)
+		}
+	}
+	return Normalize(raw), nil
+}
+
+// Normalize converts map[interface{}]interface{} (yaml.v2) into map[string]interface{} recursively
+func Normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elm := range v {
+			m[fmt.Sprintf("%v", key)] = Normalize(elm)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, elm := range v {
+			m[key] = Normalize(elm)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, elm := range v {
+			s[i] = Normalize(elm)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// Marshal serializes the document. JSON is indented by 2 spaces.
+func (doc Document) Marshal(format common.Format) (string, error) {
+	if format == common.Yml {
+		bytes, err := yaml.Marshal(map[string]interface{}(doc))
+		if err != nil {
+			return "", common.NewError(20004, "Swagger(YML) Marshal Error", err)
+		}
+		return string(bytes), nil
+	}
+	bytes, err := json.MarshalIndent(map[string]interface{}(doc), "", "  ")
+	if err != nil {
+		return "", common.NewError(20004, "Swagger(JSON) Marshal Error", err)
+	}
+	return string(bytes), nil
+}
+
+// IsOpenAPI3 returns true for OpenAPI 3.x documents
+func (doc Document) IsOpenAPI3() bool {
+	return strings.HasPrefix(String(doc, "openapi"), "3")
+}
+
+// Title returns info.title
+func (doc Document) Title() string {
+	return String(Map(doc, "info"), "title")
+}
+
+// Version returns info.version
+func (doc Document) Version() string {
+	return String(Map(doc, "info"), "version")
+}
+
+// Schemas returns named schemas (definitions in swagger 2.0, components.schemas in OpenAPI 3)
+func (doc Document) Schemas() map[string]interface{} {
+	if doc.IsOpenAPI3() {
+		return Map(Map(doc, "components"), "schemas")
+	}
+	return Map(doc, "definitions")
+}
+
+// SchemaRefPrefix returns the $ref prefix of named schemas
+func (doc Document) SchemaRefPrefix() string {
+	if doc.IsOpenAPI3() {
+		return "#/components/schemas/"
+	}
+	return "#/definitions/"
+}
+
+// Map returns obj[key] as an object. It returns nil if absent.
+func Map(obj map[string]interface{}, key string) map[string]interface{} {
+	if obj == nil {
+		return nil
+	}
+	m, _ := obj[key].(map[string]interface{})
+	return m
+}
+
+// Slice returns obj[key] as an array. It returns nil if absent.
+func Slice(obj map[string]interface{}, key string) []interface{} {
+	if obj == nil {
+		return nil
+	}
+	s, _ := obj[key].([]interface{})
+	return s
+}
+
+// String returns obj[key] as a string. It returns "" if absent.
+func String(obj map[string]interface{}, key string) string {
+	if obj == nil {
+		return ""
+	}
+	switch v := obj[key].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Bool returns obj[key] as a bool. It returns false if absent.
+func Bool(obj map[string]interface{}, key string) bool {
+	if obj == nil {
+		return false
+	}
+	b, _ := obj[key].(bool)
+	return b
+}
+
+// Strings returns obj[key] as a string array
+func Strings(obj map[string]interface{}, key string) []string {
+	var strs []string
+	for _, v := range Slice(obj, key) {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// Contains returns true if strs contains s
+func Contains(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package swagger
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Methods are the http methods of path items in lowercase
+var Methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Operation is an operation of a path item
+type Operation struct {
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	OperationId string                 `json:"operationId"`
+	Summary     string                 `json:"summary"`
+	Description string                 `json:"description"`
+	Tags        []string               `json:"tags"`
+	Deprecated  bool                   `json:"deprecated"`
+	Security    []map[string][]string  `json:"security"`
+	Parameters  []Parameter            `json:"parameters"`
+	Responses   map[string]interface{} `json:"responses"`
+	RequestBody map[string]interface{} `json:"requestBody,omitempty"`
+	Raw         map[string]interface{} `json:"-"`
+}
+
+// Parameter is a resolved parameter of an operation
+type Parameter struct {
+	Name     string                 `json:"name"`
+	In       string                 `json:"in"`
+	Required bool                   `json:"required"`
+	Type     string                 `json:"type"`
+	Raw      map[string]interface{} `json:"-"`
+}
+
+// Key returns "METHOD path"
+func (op Operation) Key() string {
+	return strings.ToUpper(op.Method) + " " + op.Path
+}
+
+// Operations returns all operations sorted by path and method.
+// Path level parameters are merged and $ref parameters are resolved.
+func (doc Document) Operations() []Operation {
+	paths := Map(doc, "paths")
+	var names []string
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	globalSecurity := securityRequirements(doc["security"])
+
+	var operations []Operation
+	for _, path := range names {
+		item, _ := paths[path].(map[string]interface{})
+		if ref := String(item, "$ref"); ref != "" {
+			if resolved, ok := doc.Resolve(ref).(map[string]interface{}); ok {
+				item = resolved
+			}
+		}
+		pathParams := doc.parameters(Slice(item, "parameters"))
+		for _, method := range Methods {
+			raw := Map(item, method)
+			if raw == nil {
+				continue
+			}
+			op := Operation{
+				Method:      method,
+				Path:        path,
+				OperationId: String(raw, "operationId"),
+				Summary:     String(raw, "summary"),
+				Description: String(raw, "description"),
+				Tags:        Strings(raw, "tags"),
+				Deprecated:  Bool(raw, "deprecated"),
+				Security:    globalSecurity,
+				Parameters:  mergeParameters(pathParams, doc.parameters(Slice(raw, "parameters"))),
+				Responses:   Map(raw, "responses"),
+				RequestBody: Map(raw, "requestBody"),
+				Raw:         raw,
+			}
+			if security, ok := raw["security"]; ok {
+				op.Security = securityRequirements(security)
+			}
+			if ref := String(op.RequestBody, "$ref"); ref != "" {
+				op.RequestBody, _ = doc.Resolve(ref).(map[string]interface{})
+			}
+			operations = append(operations, op)
+		}
+	}
+	return operations
+}
+
+// InventoryItem is a normalized operation of the inventory of a version
+type InventoryItem struct {
+	Method      string                `json:"method"` // uppercase
+	Path        string                `json:"path"`
+	OperationId string                `json:"operationId"`
+	Tags        []string              `json:"tags"`
+	Deprecated  bool                  `json:"deprecated"`
+	Security    []map[string][]string `json:"security"` // inherited from the document if not overridden
+}
+
+// Inventory returns the operations without their parameters, responses and descriptions
+func (doc Document) Inventory() []InventoryItem {
+	items := []InventoryItem{}
+	for _, op := range doc.Operations() {
+		tags := op.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		items = append(items, InventoryItem{
+			Method:      strings.ToUpper(op.Method),
+			Path:        op.Path,
+			OperationId: op.OperationId,
+			Tags:        tags,
+			Deprecated:  op.Deprecated,
+			Security:    op.Security,
+		})
+	}
+	return items
+}
+
+func (doc Document) parameters(raw []interface{}) []Parameter {
+	var params []Parameter
+	for _, p := range raw {
+		param, _ := p.(map[string]interface{})
+		if ref := String(param, "$ref"); ref != "" {
+			param, _ = doc.Resolve(ref).(map[string]interface{})
+		}
+		if param == nil {
+			continue
+		}
+		typ := String(param, "type")
+		if typ == "" {
+			typ = String(Map(param, "schema"), "type")
+		}
+		if typ == "" && String(Map(param, "schema"), "$ref") != "" {
+			typ = "object"
+		}
+		params = append(params, Parameter{
+			Name:     String(param, "name"),
+			In:       String(param, "in"),
+			Required: Bool(param, "required") || String(param, "in") == "path",
+			Type:     typ,
+			Raw:      param,
+		})
+	}
+	return params
+}
+
+// operation parameters override path parameters with the same name and location
+func mergeParameters(pathParams []Parameter, opParams []Parameter) []Parameter {
+	merged := []Parameter{}
+	for _, p := range pathParams {
+		overridden := false
+		for _, o := range opParams {
+			if o.Name == p.Name && o.In == p.In {
+				overridden = true
+			}
+		}
+		if !overridden {
+			merged = append(merged, p)
+		}
+	}
+	return append(merged, opParams...)
+}
+
+func securityRequirements(value interface{}) []map[string][]string {
+	requirements := []map[string][]string{}
+	list, _ := value.([]interface{})
+	for _, r := range list {
+		m, _ := r.(map[string]interface{})
+		requirement := map[string][]string{}
+		for name, scopes := range m {
+			requirement[name] = []string{}
+			list, _ := scopes.([]interface{})
+			for _, scope := range list {
+				if s, ok := scope.(string); ok {
+					requirement[name] = append(requirement[name], s)
+				}
+			}
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements
+}
+
+// Resolve resolves a local reference ("#/definitions/Pet"). It returns nil if not found.
+func (doc Document) Resolve(ref string) interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	value, ok := Pointer(map[string]interface{}(doc), strings.TrimPrefix(ref, "#"))
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+// maxRefDepth limits the $refs followed by Deref, which stops reference cycles
+const maxRefDepth = 32
+
+// Deref follows local $refs until value is not a reference. Other values are returned as they are.
+// It returns nil if a reference is not found or the references form a cycle.
+func (doc Document) Deref(value interface{}) interface{} {
+	for i := 0; i < maxRefDepth; i++ {
+		m, ok := value.(map[string]interface{})
+		if !ok || String(m, "$ref") == "" {
+			return value
+		}
+		value = doc.Resolve(String(m, "$ref"))
+	}
+	return nil
+}
+
+// Pointer evaluates a JSON pointer (RFC 6901) against value
+func Pointer(value interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return value, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	current := value
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case Document:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// EscapePointerToken escapes a token of a JSON pointer
+func EscapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
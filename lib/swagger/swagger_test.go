@@ -0,0 +1,238 @@
+package swagger
+
+import (
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+)
+
+var petstoreV1 = `
+swagger: '2.0'
+info:
+  title: petstore
+  version: 1.0.0
+paths:
+  /pets:
+    parameters:
+      - name: limit
+        in: query
+        type: integer
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        '200':
+          description: ok
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      tags: [pets]
+      parameters:
+        - $ref: '#/parameters/petId'
+      responses:
+        '200':
+          description: ok
+        '404':
+          description: not found
+    delete:
+      operationId: deletePet
+      tags: [pets]
+      parameters:
+        - $ref: '#/parameters/petId'
+      responses:
+        '204':
+          description: deleted
+parameters:
+  petId:
+    name: petId
+    in: path
+    required: true
+    type: string
+definitions:
+  Pet:
+    type: object
+    required: [id]
+    properties:
+      id:
+        type: string
+      name:
+        type: string
+`
+
+var petstoreV2 = `{
+  "swagger": "2.0",
+  "info": {"title": "petstore", "version": "2.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "listPets",
+        "tags": ["pets"],
+        "parameters": [
+          {"name": "limit", "in": "query", "type": "string"},
+          {"name": "owner", "in": "query", "type": "string", "required": true}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      },
+      "post": {
+        "operationId": "createPet",
+        "tags": ["pets"],
+        "responses": {"201": {"description": "created"}}
+      }
+    },
+    "/pets/{petId}": {
+      "get": {
+        "operationId": "getPet",
+        "tags": ["pets"],
+        "parameters": [{"name": "petId", "in": "path", "required": true, "type": "string"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["id", "owner"],
+      "properties": {
+        "id": {"type": "integer"},
+        "owner": {"type": "string"}
+      }
+    }
+  }
+}`
+
+func TestParseAndOperations(t *testing.T) {
+	doc, err := Parse(DetectFormat(petstoreV1), petstoreV1)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if doc.Version() != "1.0.0" || doc.IsOpenAPI3() {
+		t.Fatalf("invalid document %v", doc)
+	}
+	ops := doc.Operations()
+	if len(ops) != 3 {
+		t.Fatalf("invalid operations %v", ops)
+	}
+	if ops[0].Key() != "GET /pets" || len(ops[0].Parameters) != 1 || ops[0].Parameters[0].Name != "limit" {
+		t.Fatalf("path parameters are not merged %+v", ops[0])
+	}
+	if ops[1].Key() != "GET /pets/{petId}" || ops[1].Parameters[0].Name != "petId" || !ops[1].Parameters[0].Required {
+		t.Fatalf("$ref parameters are not resolved %+v", ops[1])
+	}
+	if ops[2].Key() != "DELETE /pets/{petId}" {
+		t.Fatalf("operations are not sorted %+v", ops[2])
+	}
+}
+
+func TestInventory(t *testing.T) {
+	doc, _ := Parse(common.Yml, `
+swagger: '2.0'
+info: {title: petstore, version: 1.0.0}
+security: [{apiKey: []}]
+paths:
+  /pets:
+    get: {operationId: listPets, tags: [pets], responses: {'200': {description: ok}}}
+    post: {operationId: createPet, deprecated: true, security: [{oauth: [write]}], responses: {'201': {description: created}}}
+`)
+	items := doc.Inventory()
+	if len(items) != 2 {
+		t.Fatalf("invalid inventory %+v", items)
+	}
+	if items[0].Method != "GET" || items[0].OperationId != "listPets" || items[0].Deprecated || len(items[0].Security) != 1 || items[0].Security[0]["apiKey"] == nil {
+		t.Fatalf("global security must be inherited %+v", items[0])
+	}
+	if items[1].Method != "POST" || !items[1].Deprecated || items[1].Tags == nil || items[1].Security[0]["oauth"][0] != "write" {
+		t.Fatalf("invalid operation %+v", items[1])
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	doc, err := Parse(common.Yml, petstoreV1)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	contents, err := doc.Marshal(common.Json)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if DetectFormat(contents) != common.Json {
+		t.Fatalf("invalid json %s", contents)
+	}
+	parsed, err := Parse(common.Json, contents)
+	if err != nil || len(parsed.Operations()) != 3 {
+		t.Fatalf("failed test %#v", err)
+	}
+}
+
+func TestPointer(t *testing.T) {
+	doc, _ := Parse(common.Yml, petstoreV1)
+	value, ok := Pointer(map[string]interface{}(doc), "/paths/~1pets~1{petId}/get/responses/404/description")
+	if !ok || value != "not found" {
+		t.Fatalf("invalid value %v", value)
+	}
+	if _, ok := Pointer(map[string]interface{}(doc), "/paths/~1pets/get/parameters/3"); ok {
+		t.Fatalf("out of range must not be found")
+	}
+	if EscapePointerToken("/pets/{petId}") != "~1pets~1{petId}" {
+		t.Fatalf("invalid escape")
+	}
+}
+
+func TestDeref(t *testing.T) {
+	doc := Document{"definitions": map[string]interface{}{
+		"Pet":   map[string]interface{}{"type": "object"},
+		"Alias": map[string]interface{}{"$ref": "#/definitions/Pet"},
+		"Loop":  map[string]interface{}{"$ref": "#/definitions/Loop"},
+	}}
+	pet, _ := doc.Deref(map[string]interface{}{"$ref": "#/definitions/Alias"}).(map[string]interface{})
+	if String(pet, "type") != "object" {
+		t.Fatalf("invalid value %#v", pet)
+	}
+	if doc.Deref(map[string]interface{}{"$ref": "#/definitions/Dog"}) != nil || doc.Deref(map[string]interface{}{"$ref": "#/definitions/Loop"}) != nil {
+		t.Fatalf("missing and cyclic refs must be nil")
+	}
+	if doc.Deref("text") != "text" {
+		t.Fatalf("other values must be returned as they are")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	v1, _ := Parse(common.Yml, petstoreV1)
+	v2, _ := Parse(common.Json, petstoreV2)
+	result := Diff(v1, v2)
+
+	expected := map[string]bool{
+		"removed endpoint  DELETE /pets/{petId}": true,
+		"added endpoint  POST /pets":             false,
+		"changed parameter limit GET /pets":      true,
+		"added parameter owner GET /pets":        true,
+		"removed response 404 GET /pets/{petId}": true,
+		"changed property Pet.id  ":              true,
+		"removed property Pet.name  ":            true,
+		"added property Pet.owner  ":             true,
+	}
+	found := map[string]bool{}
+	for _, c := range result.Changes {
+		key := c.Kind + " " + c.Target + " " + c.Name + " " + methodPath(c)
+		breaking, ok := expected[key]
+		if !ok {
+			t.Fatalf("unexpected change %q %+v", key, c)
+		}
+		if breaking != c.Breaking {
+			t.Fatalf("invalid breaking flag %+v", c)
+		}
+		found[key] = true
+	}
+	if len(found) != len(expected) {
+		t.Fatalf("missing changes %+v", result.Changes)
+	}
+	if !result.HasBreaking() || result.From != "1.0.0" || result.To != "2.0.0" {
+		t.Fatalf("invalid result %+v", result)
+	}
+}
+
+func methodPath(c Change) string {
+	if c.Method == "" {
+		return " "
+	}
+	return Operation{Method: c.Method, Path: c.Path}.Key()
+}
@@ -0,0 +1,147 @@
+// Package tenant scopes services to namespaces (organization/team) so that several
+// business units can share one deployment without seeing each other's APIs.
+package tenant
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+)
+
+// Default is the namespace of the services created before namespaces
+// and of the callers whose authorizer grants no namespaces
+const Default = servicedb.DefaultNamespace
+
+// ContextKey is the key of the authorizer context listing the namespaces of the caller,
+// comma separated. A namespace may be "org/team", "org/*" (all teams of org) or "*".
+const ContextKey = "namespaces"
+
+var namespacePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*/[a-z0-9][a-z0-9-]*$`)
+
+// Validate returns true if namespace is "organization/team" in lower case
+func Validate(namespace string) bool {
+	return namespacePattern.MatchString(namespace)
+}
+
+// Of returns the namespace of a service
+func Of(service servicedb.ServiceEntity) string {
+	if service.Namespace == "" {
+		return Default
+	}
+	return service.Namespace
+}
+
+// KeyPrefix returns the prefix of the storage keys of the services of namespace.
+// The services of the default namespace keep the keys they had before namespaces.
+func KeyPrefix(namespace string) string {
+	if namespace == "" || namespace == Default {
+		return "swagger/"
+	}
+	return "tenants/" + namespace + "/swagger/"
+}
+
+// Scope is the namespaces granted to a caller
+type Scope []string
+
+// Caller returns the namespaces granted to the caller by the authorizer
+func Caller(request events.APIGatewayProxyRequest) Scope {
+	value, _ := request.RequestContext.Authorizer[ContextKey].(string)
+	scope := Scope{}
+	for _, namespace := range strings.Split(value, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			scope = append(scope, namespace)
+		}
+	}
+	if len(scope) == 0 {
+		return Scope{Default}
+	}
+	return scope
+}
+
+// Allows returns true if the namespace is granted
+func (scope Scope) Allows(namespace string) bool {
+	if namespace == "" {
+		namespace = Default
+	}
+	parts := strings.SplitN(namespace, "/", 2)
+	for _, granted := range scope {
+		if granted == "*" || granted == namespace || (len(parts) == 2 && granted == parts[0]+"/*") {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns true if every namespace is granted
+func (scope Scope) All() bool {
+	for _, granted := range scope {
+		if granted == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Namespace returns the namespace of the services created by the caller without a namespace:
+// the only namespace granted, or the default one
+func (scope Scope) Namespace() string {
+	if len(scope) == 1 && Validate(scope[0]) {
+		return scope[0]
+	}
+	return Default
+}
+
+// Filter returns the services of the granted namespaces
+func (scope Scope) Filter(services []servicedb.ServiceEntity) []servicedb.ServiceEntity {
+	if services == nil {
+		return nil
+	}
+	filtered := []servicedb.ServiceEntity{}
+	for _, service := range services {
+		if scope.Allows(service.Namespace) {
+			filtered = append(filtered, service)
+		}
+	}
+	return filtered
+}
+
+// Conflict returns the service other than id named name in namespace, or nil.
+// Service names are unique per namespace.
+func Conflict(services []servicedb.ServiceEntity, namespace string, name string, id string) *servicedb.ServiceEntity {
+	if namespace == "" {
+		namespace = Default
+	}
+	for i, service := range services {
+		if service.Id != id && service.Servicename == name && Of(service) == namespace {
+			return &services[i]
+		}
+	}
+	return nil
+}
+
+// Authorize gets a service visible to the caller. It returns nil if the service does not exist
+// or belongs to a namespace which is not granted, so that the services of other tenants look missing.
+func Authorize(dao servicedb.ServiceRepositoryDao, request events.APIGatewayProxyRequest, serviceId string) (*servicedb.ServiceEntity, error) {
+	service, err := dao.GetService(serviceId)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil || !Caller(request).Allows(service.Namespace) {
+		return nil, nil
+	}
+	return service, nil
+}
+
+// ValidateNamespace returns an error if namespace is invalid or not granted to the caller
+func ValidateNamespace(request events.APIGatewayProxyRequest, namespace string) error {
+	if !Validate(namespace) {
+		return common.NewError(26001, "namespace must be organization/team ("+namespacePattern.String()+")", nil)
+	}
+	if !Caller(request).Allows(namespace) {
+		return common.NewError(26002, "namespace "+namespace+" is not granted", nil)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package tenant
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+)
+
+func caller(namespaces string) events.APIGatewayProxyRequest {
+	var request events.APIGatewayProxyRequest
+	request.RequestContext.Authorizer = map[string]interface{}{"principalId": "id", ContextKey: namespaces}
+	return request
+}
+
+func TestScope(t *testing.T) {
+	if scope := Caller(events.APIGatewayProxyRequest{}); len(scope) != 1 || scope[0] != Default || !scope.Allows("") {
+		t.Fatalf("invalid default scope %v", scope)
+	}
+	scope := Caller(caller("pets/store, users/*"))
+	for namespace, allowed := range map[string]bool{"pets/store": true, "pets/shop": false, "users/auth": true, "": false, "users": false} {
+		if scope.Allows(namespace) != allowed {
+			t.Errorf("%q must be %t", namespace, allowed)
+		}
+	}
+	if scope.All() || !Caller(caller("*")).All() {
+		t.Errorf("invalid all")
+	}
+	if ns := Caller(caller("pets/store")).Namespace(); ns != "pets/store" {
+		t.Errorf("invalid namespace %s", ns)
+	}
+	if ns := scope.Namespace(); ns != Default {
+		t.Errorf("invalid namespace %s", ns)
+	}
+
+	services := []servicedb.ServiceEntity{
+		{Id: "s1", Servicename: "petstore"},
+		{Id: "s2", Servicename: "petstore", Namespace: "pets/store"},
+		{Id: "s3", Servicename: "auth", Namespace: "users/auth"},
+	}
+	if filtered := scope.Filter(services); len(filtered) != 2 || filtered[0].Id != "s2" || filtered[1].Id != "s3" {
+		t.Errorf("invalid services %+v", filtered)
+	}
+	if Scope([]string{Default}).Filter(nil) != nil {
+		t.Errorf("nil must be kept")
+	}
+}
+
+func TestConflict(t *testing.T) {
+	services := []servicedb.ServiceEntity{
+		{Id: "s1", Servicename: "petstore"},
+		{Id: "s2", Servicename: "petstore", Namespace: "pets/store"},
+	}
+	if c := Conflict(services, Default, "petstore", ""); c == nil || c.Id != "s1" {
+		t.Errorf("invalid conflict %+v", c)
+	}
+	if c := Conflict(services, "pets/store", "petstore", "s2"); c != nil {
+		t.Errorf("the service itself must not conflict %+v", c)
+	}
+	if c := Conflict(services, "pets/shop", "petstore", ""); c != nil {
+		t.Errorf("names are unique per namespace %+v", c)
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	for namespace, valid := range map[string]bool{"pets/store": true, "pets-1/store-2": true, "Pets/store": false, "pets": false, "pets/*": false, "a/b/c": false, "": false} {
+		if Validate(namespace) != valid {
+			t.Errorf("%q must be %t", namespace, valid)
+		}
+	}
+	if prefix := KeyPrefix(Default); prefix != "swagger/" {
+		t.Errorf("invalid prefix %s", prefix)
+	}
+	if prefix := KeyPrefix("pets/store"); prefix != "tenants/pets/store/swagger/" {
+		t.Errorf("invalid prefix %s", prefix)
+	}
+	// the legacy services without a namespace reserve their names in the default namespace
+	if servicedb.NameKey("", "petstore") != servicedb.NameKey(Of(servicedb.ServiceEntity{}), "petstore") {
+		t.Errorf("invalid name key %s", servicedb.NameKey("", "petstore"))
+	}
+	if err := ValidateNamespace(caller("pets/*"), "users/auth"); err == nil {
+		t.Errorf("users/auth is not granted")
+	}
+	if err := ValidateNamespace(caller("pets/*"), "pets/store"); err != nil {
+		t.Errorf("failed test %#v", err)
+	}
+}
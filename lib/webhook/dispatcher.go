@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+)
+
+// Headers sent with every delivery
+const (
+	SignatureHeader = "X-Swagger-Viewer-Signature"
+	EventHeader     = "X-Swagger-Viewer-Event"
+	DeliveryHeader  = "X-Swagger-Viewer-Delivery"
+)
+
+// Payload is the JSON body posted to webhooks
+type Payload struct {
+	Event      string      `json:"event"`
+	Serviceid  string      `json:"serviceid"`
+	Deliveryid string      `json:"deliveryid"`
+	Timestamp  int64       `json:"timestamp"`
+	Data       interface{} `json:"data"`
+}
+
+// Sign returns the signature header value of body: "sha256=" + hex(HMAC-SHA256(secret, body))
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature header value of body
+func VerifySignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// UpdateEvents returns events caused by updating a version from before to after
+func UpdateEvents(before *versiondb.VersionEntity, after versiondb.VersionEntity) []string {
+	events := []string{}
+	if after.Tag == versiondb.PromotedTag && (before == nil || before.Tag != versiondb.PromotedTag) {
+		events = append(events, webhookdb.EventVersionPromoted)
+	}
+	if !after.Enable && (before == nil || before.Enable) {
+		events = append(events, webhookdb.EventVersionDisabled)
+	}
+	return events
+}
+
+// Dispatcher delivers events to the webhooks subscribed by a service.
+// Failed deliveries are retried with exponential backoff (InitialBackoff, x2, x4, ...)
+// and every delivery is recorded in the delivery log.
+type Dispatcher struct {
+	Dao            webhookdb.WebhookRepositoryDao
+	Client         *http.Client
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	Sleep          func(time.Duration)
+}
+
+// NewDispatcher returns a Dispatcher with default settings
+func NewDispatcher(dao webhookdb.WebhookRepositoryDao) *Dispatcher {
+	return &Dispatcher{
+		Dao:            dao,
+		Client:         NewClient(),
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		Sleep:          time.Sleep,
+	}
+}
+
+// NewClient returns the http client of deliveries. It connects to public addresses only:
+// the addresses are checked after name resolution, for redirects as well, so that a webhook
+// whose host resolves to an internal address later on is not delivered either.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network string, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || webhookdb.InternalIP(ip) {
+				return fmt.Errorf("webhooks are not delivered to internal address %s", host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+	}
+}
+
+// Dispatch delivers the event to all webhooks of the service which subscribe to it.
+// It returns the deliveries. An error is returned only if webhooks can not be loaded;
+// failed deliveries are reported in the delivery records.
+func (this *Dispatcher) Dispatch(serviceId string, event string, data interface{}) ([]webhookdb.DeliveryEntity, error) {
+	if this == nil {
+		return nil, common.NewError(100, "nil pointer receiver", nil)
+	}
+	webhooks, err := this.Dao.GetWebhooks(serviceId)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []webhookdb.DeliveryEntity{}
+	for _, hook := range webhooks {
+		if !hook.Subscribes(event) {
+			continue
+		}
+		delivery, err := this.deliver(hook, event, data)
+		if err != nil {
+			return deliveries, err
+		}
+		if err := this.Dao.PutDelivery(delivery); err != nil {
+			fmt.Println(err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (this *Dispatcher) deliver(hook webhookdb.WebhookEntity, event string, data interface{}) (webhookdb.DeliveryEntity, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return webhookdb.DeliveryEntity{}, common.NewError(0, "uuid error", err)
+	}
+	now := time.Now().Unix() * 1000
+	payload := Payload{
+		Event:      event,
+		Serviceid:  hook.Serviceid,
+		Deliveryid: id.String(),
+		Timestamp:  now,
+		Data:       data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return webhookdb.DeliveryEntity{}, common.NewError(101, "marshal error", err)
+	}
+
+	delivery := webhookdb.DeliveryEntity{
+		Webhookid:  hook.Id,
+		Deliveryid: webhookdb.DeliveryId(now, id.String()),
+		Serviceid:  hook.Serviceid,
+		Event:      event,
+		Payload:    string(body),
+		Timestamp:  now,
+	}
+
+	backoff := this.InitialBackoff
+	for attempt := 1; attempt <= this.MaxAttempts; attempt++ {
+		delivery.Attempts = attempt
+		statusCode, err := this.post(hook, event, id.String(), body)
+		delivery.Statuscode = statusCode
+		if err == nil && statusCode >= 200 && statusCode < 300 {
+			delivery.Success = true
+			delivery.Error = ""
+			return delivery, nil
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		} else {
+			delivery.Error = fmt.Sprintf("unexpected status code %d", statusCode)
+		}
+		if attempt < this.MaxAttempts {
+			this.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return delivery, nil
+}
+
+func (this *Dispatcher) post(hook webhookdb.WebhookEntity, event string, deliveryId string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, body))
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, deliveryId)
+
+	resp, err := this.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+)
+
+type webhookRepositoryDaoMock struct {
+	webhookdb.WebhookRepositoryDao
+	webhooks   []webhookdb.WebhookEntity
+	deliveries []webhookdb.DeliveryEntity
+}
+
+func (this *webhookRepositoryDaoMock) GetWebhooks(serviceId string) ([]webhookdb.WebhookEntity, error) {
+	return this.webhooks, nil
+}
+
+func (this *webhookRepositoryDaoMock) PutDelivery(delivery webhookdb.DeliveryEntity) error {
+	this.deliveries = append(this.deliveries, delivery)
+	return nil
+}
+
+func TestDispatchSignedWithRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		if !VerifySignature("secret", body, r.Header.Get(SignatureHeader)) {
+			t.Fatalf("invalid signature %s", r.Header.Get(SignatureHeader))
+		}
+		var payload Payload
+		if err := json.Unmarshal(body, &payload); err != nil || payload.Event != webhookdb.EventVersionUploaded {
+			t.Fatalf("invalid payload %s", body)
+		}
+		if calls < 3 {
+			w.WriteHeader(500)
+			return
+		}
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	dao := &webhookRepositoryDaoMock{
+		webhooks: []webhookdb.WebhookEntity{
+			{Serviceid: "service", Id: "hook1", Url: server.URL, Secret: "secret"},
+			{Serviceid: "service", Id: "hook2", Url: server.URL, Secret: "secret", Events: []string{webhookdb.EventVersionDeleted}},
+		},
+	}
+	var backoffs []time.Duration
+	dispatcher := NewDispatcher(dao)
+	dispatcher.Client = server.Client()
+	dispatcher.Sleep = func(d time.Duration) { backoffs = append(backoffs, d) }
+
+	deliveries, err := dispatcher.Dispatch("service", webhookdb.EventVersionUploaded, versiondb.VersionEntity{ID: "service", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if len(deliveries) != 1 || !deliveries[0].Success || deliveries[0].Attempts != 3 || deliveries[0].Statuscode != 204 {
+		t.Fatalf("invalid deliveries %+v", deliveries)
+	}
+	if len(dao.deliveries) != 1 {
+		t.Fatalf("delivery is not logged %+v", dao.deliveries)
+	}
+	if len(backoffs) != 2 || backoffs[1] != 2*backoffs[0] {
+		t.Fatalf("invalid backoff %v", backoffs)
+	}
+}
+
+func TestDispatchFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+	}))
+	defer server.Close()
+
+	dao := &webhookRepositoryDaoMock{
+		webhooks: []webhookdb.WebhookEntity{{Serviceid: "service", Id: "hook1", Url: server.URL}},
+	}
+	dispatcher := NewDispatcher(dao)
+	dispatcher.Client = server.Client()
+	dispatcher.Sleep = func(d time.Duration) {}
+
+	deliveries, err := dispatcher.Dispatch("service", webhookdb.EventVersionDeleted, nil)
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Success || deliveries[0].Attempts != dispatcher.MaxAttempts || deliveries[0].Error == "" {
+		t.Fatalf("invalid deliveries %+v", deliveries)
+	}
+}
+
+func TestRunQueuedJob(t *testing.T) {
+	var payload Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	job, err := NewJob("service", webhookdb.EventVersionDeleted, versiondb.VersionEntity{ID: "service", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	// the job goes through the queue as JSON
+	message, _ := json.Marshal(job)
+	var queued Job
+	if err := json.Unmarshal(message, &queued); err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+
+	dao := &webhookRepositoryDaoMock{
+		webhooks: []webhookdb.WebhookEntity{{Serviceid: "service", Id: "hook1", Url: server.URL}},
+	}
+	dispatcher := NewDispatcher(dao)
+	dispatcher.Client = server.Client()
+	deliveries, err := dispatcher.Run(queued)
+	if err != nil || len(deliveries) != 1 || !deliveries[0].Success {
+		t.Fatalf("invalid deliveries %+v %#v", deliveries, err)
+	}
+	data, _ := payload.Data.(map[string]interface{})
+	if payload.Event != webhookdb.EventVersionDeleted || payload.Serviceid != "service" || data["version"] != "1.0.0" {
+		t.Fatalf("invalid payload %+v", payload)
+	}
+}
+
+func TestInternalAddresses(t *testing.T) {
+	for _, u := range []string{"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://169.254.169.254/latest/meta-data/", "https://10.1.2.3/hook", "http://[::1]/hook", "http://[fe80::1]/hook", "ftp://example.com/hook"} {
+		if webhookdb.ValidateUrl(u) {
+			t.Fatalf("%s must be rejected", u)
+		}
+	}
+	if !webhookdb.ValidateUrl("https://93.184.216.34/hook") {
+		t.Fatalf("public addresses must be accepted")
+	}
+
+	// the address is checked again on delivery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("internal addresses must not be delivered to")
+	}))
+	defer server.Close()
+	dao := &webhookRepositoryDaoMock{
+		webhooks: []webhookdb.WebhookEntity{{Serviceid: "service", Id: "hook1", Url: server.URL}},
+	}
+	dispatcher := NewDispatcher(dao)
+	dispatcher.Sleep = func(d time.Duration) {}
+	deliveries, err := dispatcher.Dispatch("service", webhookdb.EventVersionDeleted, nil)
+	if err != nil || len(deliveries) != 1 || deliveries[0].Success || !strings.Contains(deliveries[0].Error, "internal address") {
+		t.Fatalf("invalid deliveries %+v %#v", deliveries, err)
+	}
+}
+
+func TestUpdateEvents(t *testing.T) {
+	before := &versiondb.VersionEntity{Enable: true, Tag: "dev"}
+	events := UpdateEvents(before, versiondb.VersionEntity{Enable: false, Tag: versiondb.PromotedTag})
+	if len(events) != 2 || events[0] != webhookdb.EventVersionPromoted || events[1] != webhookdb.EventVersionDisabled {
+		t.Fatalf("invalid events %v", events)
+	}
+	if events := UpdateEvents(&versiondb.VersionEntity{Enable: true, Tag: versiondb.PromotedTag}, versiondb.VersionEntity{Enable: true, Tag: versiondb.PromotedTag}); len(events) != 0 {
+		t.Fatalf("invalid events %v", events)
+	}
+}
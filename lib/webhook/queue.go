@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+)
+
+// Job is an event to deliver to the webhooks of a service.
+// Handlers queue jobs, and the worker dispatches them out of the request path.
+type Job struct {
+	Serviceid string          `json:"serviceid"`
+	Event     string          `json:"event"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewJob returns a job delivering the event with data
+func NewJob(serviceId string, event string, data interface{}) (Job, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Job{}, common.NewError(101, "marshal error", err)
+	}
+	return Job{Serviceid: serviceId, Event: event, Data: raw}, nil
+}
+
+// Run dispatches the job. Deliveries are retried by the dispatcher,
+// and an error means the job is to be retried as a whole.
+func (this *Dispatcher) Run(job Job) ([]webhookdb.DeliveryEntity, error) {
+	return this.Dispatch(job.Serviceid, job.Event, job.Data)
+}
+
+// Queue queues events to deliver
+type Queue interface {
+	Enqueue(serviceId string, event string, data interface{}) error
+}
+
+// SQSQueue sends jobs to a SQS queue
+type SQSQueue struct {
+	queueUrl  string
+	sqsClient *sqs.SQS
+}
+
+// NewSQSQueue return SQS Queue
+func NewSQSQueue(queueUrl string) (*SQSQueue, error) {
+	cfg, err := external.LoadDefaultAWSConfig()
+	if err != nil {
+		return nil, common.NewError(200, "aws-sdk config error", err)
+	}
+	return &SQSQueue{
+		queueUrl:  queueUrl,
+		sqsClient: sqs.New(cfg),
+	}, nil
+}
+
+// Enqueue sends the job of the event to the queue
+func (this *SQSQueue) Enqueue(serviceId string, event string, data interface{}) error {
+	if this == nil {
+		return common.NewError(100, "nil pointer receiver", nil)
+	}
+	job, err := NewJob(serviceId, event, data)
+	if err != nil {
+		return err
+	}
+	message, err := json.Marshal(job)
+	if err != nil {
+		return common.NewError(101, "marshal error", err)
+	}
+	if _, err := this.sqsClient.SendMessageRequest(&sqs.SendMessageInput{
+		QueueUrl:    aws.String(this.queueUrl),
+		MessageBody: aws.String(string(message)),
+	}).Send(); err != nil {
+		return common.NewError(311, "sqs sendmessage error", err)
+	}
+	return nil
+}
+
+// NopQueue discards events
+type NopQueue struct{}
+
+// Enqueue does nothing
+func (NopQueue) Enqueue(serviceId string, event string, data interface{}) error {
+	return nil
+}
+
+// NewQueueFromEnv returns the SQS queue of WEBHOOK_QUEUE_URL. Events are discarded if it is not set.
+func NewQueueFromEnv() (Queue, error) {
+	if url := os.Getenv("WEBHOOK_QUEUE_URL"); url != "" {
+		return NewSQSQueue(url)
+	}
+	return NopQueue{}, nil
+}
@@ -1,5 +1,6 @@
 const fs = require('fs');
 const path = require('path');
+const crypto = require('crypto');
 const yaml = require('js-yaml');
 
 const config = yaml.safeLoad(fs.readFileSync(path.resolve(__dirname, './config.yaml'), 'utf8'));
@@ -28,9 +29,26 @@ function generatePolicy(principal_id, effect, resource, user=null) {
 }
 
 
+// caller returns the entry of config.Authorization.callers of the api key of the request, or null
+function caller(event) {
+  const headers = event.headers || {};
+  const name = Object.keys(headers).find((key) => key.toLowerCase() === 'x-api-key');
+  if (!name || !headers[name]) {
+    return null;
+  }
+  const digest = crypto.createHash('sha256').update(headers[name]).digest('hex');
+  return (config.Authorization['callers'] || {})[digest] || null;
+}
+
 exports.handler = async (event, context) => {
   try {
-    return generatePolicy('id', 'Allow', event.methodArn, {user: 'test'});
+    const granted = caller(event);
+    if (!granted || !granted.principal || !(granted.namespaces || []).length) {
+      throw new Error('Unauthorized'); // 401
+    }
+    // the namespaces (organization/team, organization/* or *) granted to the caller, comma separated
+    const namespaces = granted.namespaces.join(',');
+    return generatePolicy(granted.principal, 'Allow', event.methodArn, {user: granted.principal, namespaces: namespaces});
   }catch (error) {
     console.error(error);
     throw error;
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/google/uuid"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/comment"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	commentdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/comment"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+var commentDao commentdb.CommentRepositoryDao
+var commentInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
+
+type requestBody struct {
+	Pointer  string `json:"pointer"`  // JSON pointer of an operation or a schema. ignored for a reply
+	Body     string `json:"body"`     // required
+	ParentId string `json:"parentId"` // optional. the comment replied to
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil || commentInitError != nil || auditInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	var reqbody requestBody
+	if err := json.Unmarshal([]byte(request.Body), &reqbody); err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+	if err := comment.ValidateBody(reqbody.Body); err != nil {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: err.(*common.Error).Message,
+			},
+		})
+	}
+
+	serviceId := request.PathParameters["id"]
+	service, err := tenant.Authorize(serviceDao, request, serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	version, err := versionDao.GetVersion(serviceId, request.PathParameters["version"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if version == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10001,
+				Message: "ID and version do not exists",
+			},
+		})
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+	entity := commentdb.CommentEntity{
+		Serviceid: serviceId,
+		Id:        id.String(),
+		Version:   version.Version,
+		Pointer:   reqbody.Pointer,
+		Author:    auditdb.Principal(request),
+		Body:      reqbody.Body,
+		Createdat: time.Now().Unix() * 1000,
+	}
+
+	if reqbody.ParentId != "" {
+		// a reply joins the thread of the comment and shares its target
+		parent, err := commentDao.GetComment(serviceId, reqbody.ParentId)
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "DB Error",
+				},
+			})
+		}
+		if parent == nil {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10004,
+					Message: "Comment does not exist",
+				},
+			})
+		}
+		entity.Pointer = parent.Pointer
+		entity.Parentid = parent.Id
+		if parent.Parentid != "" {
+			entity.Parentid = parent.Parentid
+		}
+	} else {
+		key := version.Path
+		if version.Jsonpath != "" {
+			key = version.Jsonpath
+		}
+		contents, err := versionDao.DownloadVersion(os.Getenv("SWAGGER_BUCKET_NAME"), key)
+		if err != nil {
+			fmt.Println(err)
+			if err.(*common.Error).Code == 1002 {
+				return common.CreateErrorResponse(404, common.ErrorBody{
+					Error: common.ErrorElm{
+						Code:    10002,
+						Message: "Swagger file does not exist",
+					},
+				})
+			}
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "S3 Error",
+				},
+			})
+		}
+		doc, err := swagger.Parse(swagger.DetectFormat(contents), contents)
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1402,
+					Message: "Swagger Error",
+				},
+			})
+		}
+		if err := comment.ValidatePointer(doc, reqbody.Pointer); err != nil {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1301,
+					Message: err.(*common.Error).Message,
+				},
+			})
+		}
+	}
+
+	if err := commentDao.CreateComment(entity); err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1400,
+				Message: "DynamoError",
+			},
+		})
+	}
+
+	auditdb.Record(auditDao, request, serviceId, auditdb.ActionCreateComment, nil, entity)
+
+	resp, err := common.CreateResponse(201, entity)
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	commentDao, commentInitError = commentdb.NewDaoDefaultConfig(os.Getenv("COMMENTTABLENAME"))
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
+	lambda.Start(Handler)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"time"
 
@@ -11,13 +12,20 @@ import (
 	"github.com/google/uuid"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
 	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/event"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
 )
 
 var serviceDao servicedb.ServiceRepositoryDao
 var serviceInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
+var eventInitError error
 
 type requestBody struct {
 	Servicename string `json:"servicename" validate:"required"`
+	Namespace   string `json:"namespace"` // organization/team. the only namespace granted to the caller or the default one if empty
 	// Latestversion string `json:"latestversion" validate:"required"`
 	// Lastupdated   int64  `json:"lastupdated" validate:"required"`
 }
@@ -26,8 +34,7 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	// serviceDao, err := servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
 
-	// todo: duplicate ServiceName Check
-	if serviceInitError != nil {
+	if serviceInitError != nil || auditInitError != nil || eventInitError != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
 			Error: common.ErrorElm{
 				Code:    1500,
@@ -55,6 +62,48 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
+	namespace := reqbody.Namespace
+	if namespace == "" {
+		namespace = tenant.Caller(request).Namespace()
+	}
+	if err := tenant.ValidateNamespace(request, namespace); err != nil {
+		if err.(*common.Error).Code == 26002 {
+			return common.CreateErrorResponse(403, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1303,
+					Message: "Namespace Not Granted",
+				},
+			})
+		}
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: err.(*common.Error).Message,
+			},
+		})
+	}
+
+	// service names are unique per namespace. the name table reserves the name atomically on create,
+	// and the list also covers the services created before the name table
+	services, err := serviceDao.GetServiceList()
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if tenant.Conflict(services, namespace, reqbody.Servicename, "") != nil {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10003,
+				Message: "Service Name already exists in the namespace",
+			},
+		})
+	}
+
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
@@ -68,11 +117,20 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	requestEntity := servicedb.ServiceEntity{
 		Id:            id.String(),
 		Servicename:   reqbody.Servicename,
+		Namespace:     namespace,
 		Latestversion: "0.0.0",
 		Lastupdated:   time.Now().Unix() * 1000,
 	}
 
 	if _, err := serviceDao.CreateService(requestEntity); err != nil { //Todo: Error
+		if err.(*common.Error).Code == 1003 {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10003,
+					Message: "Service Name already exists in the namespace",
+				},
+			})
+		}
 		if err.(*common.Error).Code == 1001 {
 			return common.CreateErrorResponse(400, common.ErrorBody{
 				Error: common.ErrorElm{
@@ -89,6 +147,8 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
+	auditdb.Record(auditDao, request, requestEntity.Id, auditdb.ActionCreateService, nil, requestEntity)
+
 	resp, err := common.CreateResponse(201, requestEntity)
 
 	if err != nil {
@@ -105,5 +165,14 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 func main() {
 	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	if serviceInitError == nil {
+		serviceDao.SetNameTable(os.Getenv("SERVICENAMETABLENAME"))
+	}
+	var publisher event.Publisher
+	publisher, eventInitError = event.NewPublisherFromEnv()
+	if serviceInitError == nil && eventInitError == nil {
+		serviceDao.SetPublisher(publisher)
+	}
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
 	lambda.Start(Handler)
 }
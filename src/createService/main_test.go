@@ -3,11 +3,15 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/client"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
 	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/server"
 )
 
 var dynamoLocalEndpoint string = "http://localhost:8027"
@@ -16,8 +20,10 @@ func TestHandlerSuccess(t *testing.T) {
 
 	os.Setenv("AWS_DEFAULT_REGION", "ap-northeast-1")
 	os.Setenv("SERVICETABLENAME", "swagger-dev-swagger-dynamo-serviceinfo")
+	os.Setenv("AUDITTABLENAME", "swagger-dev-swagger-dynamo-audit")
 
 	serviceDao, serviceInitError = servicedb.NewDaoWithRegionAndEndpoint(os.Getenv("SERVICETABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
+	auditDao, auditInitError = auditdb.NewDaoWithRegionAndEndpoint(os.Getenv("AUDITTABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
 
 	body := map[string]interface{}{
 		"serviceName": "service",
@@ -38,3 +44,54 @@ func TestHandlerSuccess(t *testing.T) {
 	}
 	fmt.Printf("%+v\n", response.Body)
 }
+
+// serviceRepositoryDaoStub creates services in memory
+type serviceRepositoryDaoStub struct {
+	servicedb.ServiceRepositoryDao
+	services []servicedb.ServiceEntity
+}
+
+func (this *serviceRepositoryDaoStub) GetServiceList() ([]servicedb.ServiceEntity, error) {
+	return this.services, nil
+}
+
+func (this *serviceRepositoryDaoStub) CreateService(service servicedb.ServiceEntity) (*servicedb.ServiceEntity, error) {
+	this.services = append(this.services, service)
+	return &service, nil
+}
+
+type auditRepositoryDaoStub struct {
+	auditdb.AuditRepositoryDao
+}
+
+func (auditRepositoryDaoStub) PutAudit(audit auditdb.AuditEntity) error {
+	return nil
+}
+
+func TestClientCreateService(t *testing.T) {
+	stub := &serviceRepositoryDaoStub{}
+	serviceDao, serviceInitError = stub, nil
+	auditDao, auditInitError = auditRepositoryDaoStub{}, nil
+
+	ts := httptest.NewServer(server.New(server.Route{Method: "POST", Path: "services", Handler: Handler}))
+	defer ts.Close()
+
+	c := client.New(ts.URL, "")
+	service, err := c.CreateService(context.Background(), "clientservice")
+	if err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if service.Id == "" || service.Servicename != "clientservice" || len(stub.services) != 1 || stub.services[0].Id != service.Id {
+		t.Fatalf("invalid response %+v", service)
+	}
+
+	_, err = c.CreateService(context.Background(), "clientservice")
+	if e, ok := err.(*client.Error); !ok || e.StatusCode != 400 || e.Body.Error.Code != 10003 {
+		t.Fatalf("duplicated name error is expected %#v", err)
+	}
+
+	_, err = c.CreateService(context.Background(), "invalid name")
+	if e, ok := err.(*client.Error); !ok || e.StatusCode != 400 || e.Body.Error.Code != 1301 {
+		t.Fatalf("validation error is expected %#v", err)
+	}
+}
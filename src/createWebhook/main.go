@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/google/uuid"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var webhookDao webhookdb.WebhookRepositoryDao
+var webhookInitError error
+
+type requestBody struct {
+	Url    string   `json:"url" validate:"required"`
+	Secret string   `json:"secret"` // optional. generated if empty
+	Events []string `json:"events"` // optional. all events if empty
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || webhookInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	service, err := tenant.Authorize(serviceDao, request, request.PathParameters["id"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	var reqbody requestBody
+	if err := json.Unmarshal([]byte(request.Body), &reqbody); err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+
+	if !webhookdb.ValidateUrl(reqbody.Url) {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "url must be an absolute http(s) url of a public host",
+			},
+		})
+	}
+	if !webhookdb.ValidateEvents(reqbody.Events) {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1302,
+				Message: "unknown event",
+			},
+		})
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+
+	secret := reqbody.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "Internal Error",
+				},
+			})
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	requestEntity := webhookdb.WebhookEntity{
+		Serviceid:   request.PathParameters["id"],
+		Id:          id.String(),
+		Url:         reqbody.Url,
+		Secret:      secret,
+		Events:      reqbody.Events,
+		Lastupdated: time.Now().Unix() * 1000,
+	}
+
+	if _, err := webhookDao.CreateWebhook(requestEntity); err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1400,
+				Message: "DynamoError",
+			},
+		})
+	}
+
+	resp, err := common.CreateResponse(201, requestEntity)
+
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	webhookDao, webhookInitError = webhookdb.NewDaoDefaultConfig(os.Getenv("WEBHOOKTABLENAME"), os.Getenv("WEBHOOKDELIVERYTABLENAME"))
+	lambda.Start(Handler)
+}
@@ -4,22 +4,32 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
 	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/event"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/search"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
 )
 
 var serviceDao servicedb.ServiceRepositoryDao
 var serviceInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
+var eventInitError error
+var searchQueue search.Queue
+var searchInitError error
 
 func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 
 	// serviceDao, err := servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
 
-	if serviceInitError != nil {
+	if serviceInitError != nil || auditInitError != nil || eventInitError != nil || searchInitError != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
 			Error: common.ErrorElm{
 				Code:    1500,
@@ -28,6 +38,60 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
+	serviceId := request.PathParameters["id"]
+	service, err := tenant.Authorize(serviceDao, request, serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	before, err := serviceDao.DeleteService(serviceId)
+	if err != nil {
+		fmt.Println(err)
+		if err.(*common.Error).Code == 1003 {
+			return common.CreateErrorResponse(409, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1409,
+					Message: "Service was changed concurrently",
+				},
+			})
+		}
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if before.Id == "" {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	auditdb.Record(auditDao, request, serviceId, auditdb.ActionDeleteService, before, nil)
+
+	// the worker removes the versions of the service from the index
+	if err := searchQueue.Enqueue(search.Job{Serviceid: serviceId}); err != nil {
+		fmt.Println(err)
+	}
+
 	body, err := json.Marshal(map[string]interface{}{
 		"success": true,
 	})
@@ -57,5 +121,15 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 func main() {
 	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	if serviceInitError == nil {
+		serviceDao.SetNameTable(os.Getenv("SERVICENAMETABLENAME"))
+	}
+	var publisher event.Publisher
+	publisher, eventInitError = event.NewPublisherFromEnv()
+	if serviceInitError == nil && eventInitError == nil {
+		serviceDao.SetPublisher(publisher)
+	}
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
+	searchQueue, searchInitError = search.NewQueueFromEnv()
 	lambda.Start(Handler)
 }
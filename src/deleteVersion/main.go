@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/search"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/webhook"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
+var webhookQueue webhook.Queue
+var webhookInitError error
+var searchQueue search.Queue
+var searchInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil || auditInitError != nil || webhookInitError != nil || searchInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	service, err := tenant.Authorize(serviceDao, request, request.PathParameters["id"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	serviceId := request.PathParameters["id"]
+	before, err := versionDao.DeleteVersion(serviceId, request.PathParameters["version"], os.Getenv("SWAGGER_BUCKET_NAME"))
+	if err != nil && (before == nil || before.ID == "") {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if err != nil {
+		// the record is deleted, but the swagger file remains
+		fmt.Println(err)
+	}
+
+	if before.ID == "" {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10001,
+				Message: "ID and version do not exists",
+			},
+		})
+	}
+
+	auditdb.Record(auditDao, request, serviceId, auditdb.ActionDeleteVersion, before, nil)
+
+	if err := webhookQueue.Enqueue(serviceId, webhookdb.EventVersionDeleted, before); err != nil {
+		fmt.Println(err)
+	}
+
+	if err := searchQueue.Enqueue(search.Job{Serviceid: serviceId, Version: before.Version}); err != nil {
+		fmt.Println(err)
+	}
+
+	resp, err := common.CreateResponse(200, before)
+
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
+	webhookQueue, webhookInitError = webhook.NewQueueFromEnv()
+	searchQueue, searchInitError = search.NewQueueFromEnv()
+	lambda.Start(Handler)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var webhookDao webhookdb.WebhookRepositoryDao
+var webhookInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || webhookInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	service, err := tenant.Authorize(serviceDao, request, request.PathParameters["id"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	webhook, err := webhookDao.DeleteWebhook(request.PathParameters["id"], request.PathParameters["webhookid"])
+
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+
+	if webhook.Id == "" {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1404,
+				Message: "Webhook Not Found",
+			},
+		})
+	}
+
+	resp, err := common.CreateResponse(200, map[string]interface{}{
+		"success": true,
+	})
+
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	webhookDao, webhookInitError = webhookdb.NewDaoDefaultConfig(os.Getenv("WEBHOOKTABLENAME"), os.Getenv("WEBHOOKDELIVERYTABLENAME"))
+	lambda.Start(Handler)
+}
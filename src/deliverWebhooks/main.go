@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/webhook"
+)
+
+var webhookDao webhookdb.WebhookRepositoryDao
+var webhookInitError error
+
+func Handler(ctx context.Context, request events.SQSEvent) error {
+
+	if webhookInitError != nil {
+		return webhookInitError
+	}
+
+	dispatcher := webhook.NewDispatcher(webhookDao)
+	for _, message := range request.Records {
+		var job webhook.Job
+		if err := json.Unmarshal([]byte(message.Body), &job); err != nil {
+			// a broken message is never delivered
+			fmt.Println(err)
+			continue
+		}
+		// the message returns to the queue and is retried
+		if _, err := dispatcher.Run(job); err != nil {
+			fmt.Println(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	webhookDao, webhookInitError = webhookdb.NewDaoDefaultConfig(os.Getenv("WEBHOOKTABLENAME"), os.Getenv("WEBHOOKDELIVERYTABLENAME"))
+	lambda.Start(Handler)
+}
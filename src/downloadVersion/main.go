@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	service, err := tenant.Authorize(serviceDao, request, request.PathParameters["id"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	version, err := versionDao.GetVersion(request.PathParameters["id"], request.PathParameters["version"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if version == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10001,
+				Message: "ID and version do not exists",
+			},
+		})
+	}
+
+	convert := request.QueryStringParameters["convert"]
+	if convert != "" && convert != "oas3" {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "convert must be oas3",
+			},
+		})
+	}
+	outputFormat := request.QueryStringParameters["format"]
+	if outputFormat != "" && outputFormat != "yaml" && outputFormat != "yml" && outputFormat != "json" {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1401,
+				Message: "FileFormat Error",
+			},
+		})
+	}
+	if (convert != "" || outputFormat != "") && request.QueryStringParameters["source"] == "true" {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "source can not be converted",
+			},
+		})
+	}
+
+	// the bundled document is served by default. source=true serves the original tree of a multi-file upload
+	key := version.Path
+	if request.QueryStringParameters["source"] == "true" {
+		if version.Sourcepath == "" {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10002,
+					Message: "Version was not uploaded as multiple files",
+				},
+			})
+		}
+		key = version.Sourcepath
+	} else if outputFormat == "json" && convert == "" && version.Jsonpath != "" {
+		key = version.Jsonpath
+	}
+
+	contents, err := versionDao.DownloadVersion(os.Getenv("SWAGGER_BUCKET_NAME"), key)
+	if err != nil {
+		fmt.Println(err)
+		if err.(*common.Error).Code == 1002 {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10002,
+					Message: "Swagger file does not exist",
+				},
+			})
+		}
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "S3 Error",
+			},
+		})
+	}
+
+	// the stored format is served unless another format is requested.
+	// the versions uploaded before the format was recorded fall back to detection
+	var storedFormat common.Format
+	switch {
+	case key == version.Jsonpath || key == version.Sourcepath:
+		storedFormat = common.Json
+	case version.Format == "yaml":
+		storedFormat = common.Yml
+	case version.Format == "json":
+		storedFormat = common.Json
+	default:
+		storedFormat = swagger.DetectFormat(contents)
+	}
+	format := storedFormat
+	switch outputFormat {
+	case "yaml", "yml":
+		format = common.Yml
+	case "json":
+		format = common.Json
+	}
+	if convert == "oas3" || format != storedFormat {
+		doc, err := swagger.Parse(storedFormat, contents)
+		if err == nil && convert == "oas3" {
+			doc = swagger.ConvertToOAS3(doc)
+		}
+		if err == nil {
+			contents, err = doc.Marshal(format)
+		}
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1402,
+					Message: "Swagger Error",
+				},
+			})
+		}
+	}
+
+	contentType := "application/x-yaml; charset=utf-8"
+	if format == common.Json {
+		contentType = "application/json; charset=utf-8"
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode:      200,
+		IsBase64Encoded: false,
+		Body:            contents,
+		Headers: map[string]string{
+			"Content-Type":                 contentType,
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Headers": "*",
+		},
+	}, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	lambda.Start(Handler)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/export"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	format := request.QueryStringParameters["format"]
+	if format != "postman" && format != "http" {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1401,
+				Message: "format must be postman or http",
+			},
+		})
+	}
+
+	serviceId, versionName := request.PathParameters["id"], request.PathParameters["version"]
+	service, err := tenant.Authorize(serviceDao, request, serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	version, err := versionDao.GetVersion(serviceId, versionName)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil || version == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10001,
+				Message: "ID and version do not exists",
+			},
+		})
+	}
+
+	key := version.Path
+	if version.Jsonpath != "" {
+		key = version.Jsonpath
+	}
+	contents, err := versionDao.DownloadVersion(os.Getenv("SWAGGER_BUCKET_NAME"), key)
+	if err != nil {
+		fmt.Println(err)
+		if err.(*common.Error).Code == 1002 {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10002,
+					Message: "Swagger file does not exist",
+				},
+			})
+		}
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "S3 Error",
+			},
+		})
+	}
+	doc, err := swagger.Parse(swagger.DetectFormat(contents), contents)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1402,
+				Message: "Swagger Error",
+			},
+		})
+	}
+
+	name := service.Servicename + " " + version.Version
+	body := export.HTTPFile(doc, name)
+	contentType, filename := "text/plain; charset=utf-8", service.Servicename+"-"+version.Version+".http"
+	if format == "postman" {
+		if body, err = export.Postman(doc, name).JSON(); err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "Internal Error",
+				},
+			})
+		}
+		contentType, filename = "application/json; charset=utf-8", service.Servicename+"-"+version.Version+".postman_collection.json"
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode:      200,
+		IsBase64Encoded: false,
+		Body:            body,
+		Headers: map[string]string{
+			"Content-Type":                 contentType,
+			"Content-Disposition":          fmt.Sprintf("attachment; filename=%q", filename),
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Headers": "*",
+		},
+	}, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	lambda.Start(Handler)
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
 	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
 )
 
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
 var versionDao versiondb.VersionRepositoryDao
 var versionInitError error
 
@@ -18,7 +23,7 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	// serviceDao, err := servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
 
-	if versionInitError != nil {
+	if serviceInitError != nil || versionInitError != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
 			Error: common.ErrorElm{
 				Code:    1500,
@@ -27,10 +32,52 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
-	versions, err := versionDao.GetAllVersions(request.PathParameters["id"])
+	service, err := tenant.Authorize(serviceDao, request, request.PathParameters["id"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	var versions []versiondb.VersionEntity
+	var next string
+	if limit := request.QueryStringParameters["limit"]; limit != "" {
+		pageSize, perr := strconv.ParseInt(limit, 10, 64)
+		if perr != nil || pageSize <= 0 {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1403,
+					Message: "limit must be a positive integer",
+				},
+			})
+		}
+		versions, next, err = versionDao.GetVersionPage(request.PathParameters["id"], pageSize, request.QueryStringParameters["next"])
+	} else {
+		versions, err = versionDao.GetAllVersions(request.PathParameters["id"])
+	}
 
 	if err != nil {
 		fmt.Println(err)
+		if err.(*common.Error).Code == 303 {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1403,
+					Message: "Invalid Page Token",
+				},
+			})
+		}
 		return common.CreateErrorResponse(500, common.ErrorBody{
 			Error: common.ErrorElm{
 				Code:    1500,
@@ -47,9 +94,13 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 			},
 		})
 	}
-	resp, err := common.CreateResponse(200, map[string]interface{}{
+	body := map[string]interface{}{
 		"Items": versions,
-	})
+	}
+	if next != "" {
+		body["Next"] = next
+	}
+	resp, err := common.CreateResponse(200, body)
 
 	if err != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
@@ -64,6 +115,7 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 }
 
 func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
 	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
 	lambda.Start(Handler)
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
 	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
 )
 
@@ -15,8 +16,10 @@ var dynamoLocalEndpoint string = "http://localhost:8027"
 func TestHandlerSuccess(t *testing.T) {
 
 	os.Setenv("AWS_DEFAULT_REGION", "ap-northeast-1")
+	os.Setenv("SERVICETABLENAME", "swagger-dev-swagger-dynamo-serviceinfo")
 	os.Setenv("VERSIONTABLENAME", "swagger-dev-swagger-dynamo-versioninfo")
 
+	serviceDao, serviceInitError = servicedb.NewDaoWithRegionAndEndpoint(os.Getenv("SERVICETABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
 	versionDao, versionInitError = versiondb.NewDaoWithRegionAndEndpoint(os.Getenv("VERSIONTABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
 
 	body := map[string]interface{}{}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || auditInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	var since int64
+	if s := request.QueryStringParameters["since"]; s != "" {
+		parsed, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || parsed < 0 {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1401,
+					Message: "since must be unix time in milliseconds",
+				},
+			})
+		}
+		since = parsed
+	}
+
+	var audits []auditdb.AuditEntity
+	var err error
+	if serviceId := request.QueryStringParameters["service"]; serviceId != "" {
+		audits, err = auditDao.GetAudits(serviceId, since)
+	} else {
+		audits, err = auditDao.GetAllAudits(since)
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+
+	// the audits of the services of other namespaces are hidden.
+	// those of deleted services are only listed to the callers granted every namespace.
+	scope := tenant.Caller(request)
+	if !scope.All() {
+		services, err := serviceDao.GetServiceList()
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "DB Error",
+				},
+			})
+		}
+		visible := map[string]bool{}
+		for _, service := range scope.Filter(services) {
+			visible[service.Id] = true
+		}
+		filtered := []auditdb.AuditEntity{}
+		for _, audit := range audits {
+			if visible[audit.Serviceid] {
+				filtered = append(filtered, audit)
+			}
+		}
+		audits = filtered
+	}
+
+	resp, err := common.CreateResponse(200, map[string]interface{}{
+		"Items": audits,
+	})
+
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
+	lambda.Start(Handler)
+}
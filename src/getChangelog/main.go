@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/changelog"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	format := request.QueryStringParameters["format"]
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "markdown" && format != "html" && format != "json" {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1401,
+				Message: "format must be markdown, html or json",
+			},
+		})
+	}
+	from, to := request.QueryStringParameters["from"], request.QueryStringParameters["to"]
+	if (from == "") != (to == "") {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "from and to must be specified together",
+			},
+		})
+	}
+
+	serviceId := request.PathParameters["id"]
+	service, err := tenant.Authorize(serviceDao, request, serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1404,
+				Message: "Service Not Found",
+			},
+		})
+	}
+
+	versions, err := versionDao.GetAllVersions(serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+
+	// the changelog between from and to, or across all versions
+	if from != "" {
+		selected := []versiondb.VersionEntity{}
+		for _, name := range []string{from, to} {
+			for _, v := range versions {
+				if v.Version == name {
+					selected = append(selected, v)
+				}
+			}
+		}
+		if len(selected) != 2 {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10001,
+					Message: "ID and version do not exists",
+				},
+			})
+		}
+		versions = selected
+	}
+
+	docs := make([]swagger.Document, len(versions))
+	for i, v := range versions {
+		contents, err := versionDao.DownloadVersion(os.Getenv("SWAGGER_BUCKET_NAME"), v.Path)
+		if err == nil {
+			docs[i], err = swagger.Parse(swagger.DetectFormat(contents), contents)
+		}
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1402,
+					Message: "Swagger Error: " + v.Version,
+				},
+			})
+		}
+	}
+
+	result := changelog.Changelog{Title: service.Servicename + " changelog"}
+	if from != "" {
+		result.Releases = []changelog.Release{changelog.NewRelease(docs[0], docs[1], versions[1].Lastupdated)}
+	} else {
+		snapshots := make([]changelog.Snapshot, len(versions))
+		for i, v := range versions {
+			snapshots[i] = changelog.Snapshot{Document: docs[i], Lastupdated: v.Lastupdated}
+		}
+		result = changelog.New(result.Title, snapshots)
+	}
+
+	if format == "json" {
+		resp, err := common.CreateResponse(200, result)
+		if err != nil {
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "Internal Error",
+				},
+			})
+		}
+		return resp, nil
+	}
+
+	body, contentType := result.Markdown(), "text/markdown; charset=utf-8"
+	if format == "html" {
+		if body, err = result.HTML(); err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "Internal Error",
+				},
+			})
+		}
+		contentType = "text/html; charset=utf-8"
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode:      200,
+		IsBase64Encoded: false,
+		Body:            body,
+		Headers: map[string]string{
+			"Content-Type":                 contentType,
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Headers": "*",
+		},
+	}, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	lambda.Start(Handler)
+}
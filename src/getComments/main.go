@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/comment"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	commentdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/comment"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+var commentDao commentdb.CommentRepositoryDao
+var commentInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil || commentInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	var resolved *bool
+	if value := request.QueryStringParameters["resolved"]; value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1301,
+					Message: "resolved must be true or false",
+				},
+			})
+		}
+		resolved = &b
+	}
+
+	serviceId := request.PathParameters["id"]
+	service, err := tenant.Authorize(serviceDao, request, serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	comments, err := commentDao.GetComments(serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	threads := comment.Threads(comments)
+
+	// the threads of a version include the threads of earlier versions whose target still exists
+	if request.PathParameters["version"] != "" {
+		version, err := versionDao.GetVersion(serviceId, request.PathParameters["version"])
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "DB Error",
+				},
+			})
+		}
+		if version == nil {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10001,
+					Message: "ID and version do not exists",
+				},
+			})
+		}
+
+		key := version.Path
+		if version.Jsonpath != "" {
+			key = version.Jsonpath
+		}
+		contents, err := versionDao.DownloadVersion(os.Getenv("SWAGGER_BUCKET_NAME"), key)
+		if err != nil {
+			fmt.Println(err)
+			if err.(*common.Error).Code == 1002 {
+				return common.CreateErrorResponse(404, common.ErrorBody{
+					Error: common.ErrorElm{
+						Code:    10002,
+						Message: "Swagger file does not exist",
+					},
+				})
+			}
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "S3 Error",
+				},
+			})
+		}
+		doc, err := swagger.Parse(swagger.DetectFormat(contents), contents)
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1402,
+					Message: "Swagger Error",
+				},
+			})
+		}
+		threads = comment.ForVersion(threads, version.Version, doc)
+	}
+
+	resp, err := common.CreateResponse(200, map[string]interface{}{
+		"Items": comment.Filter(threads, request.QueryStringParameters["pointer"], resolved),
+	})
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	commentDao, commentInitError = commentdb.NewDaoDefaultConfig(os.Getenv("COMMENTTABLENAME"))
+	lambda.Start(Handler)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/dependency"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	format := request.QueryStringParameters["format"]
+	if format != "" && format != "json" && format != "dot" {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "format must be json or dot",
+			},
+		})
+	}
+
+	services, err := serviceDao.GetServiceList()
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	services = tenant.Caller(request).Filter(services)
+
+	// the enabled versions of the consumed services
+	versions := map[string][]string{}
+	for _, provider := range dependency.Providers(services) {
+		entities, err := versionDao.GetAllVersions(provider)
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "DB Error",
+				},
+			})
+		}
+		for _, v := range entities {
+			if v.Enable {
+				versions[provider] = append(versions[provider], v.Version)
+			}
+		}
+	}
+
+	graph := dependency.New(services, versions)
+	if format == "dot" {
+		return events.APIGatewayProxyResponse{
+			StatusCode:      200,
+			IsBase64Encoded: false,
+			Body:            graph.DOT(),
+			Headers: map[string]string{
+				"Content-Type":                 "text/vnd.graphviz; charset=utf-8",
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": "*",
+			},
+		}, nil
+	}
+
+	resp, err := common.CreateResponse(200, graph)
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	lambda.Start(Handler)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/docs"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	service, err := tenant.Authorize(serviceDao, request, request.PathParameters["id"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	version, err := versionDao.GetVersion(request.PathParameters["id"], request.PathParameters["version"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if version == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10001,
+				Message: "ID and version do not exists",
+			},
+		})
+	}
+	bucketName := os.Getenv("SWAGGER_BUCKET_NAME")
+
+	// the page is rendered on the first request and cached until the version is uploaded again
+	if version.Docspath != "" {
+		page, err := versionDao.DownloadVersion(bucketName, version.Docspath)
+		if err == nil {
+			return htmlResponse(page), nil
+		}
+		fmt.Println(err)
+	}
+
+	key := version.Path
+	if version.Jsonpath != "" {
+		key = version.Jsonpath
+	}
+	contents, err := versionDao.DownloadVersion(bucketName, key)
+	if err != nil {
+		fmt.Println(err)
+		if err.(*common.Error).Code == 1002 {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10002,
+					Message: "Swagger file does not exist",
+				},
+			})
+		}
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "S3 Error",
+			},
+		})
+	}
+	var page string
+	doc, err := swagger.Parse(swagger.DetectFormat(contents), contents)
+	if err == nil {
+		page, err = docs.Render(doc)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1402,
+				Message: "Swagger Error",
+			},
+		})
+	}
+
+	// failures to cache only cost a rendering on the next request
+	docsKey := strings.TrimSuffix(version.Path, path.Ext(version.Path)) + ".docs.html"
+	if err := versionDao.UploadFile(bucketName, docsKey, page); err != nil {
+		fmt.Println(err)
+	} else if err := versionDao.SetDocspath(*version, docsKey); err != nil {
+		fmt.Println(err)
+	}
+
+	return htmlResponse(page), nil
+}
+
+func htmlResponse(page string) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		StatusCode:      200,
+		IsBase64Encoded: false,
+		Body:            page,
+		Headers: map[string]string{
+			"Content-Type":                 "text/html; charset=utf-8",
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Headers": "*",
+		},
+	}
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	lambda.Start(Handler)
+}
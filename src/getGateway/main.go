@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/gateway"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/semver"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+// maxServices bounds the documents downloaded by a request
+const maxServices = 50
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	// services=<id>[:<prefix>],...
+	var entries []string
+	for _, entry := range strings.Split(request.QueryStringParameters["services"], ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 || len(entries) > maxServices {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: fmt.Sprintf("services must list 1 to %d service ids", maxServices),
+			},
+		})
+	}
+	title := request.QueryStringParameters["title"]
+	if title == "" {
+		title = "Gateway"
+	}
+
+	bucketName := os.Getenv("SWAGGER_BUCKET_NAME")
+	missing := []gateway.Conflict{}
+	sources := []gateway.Source{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, ":", 2)
+		serviceId := parts[0]
+		service, err := tenant.Authorize(serviceDao, request, serviceId)
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "DB Error",
+				},
+			})
+		}
+		if service == nil {
+			missing = append(missing, gateway.Conflict{Kind: gateway.KindMissing, ServiceId: serviceId, Message: "service does not exist"})
+			continue
+		}
+		versions, err := versionDao.GetAllVersions(serviceId)
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "DB Error",
+				},
+			})
+		}
+
+		// the highest enabled version tagged prod
+		var promoted *versiondb.VersionEntity
+		for i, v := range versions {
+			if v.Enable && v.Tag == versiondb.PromotedTag && (promoted == nil || semver.Compare(v.Version, promoted.Version) > 0) {
+				promoted = &versions[i]
+			}
+		}
+		if promoted == nil {
+			missing = append(missing, gateway.Conflict{Kind: gateway.KindMissing, ServiceId: serviceId, Name: service.Servicename, Message: "no enabled version is tagged " + versiondb.PromotedTag})
+			continue
+		}
+
+		key := promoted.Path
+		if promoted.Jsonpath != "" {
+			key = promoted.Jsonpath
+		}
+		var doc swagger.Document
+		contents, err := versionDao.DownloadVersion(bucketName, key)
+		if err == nil {
+			doc, err = swagger.Parse(swagger.DetectFormat(contents), contents)
+		}
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1402,
+					Message: "Swagger Error: " + serviceId + " " + promoted.Version,
+				},
+			})
+		}
+
+		prefix := gateway.DefaultPrefix(service.Servicename)
+		if len(parts) == 2 {
+			prefix = "/" + strings.Trim(parts[1], "/")
+		}
+		sources = append(sources, gateway.Source{
+			ServiceId: serviceId,
+			Name:      service.Servicename,
+			Version:   promoted.Version,
+			Prefix:    prefix,
+			Doc:       doc,
+		})
+	}
+
+	result := gateway.Merge(title, sources)
+	result.Conflicts = append(missing, result.Conflicts...)
+	resp, err := common.CreateResponse(200, result)
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	lambda.Start(Handler)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/codegen"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	kind := request.QueryStringParameters["kind"]
+	if kind == "" {
+		kind = codegen.KindClient
+	}
+	if kind != codegen.KindClient && kind != codegen.KindServer {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "kind must be client or server",
+			},
+		})
+	}
+	pkg := request.QueryStringParameters["package"]
+	if pkg != "" && !codegen.ValidPackageName(pkg) {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "package must be a lowercase Go identifier",
+			},
+		})
+	}
+
+	serviceId, versionName := request.PathParameters["id"], request.PathParameters["version"]
+	service, err := tenant.Authorize(serviceDao, request, serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	version, err := versionDao.GetVersion(serviceId, versionName)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil || version == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10001,
+				Message: "ID and version do not exists",
+			},
+		})
+	}
+
+	key := version.Path
+	if version.Jsonpath != "" {
+		key = version.Jsonpath
+	}
+	contents, err := versionDao.DownloadVersion(os.Getenv("SWAGGER_BUCKET_NAME"), key)
+	if err != nil {
+		fmt.Println(err)
+		if err.(*common.Error).Code == 1002 {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10002,
+					Message: "Swagger file does not exist",
+				},
+			})
+		}
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "S3 Error",
+			},
+		})
+	}
+	var files map[string]string
+	doc, err := swagger.Parse(swagger.DetectFormat(contents), contents)
+	if err == nil {
+		files, err = codegen.Go(doc, kind, pkg)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1402,
+				Message: "Swagger Error",
+			},
+		})
+	}
+	archive, err := swagger.WriteZip(files)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1406,
+				Message: "Archive Error",
+			},
+		})
+	}
+
+	// application/zip is a binary media type of the api, so the body is decoded by API Gateway
+	return events.APIGatewayProxyResponse{
+		StatusCode:      200,
+		IsBase64Encoded: true,
+		Body:            base64.StdEncoding.EncodeToString(archive),
+		Headers: map[string]string{
+			"Content-Type":                 "application/zip",
+			"Content-Disposition":          fmt.Sprintf("attachment; filename=%q", service.Servicename+"-"+version.Version+"-go-"+kind+".zip"),
+			"Access-Control-Allow-Origin":  "*",
+			"Access-Control-Allow-Headers": "*",
+		},
+	}, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	lambda.Start(Handler)
+}
@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/client"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
 	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/server"
 )
 
 var dynamoLocalEndpoint string = "http://localhost:8027"
@@ -64,3 +67,41 @@ func TestHandlerFailure(t *testing.T) {
 	}
 	// fmt.Printf("%+v\n", response.Body)
 }
+
+type serviceRepositoryDaoStub struct {
+	servicedb.ServiceRepositoryDao
+	services []servicedb.ServiceEntity
+}
+
+func (this *serviceRepositoryDaoStub) GetService(serviceId string) (*servicedb.ServiceEntity, error) {
+	for _, s := range this.services {
+		if s.Id == serviceId {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestClientGetService(t *testing.T) {
+	serviceDao, serviceInitError = &serviceRepositoryDaoStub{services: []servicedb.ServiceEntity{
+		{Id: "s1", Servicename: "petstore"},
+		{Id: "s2", Servicename: "bookstore"},
+	}}, nil
+
+	ts := httptest.NewServer(server.New(server.Route{Method: "GET", Path: "services/{id}", Handler: Handler}))
+	defer ts.Close()
+	c := client.New(ts.URL, "")
+
+	service, err := c.GetService(context.Background(), "s2")
+	if err != nil || service.Servicename != "bookstore" {
+		t.Fatalf("failed test %#v %#v", service, err)
+	}
+
+	_, err = c.GetService(context.Background(), "unknown")
+	if !client.IsNotFound(err) {
+		t.Fatalf("404 is expected %#v", err)
+	}
+	if err.(*client.Error).Body.Error.Code != 1404 || err.(*client.Error).Body.Error.Message != "Service Not Found" {
+		t.Fatalf("error body is not decoded %#v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
 	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
 )
 
 var serviceDao servicedb.ServiceRepositoryDao
@@ -27,7 +28,7 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
-	serviceEntity, err := serviceDao.GetService(request.PathParameters["id"])
+	serviceEntity, err := tenant.Authorize(serviceDao, request, request.PathParameters["id"])
 
 	if err != nil {
 		fmt.Println(err)
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
 	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
 )
 
 var serviceDao servicedb.ServiceRepositoryDao
@@ -27,10 +29,34 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
-	services, err := serviceDao.GetServiceList()
+	var services []servicedb.ServiceEntity
+	var next string
+	var err error
+	if limit := request.QueryStringParameters["limit"]; limit != "" {
+		pageSize, perr := strconv.ParseInt(limit, 10, 64)
+		if perr != nil || pageSize <= 0 {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1403,
+					Message: "limit must be a positive integer",
+				},
+			})
+		}
+		services, next, err = serviceDao.GetServicePage(pageSize, request.QueryStringParameters["next"])
+	} else {
+		services, err = serviceDao.GetServiceList()
+	}
 
 	if err != nil {
 		fmt.Println(err)
+		if err.(*common.Error).Code == 303 {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1403,
+					Message: "Invalid Page Token",
+				},
+			})
+		}
 		return common.CreateErrorResponse(500, common.ErrorBody{
 			Error: common.ErrorElm{
 				Code:    1500,
@@ -39,6 +65,12 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
+	// only the services of the namespaces granted to the caller are listed, optionally of one of them
+	services = tenant.Caller(request).Filter(services)
+	if namespace := request.QueryStringParameters["namespace"]; namespace != "" {
+		services = tenant.Scope{namespace}.Filter(services)
+	}
+
 	if services == nil {
 		return common.CreateErrorResponse(404, common.ErrorBody{
 			Error: common.ErrorElm{
@@ -48,9 +80,13 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
-	resp, err := common.CreateResponse(200, map[string]interface{}{
+	body := map[string]interface{}{
 		"Items": services,
-	})
+	}
+	if next != "" {
+		body["Next"] = next
+	}
+	resp, err := common.CreateResponse(200, body)
 
 	if err != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/client"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/server"
+)
+
+// serviceRepositoryDaoStub pages the services with the page tokens of the dao
+type serviceRepositoryDaoStub struct {
+	servicedb.ServiceRepositoryDao
+	services []servicedb.ServiceEntity
+}
+
+func (this *serviceRepositoryDaoStub) GetServiceList() ([]servicedb.ServiceEntity, error) {
+	return this.services, nil
+}
+
+func (this *serviceRepositoryDaoStub) GetServicePage(limit int64, next string) ([]servicedb.ServiceEntity, string, error) {
+	startKey, err := common.DecodePageToken(next)
+	if err != nil {
+		return nil, "", err
+	}
+	start := 0
+	for i, s := range this.services {
+		if startKey != nil && s.Id == startKey["id"] {
+			start = i + 1
+		}
+	}
+	end := start + int(limit)
+	if end >= len(this.services) {
+		return this.services[start:], "", nil
+	}
+	return this.services[start:end], common.EncodePageToken(map[string]string{"id": this.services[end-1].Id}), nil
+}
+
+func TestClientWalkServices(t *testing.T) {
+	serviceDao, serviceInitError = &serviceRepositoryDaoStub{services: []servicedb.ServiceEntity{
+		{Id: "s1", Servicename: "petstore"},
+		{Id: "s2", Servicename: "bookstore"},
+		{Id: "s3", Servicename: "weather"},
+	}}, nil
+
+	ts := httptest.NewServer(server.New(server.Route{Method: "GET", Path: "services", Handler: Handler}))
+	defer ts.Close()
+	c := client.New(ts.URL, "")
+
+	var pages [][]servicedb.ServiceEntity
+	err := c.WalkServices(context.Background(), 2, func(page *client.ServicePage) error {
+		pages = append(pages, page.Items)
+		return nil
+	})
+	if err != nil || len(pages) != 2 || len(pages[0]) != 2 || len(pages[1]) != 1 || pages[1][0].Id != "s3" {
+		t.Fatalf("failed test %#v %#v", pages, err)
+	}
+
+	all, err := c.ListServices(context.Background())
+	if err != nil || len(all) != 3 {
+		t.Fatalf("failed test %#v %#v", all, err)
+	}
+
+	_, err = c.ListServicesPage(context.Background(), 2, "invalid token")
+	if e, ok := err.(*client.Error); !ok || e.StatusCode != 400 || e.Body.Error.Code != 1403 {
+		t.Fatalf("invalid page token is expected %#v", err)
+	}
+}
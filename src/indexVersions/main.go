@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	searchdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/search"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/search"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+var searchDao searchdb.SearchRepositoryDao
+var searchInitError error
+
+func Handler(ctx context.Context, request events.SQSEvent) error {
+
+	if serviceInitError != nil {
+		return serviceInitError
+	}
+	if versionInitError != nil {
+		return versionInitError
+	}
+	if searchInitError != nil {
+		return searchInitError
+	}
+
+	for _, message := range request.Records {
+		var job search.Job
+		if err := json.Unmarshal([]byte(message.Body), &job); err != nil {
+			// a broken message is never indexed
+			fmt.Println(err)
+			continue
+		}
+		// the message returns to the queue and is retried
+		if err := index(job); err != nil {
+			fmt.Println(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// index indexes the versions of the job as they are stored now, so that jobs may run late or twice.
+// The versions of a deleted service and the deleted versions are removed from the index.
+func index(job search.Job) error {
+	service, err := serviceDao.GetService(job.Serviceid)
+	if err != nil {
+		return err
+	}
+	versions := []string{job.Version}
+	if job.Version == "" {
+		entities, err := versionDao.GetAllVersions(job.Serviceid)
+		if err != nil {
+			return err
+		}
+		versions = []string{}
+		for _, v := range entities {
+			versions = append(versions, v.Version)
+		}
+	}
+
+	for _, version := range versions {
+		var v *versiondb.VersionEntity
+		if service != nil {
+			if v, err = versionDao.GetVersion(job.Serviceid, version); err != nil {
+				return err
+			}
+		}
+		if v == nil {
+			if err := searchDao.DeleteIndex(job.Serviceid, version); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := v.Path
+		if v.Jsonpath != "" {
+			key = v.Jsonpath
+		}
+		contents, err := versionDao.DownloadVersion(os.Getenv("SWAGGER_BUCKET_NAME"), key)
+		if err != nil {
+			return err
+		}
+		doc, err := swagger.Parse(swagger.DetectFormat(contents), contents)
+		if err != nil {
+			// the file was validated on upload: retrying does not help
+			fmt.Println(err)
+			continue
+		}
+		if err := searchDao.PutIndex(v.ID, v.Version, search.Extract(doc), v.Lastupdated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	searchDao, searchInitError = searchdb.NewDaoDefaultConfig(os.Getenv("SEARCHTABLENAME"))
+	lambda.Start(Handler)
+}
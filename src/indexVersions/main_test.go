@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	searchdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/search"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/search"
+)
+
+var petstore = `{"swagger": "2.0", "info": {"title": "petstore", "version": "1.0.0"},
+"paths": {"/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "ok"}}}}}}`
+
+type serviceRepositoryDaoStub struct {
+	servicedb.ServiceRepositoryDao
+	services map[string]servicedb.ServiceEntity
+}
+
+func (this *serviceRepositoryDaoStub) GetService(serviceId string) (*servicedb.ServiceEntity, error) {
+	if s, ok := this.services[serviceId]; ok {
+		return &s, nil
+	}
+	return nil, nil
+}
+
+type versionRepositoryDaoStub struct {
+	versiondb.VersionRepositoryDao
+	versions []versiondb.VersionEntity
+}
+
+func (this *versionRepositoryDaoStub) GetAllVersions(serviceId string) ([]versiondb.VersionEntity, error) {
+	versions := []versiondb.VersionEntity{}
+	for _, v := range this.versions {
+		if v.ID == serviceId {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+func (this *versionRepositoryDaoStub) GetVersion(serviceId string, version string) (*versiondb.VersionEntity, error) {
+	for _, v := range this.versions {
+		if v.ID == serviceId && v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (this *versionRepositoryDaoStub) DownloadVersion(bucket string, key string) (string, error) {
+	return petstore, nil
+}
+
+// searchRepositoryDaoStub keeps the entries of the indexed versions by id#version
+type searchRepositoryDaoStub struct {
+	searchdb.SearchRepositoryDao
+	index map[string][]search.Entry
+}
+
+func (this *searchRepositoryDaoStub) PutIndex(serviceId string, version string, entries []search.Entry, lastupdated int64) error {
+	this.index[serviceId+"#"+version] = entries
+	return nil
+}
+
+func (this *searchRepositoryDaoStub) DeleteIndex(serviceId string, version string) error {
+	delete(this.index, serviceId+"#"+version)
+	return nil
+}
+
+func message(job search.Job) events.SQSMessage {
+	body, _ := json.Marshal(job)
+	return events.SQSMessage{Body: string(body)}
+}
+
+func TestHandler(t *testing.T) {
+	services := &serviceRepositoryDaoStub{services: map[string]servicedb.ServiceEntity{"s1": {Id: "s1"}, "s2": {Id: "s2"}}}
+	serviceDao, serviceInitError = services, nil
+	versionDao, versionInitError = &versionRepositoryDaoStub{versions: []versiondb.VersionEntity{
+		{ID: "s1", Version: "1.0.0", Path: "swagger/s1/1.0.0.json"},
+		{ID: "s2", Version: "1.0.0", Path: "swagger/s2/1.0.0.json"},
+		{ID: "s2", Version: "2.0.0", Path: "swagger/s2/2.0.0.json"},
+	}}, nil
+	index := &searchRepositoryDaoStub{index: map[string][]search.Entry{"s1#0.9.0": {{Kind: search.KindPath, Path: "/old"}}}}
+	searchDao, searchInitError = index, nil
+
+	err := Handler(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		message(search.Job{Serviceid: "s1", Version: "1.0.0"}),
+		message(search.Job{Serviceid: "s1", Version: "0.9.0"}),
+		{Body: "broken"},
+		message(search.Job{Serviceid: "s2"}),
+	}})
+	if err != nil || len(index.index) != 3 || len(index.index["s1#1.0.0"]) == 0 || len(index.index["s2#2.0.0"]) == 0 {
+		t.Fatalf("failed test %#v %#v", index.index, err)
+	}
+
+	// the versions of a deleted service are removed from the index
+	delete(services.services, "s2")
+	if err := Handler(context.Background(), events.SQSEvent{Records: []events.SQSMessage{message(search.Job{Serviceid: "s2"})}}); err != nil {
+		t.Fatalf("failed test %#v", err)
+	}
+	if _, ok := index.index["s2#1.0.0"]; ok || len(index.index) != 1 {
+		t.Fatalf("the versions of a deleted service must be removed %#v", index.index)
+	}
+}
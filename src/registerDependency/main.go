@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/dependency"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
+
+type requestBody struct {
+	Range      string   `json:"range"`      // * if empty
+	Operations []string `json:"operations"` // "METHOD /path" or operationIds. all operations if empty
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || auditInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	var reqbody requestBody
+	if err := json.Unmarshal([]byte(request.Body), &reqbody); err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+
+	serviceId := request.PathParameters["id"]
+	providerId := request.PathParameters["provider"]
+	before, err := tenant.Authorize(serviceDao, request, serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if before == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+	provider, err := tenant.Authorize(serviceDao, request, providerId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if provider == nil {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "service " + providerId + " does not exist",
+			},
+		})
+	}
+
+	// the dependency on the provider is replaced, the others are kept
+	registered := servicedb.Dependency{ServiceId: providerId, Range: reqbody.Range, Operations: reqbody.Operations}
+	if registered.Range == "" {
+		registered.Range = "*"
+	}
+	dependencies := []servicedb.Dependency{}
+	for _, d := range before.Dependencies {
+		if d.ServiceId != providerId {
+			dependencies = append(dependencies, d)
+		}
+	}
+	dependencies = append(dependencies, registered)
+	if err := dependency.Validate(serviceId, dependencies); err != nil {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: err.(*common.Error).Message,
+			},
+		})
+	}
+
+	// the update fails if another registration changed the list since it was read
+	after, err := serviceDao.UpdateService(servicedb.UpdateServiceEntity{
+		Id:           &serviceId,
+		Dependencies: &dependencies,
+		Replaced:     &before.Dependencies,
+	})
+	if err != nil {
+		fmt.Println(err)
+		if err.(*common.Error).Code == 1004 {
+			return common.CreateErrorResponse(409, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1409,
+					Message: "dependencies were changed concurrently. retry the registration",
+				},
+			})
+		}
+		if err.(*common.Error).Code == 1002 {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10002,
+					Message: "ID does not exist",
+				},
+			})
+		}
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1400,
+				Message: "DynamoError",
+			},
+		})
+	}
+
+	auditdb.Record(auditDao, request, serviceId, auditdb.ActionUpdateDependencies, before, after)
+
+	resp, err := common.CreateResponse(200, after)
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
+	lambda.Start(Handler)
+}
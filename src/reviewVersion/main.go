@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/event"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/review"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/webhook"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
+var eventInitError error
+var webhookQueue webhook.Queue
+var webhookInitError error
+
+type requestBody struct {
+	Decision string `json:"decision"` // approve or reject
+	Comment  string `json:"comment"`  // required to reject
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil || auditInitError != nil || eventInitError != nil || webhookInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	var reqbody requestBody
+	if err := json.Unmarshal([]byte(request.Body), &reqbody); err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+
+	// reviewers belong to the namespace of the service
+	serviceId := request.PathParameters["id"]
+	service, err := tenant.Authorize(serviceDao, request, serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	before, err := versionDao.GetVersion(serviceId, request.PathParameters["version"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if before == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10001,
+				Message: "ID and version do not exists",
+			},
+		})
+	}
+
+	// a removed policy lists no reviewers: the versions it left pending are published by uploading them again
+	policy := servicedb.ReviewPolicy{}
+	if service.Review != nil {
+		policy = *service.Review
+	}
+	after := *before
+	if before.Review != nil {
+		r := *before.Review
+		r.Decisions = append([]versiondb.ReviewDecision{}, before.Review.Decisions...)
+		after.Review = &r
+	}
+	if err := review.Decide(policy, &after, auditdb.Principal(request), reqbody.Decision, reqbody.Comment, time.Now().Unix()*1000); err != nil {
+		switch err.(*common.Error).Code {
+		case review.CodeForbidden:
+			return common.CreateErrorResponse(403, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1304,
+					Message: err.(*common.Error).Message,
+				},
+			})
+		case review.CodeNotPending:
+			return common.CreateErrorResponse(409, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1409,
+					Message: err.(*common.Error).Message,
+				},
+			})
+		}
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: err.(*common.Error).Message,
+			},
+		})
+	}
+
+	// only the review and the requested state are written, so that concurrent decisions
+	// and a re-upload of the version conflict instead of overwriting each other
+	if err := versionDao.SetReview(*before, after); err != nil {
+		fmt.Println(err)
+		if err.(*common.Error).Code == 1001 {
+			return common.CreateErrorResponse(409, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1409,
+					Message: "version was replaced or reviewed concurrently. retry the review",
+				},
+			})
+		}
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1400,
+				Message: "DynamoError",
+			},
+		})
+	}
+
+	auditdb.Record(auditDao, request, serviceId, auditdb.ActionReviewVersion, before, after)
+
+	// an approval publishes the version as requested on upload
+	if err := webhookQueue.Enqueue(serviceId, webhookdb.EventVersionReviewed, after); err != nil {
+		fmt.Println(err)
+	}
+	if after.Review.Status == review.StatusApproved {
+		for _, e := range webhook.UpdateEvents(before, after) {
+			if err := webhookQueue.Enqueue(serviceId, e, after); err != nil {
+				fmt.Println(err)
+			}
+		}
+	}
+
+	resp, err := common.CreateResponse(200, after)
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	var publisher event.Publisher
+	publisher, eventInitError = event.NewPublisherFromEnv()
+	if versionInitError == nil && eventInitError == nil {
+		versionDao.SetPublisher(publisher)
+	}
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
+	webhookQueue, webhookInitError = webhook.NewQueueFromEnv()
+	lambda.Start(Handler)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	searchdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/search"
+	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/search"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+)
+
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
+var versionDao versiondb.VersionRepositoryDao
+var versionInitError error
+var searchDao searchdb.SearchRepositoryDao
+var searchInitError error
+
+// defaultLimit is the number of hits returned without the limit parameter
+const defaultLimit = 50
+
+type versionKey struct {
+	id      string
+	version string
+}
+
+type searchHit struct {
+	Serviceid   string `json:"serviceid"`
+	Servicename string `json:"servicename"`
+	Version     string `json:"version"`
+	Tag         string `json:"tag"`
+	search.Hit
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	if serviceInitError != nil || versionInitError != nil || searchInitError != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DynamoClientError",
+			},
+		})
+	}
+
+	query := request.QueryStringParameters["q"]
+	if len(search.Tokenize(query)) == 0 {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "q is required",
+			},
+		})
+	}
+	limit := defaultLimit
+	if l := request.QueryStringParameters["limit"]; l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1403,
+					Message: "limit must be a positive integer",
+				},
+			})
+		}
+		limit = n
+	}
+	serviceFilter := request.QueryStringParameters["service"]
+	tagFilter := request.QueryStringParameters["tag"]
+	methodFilter := strings.ToUpper(request.QueryStringParameters["method"])
+
+	// the entries are looked up by the longest word, and match the other words too
+	word := ""
+	for _, w := range search.Tokenize(query) {
+		if len([]rune(w)) > len([]rune(word)) {
+			word = w
+		}
+	}
+	if len([]rune(word)) < searchdb.PrefixLength {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: fmt.Sprintf("q needs a word of %d or more letters", searchdb.PrefixLength),
+			},
+		})
+	}
+
+	// the service filter is either an id or a name. only a name is looked up in the list of services
+	serviceId := ""
+	if serviceFilter != "" {
+		service, err := tenant.Authorize(serviceDao, request, serviceFilter)
+		if err == nil && service == nil {
+			var services []servicedb.ServiceEntity
+			services, err = serviceDao.GetServiceList()
+			for _, s := range tenant.Caller(request).Filter(services) {
+				if s.Servicename == serviceFilter {
+					service = &s
+					break
+				}
+			}
+		}
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "DB Error",
+				},
+			})
+		}
+		if service == nil {
+			return common.CreateErrorResponse(404, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1404,
+					Message: "Service Not Found",
+				},
+			})
+		}
+		serviceId = service.Id
+	}
+
+	postings, err := searchDao.GetPostings(word)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+
+	// an entry is listed under each of its words starting with word
+	seen := map[string]bool{}
+	versions := []versionKey{}
+	entries := map[versionKey][]search.Entry{}
+	for _, posting := range postings {
+		if seen[posting.Ref()] || (serviceId != "" && posting.ID != serviceId) {
+			continue
+		}
+		seen[posting.Ref()] = true
+		key := versionKey{posting.ID, posting.Version}
+		if _, ok := entries[key]; !ok {
+			versions = append(versions, key)
+		}
+		entries[key] = append(entries[key], posting.Entry)
+	}
+
+	// the services and the versions decide what is visible, the index only lists the entries
+	services := map[string]*servicedb.ServiceEntity{}
+	hits := []searchHit{}
+	for _, key := range versions {
+		service, ok := services[key.id]
+		if !ok {
+			service, err = tenant.Authorize(serviceDao, request, key.id)
+			if err != nil {
+				fmt.Println(err)
+				return common.CreateErrorResponse(500, common.ErrorBody{
+					Error: common.ErrorElm{
+						Code:    1500,
+						Message: "DB Error",
+					},
+				})
+			}
+			services[key.id] = service
+		}
+		// indexes of deleted services are left behind
+		if service == nil {
+			continue
+		}
+		version, err := versionDao.GetVersion(key.id, key.version)
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "DB Error",
+				},
+			})
+		}
+		if version == nil || !version.Enable || (tagFilter != "" && version.Tag != tagFilter) {
+			continue
+		}
+		for _, hit := range search.Match(entries[key], query) {
+			if methodFilter != "" && hit.Method != methodFilter {
+				continue
+			}
+			hits = append(hits, searchHit{Serviceid: key.id, Servicename: service.Servicename, Version: key.version, Tag: version.Tag, Hit: hit})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Servicename != hits[j].Servicename {
+			return hits[i].Servicename < hits[j].Servicename
+		}
+		return hits[i].Version < hits[j].Version
+	})
+
+	body := map[string]interface{}{
+		"Items": hits,
+		"Total": len(hits),
+	}
+	if len(hits) > limit {
+		body["Items"] = hits[:limit]
+	}
+	resp, err := common.CreateResponse(200, body)
+
+	if err != nil {
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "Internal Error",
+			},
+		})
+	}
+
+	return resp, nil
+}
+
+func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	searchDao, searchInitError = searchdb.NewDaoDefaultConfig(os.Getenv("SEARCHTABLENAME"))
+	lambda.Start(Handler)
+}
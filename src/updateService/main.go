@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
 	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
 )
 
 var serviceDao servicedb.ServiceRepositoryDao
 var serviceInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
 
 type requestBody struct {
 	Servicename string `json:"servicename" validate:"required"`
@@ -23,7 +28,7 @@ type requestBody struct {
 
 func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 
-	if serviceInitError != nil {
+	if serviceInitError != nil || auditInitError != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
 			Error: common.ErrorElm{
 				Code:    1500,
@@ -65,7 +70,56 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	// 	updateService.Latestversion = &reqbody.Latestversion
 	// }
 
-	if _, err := serviceDao.UpdateService(updateService); err != nil {
+	before, err := tenant.Authorize(serviceDao, request, serviceId)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if before == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	// service names are unique per namespace. the name table reserves the new name atomically on update,
+	// and the list also covers the services created before the name table
+	services, err := serviceDao.GetServiceList()
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if tenant.Conflict(services, before.Namespace, reqbody.Servicename, serviceId) != nil {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10003,
+				Message: "Service Name already exists in the namespace",
+			},
+		})
+	}
+
+	after, err := serviceDao.UpdateService(updateService)
+	if err != nil {
+		if err.(*common.Error).Code == 1003 {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    10003,
+					Message: "Service Name already exists in the namespace",
+				},
+			})
+		}
 		if err.(*common.Error).Code == 1002 {
 			return common.CreateErrorResponse(404, common.ErrorBody{
 				Error: common.ErrorElm{
@@ -82,6 +136,8 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
+	auditdb.Record(auditDao, request, serviceId, auditdb.ActionUpdateService, before, after)
+
 	body, err := json.Marshal(map[string]interface{}{
 		"success": true,
 	})
@@ -111,5 +167,9 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 func main() {
 	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
+	if serviceInitError == nil {
+		serviceDao.SetNameTable(os.Getenv("SERVICENAMETABLENAME"))
+	}
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
 	lambda.Start(Handler)
 }
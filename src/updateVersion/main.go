@@ -10,16 +10,29 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
 	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/event"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/review"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/webhook"
 )
 
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
 var versionDao versiondb.VersionRepositoryDao
 var versionInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
+var eventInitError error
+var webhookQueue webhook.Queue
+var webhookInitError error
 
 type requestBody struct {
 	// ID      string `json:"id" validate:"required"`
 	// Version string `json:"version" validate:"required"`
-	Path string `json:"path" validate:"required"`
+	Path string `json:"path"` // must be the path of the version: its swagger file is replaced by uploading
 	// Lastupdated int64  `json:"lastupdated"` validate:"required"
 	Enable bool   `json:"enable" validate:"required"`
 	Tag    string `json:"tag" validate:"required"`
@@ -30,7 +43,7 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	// serviceDao, err := servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
 
 	// todo: duplicate ServiceName Check
-	if versionInitError != nil {
+	if serviceInitError != nil || versionInitError != nil || auditInitError != nil || eventInitError != nil || webhookInitError != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
 			Error: common.ErrorElm{
 				Code:    1500,
@@ -39,6 +52,25 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
+	service, err := tenant.Authorize(serviceDao, request, request.PathParameters["id"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
 	var reqbody requestBody
 	if err := json.Unmarshal([]byte(request.Body), &reqbody); err != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
@@ -49,22 +81,60 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
-	requestEntity := versiondb.VersionEntity{
-		ID:          request.PathParameters["id"],
-		Version:     request.PathParameters["version"],
-		Path:        reqbody.Path,
-		Lastupdated: time.Now().Unix() * 1000,
-		Enable:      reqbody.Enable,
-		Tag:         reqbody.Tag,
+	before, err := versionDao.GetVersion(request.PathParameters["id"], request.PathParameters["version"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if before == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10001,
+				Message: "ID and version do not exists",
+			},
+		})
+	}
+	// the key is in the storage prefix of the tenant and its contents were reviewed:
+	// pointing the version at another key would bypass both
+	if reqbody.Path != "" && reqbody.Path != before.Path {
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1301,
+				Message: "path cannot be changed: upload the version to replace its swagger file",
+			},
+		})
+	}
+
+	// the swagger file is kept, and so are its JSON rendition, inventory, index and documentation
+	requestEntity := *before
+	requestEntity.Lastupdated = time.Now().Unix() * 1000
+	requestEntity.Enable = reqbody.Enable
+	requestEntity.Tag = reqbody.Tag
+
+	// versions under review are enabled and promoted by their approval only
+	if err := review.CheckUpdate(*before, requestEntity.Enable, requestEntity.Tag); err != nil {
+		return common.CreateErrorResponse(409, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1409,
+				Message: err.(*common.Error).Message,
+			},
+		})
 	}
 
-	if _, err := versionDao.UpdateVersion(requestEntity); err != nil { //Todo: Error
-		fmt.Println(err.(*common.Error).Error())
+	// only the requested state is written, so that a review decision or a re-upload
+	// since the version was read conflicts instead of being overwritten
+	if err := versionDao.SetStatus(*before, requestEntity); err != nil {
+		fmt.Println(err)
 		if err.(*common.Error).Code == 1001 {
-			return common.CreateErrorResponse(404, common.ErrorBody{
+			return common.CreateErrorResponse(409, common.ErrorBody{
 				Error: common.ErrorElm{
-					Code:    10001,
-					Message: "ID and version do not exists",
+					Code:    1409,
+					Message: "version was replaced or reviewed concurrently. retry the update",
 				},
 			})
 		}
@@ -76,6 +146,14 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
+	auditdb.Record(auditDao, request, requestEntity.ID, auditdb.ActionUpdateVersion, before, requestEntity)
+
+	for _, event := range webhook.UpdateEvents(before, requestEntity) {
+		if err := webhookQueue.Enqueue(requestEntity.ID, event, requestEntity); err != nil {
+			fmt.Println(err)
+		}
+	}
+
 	resp, err := common.CreateResponse(200, requestEntity)
 
 	if err != nil {
@@ -91,6 +169,14 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 }
 
 func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
 	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	var publisher event.Publisher
+	publisher, eventInitError = event.NewPublisherFromEnv()
+	if versionInitError == nil && eventInitError == nil {
+		versionDao.SetPublisher(publisher)
+	}
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
+	webhookQueue, webhookInitError = webhook.NewQueueFromEnv()
 	lambda.Start(Handler)
 }
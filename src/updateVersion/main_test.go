@@ -6,7 +6,11 @@ import (
 	"testing"
 
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
 	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/webhook"
 )
 
 var dynamoLocalEndpoint string = "http://localhost:8027"
@@ -14,13 +18,17 @@ var dynamoLocalEndpoint string = "http://localhost:8027"
 func TestHandlerSuccess(t *testing.T) {
 
 	os.Setenv("AWS_DEFAULT_REGION", "ap-northeast-1")
+	os.Setenv("SERVICETABLENAME", "swagger-dev-swagger-dynamo-serviceinfo")
 	os.Setenv("VERSIONTABLENAME", "swagger-dev-swagger-dynamo-versioninfo")
+	os.Setenv("AUDITTABLENAME", "swagger-dev-swagger-dynamo-audit")
 
+	serviceDao, serviceInitError = servicedb.NewDaoWithRegionAndEndpoint(os.Getenv("SERVICETABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
 	versionDao, versionInitError = versiondb.NewDaoWithRegionAndEndpoint(os.Getenv("VERSIONTABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
+	auditDao, auditInitError = auditdb.NewDaoWithRegionAndEndpoint(os.Getenv("AUDITTABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
+	webhookQueue, webhookInitError = webhook.NopQueue{}, nil
 
 	body := map[string]interface{}{
 		"enable": true,
-		"path":   "./a/b/c",
 		"tag":    "nonono",
 	}
 	queryParams := map[string]string{}
@@ -45,8 +53,10 @@ func TestHandlerSuccess(t *testing.T) {
 func TestHandlerFailureNotExistIdAndVersion(t *testing.T) {
 
 	os.Setenv("AWS_DEFAULT_REGION", "ap-northeast-1")
+	os.Setenv("SERVICETABLENAME", "swagger-dev-swagger-dynamo-serviceinfo")
 	os.Setenv("VERSIONTABLENAME", "swagger-dev-swagger-dynamo-versioninfo")
 
+	serviceDao, serviceInitError = servicedb.NewDaoWithRegionAndEndpoint(os.Getenv("SERVICETABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
 	versionDao, versionInitError = versiondb.NewDaoWithRegionAndEndpoint(os.Getenv("VERSIONTABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
 
 	body := map[string]interface{}{
@@ -73,3 +83,120 @@ func TestHandlerFailureNotExistIdAndVersion(t *testing.T) {
 
 	// fmt.Printf("%+v\n", response.Body)
 }
+
+type serviceRepositoryDaoStub struct {
+	servicedb.ServiceRepositoryDao
+	service servicedb.ServiceEntity
+}
+
+func (this *serviceRepositoryDaoStub) GetService(serviceId string) (*servicedb.ServiceEntity, error) {
+	if serviceId != this.service.Id {
+		return nil, nil
+	}
+	return &this.service, nil
+}
+
+// versionRepositoryDaoStub keeps one version
+type versionRepositoryDaoStub struct {
+	versiondb.VersionRepositoryDao
+	version versiondb.VersionEntity
+	updated []versiondb.VersionEntity
+}
+
+func (this *versionRepositoryDaoStub) GetVersion(serviceId string, version string) (*versiondb.VersionEntity, error) {
+	if serviceId != this.version.ID || version != this.version.Version {
+		return nil, nil
+	}
+	v := this.version
+	return &v, nil
+}
+
+// SetStatus fails like the conditional update if the version changed since before was read
+func (this *versionRepositoryDaoStub) SetStatus(before versiondb.VersionEntity, after versiondb.VersionEntity) error {
+	if before.Lastupdated != this.version.Lastupdated {
+		return common.NewError(1001, "the version was deleted, replaced or reviewed concurrently", nil)
+	}
+	this.updated = append(this.updated, after)
+	return nil
+}
+
+type auditRepositoryDaoStub struct {
+	auditdb.AuditRepositoryDao
+}
+
+func (auditRepositoryDaoStub) PutAudit(audit auditdb.AuditEntity) error {
+	return nil
+}
+
+func TestHandlerRejectsPathChange(t *testing.T) {
+	serviceDao, serviceInitError = &serviceRepositoryDaoStub{service: servicedb.ServiceEntity{Id: "s1", Servicename: "petstore", Namespace: "acme/pets"}}, nil
+	stub := &versionRepositoryDaoStub{version: versiondb.VersionEntity{ID: "s1", Version: "1.0.0", Path: "tenants/acme/pets/swagger/s1/1.0.0.yaml"}}
+	versionDao, versionInitError = stub, nil
+	auditDao, auditInitError = auditRepositoryDaoStub{}, nil
+	webhookQueue, webhookInitError = webhook.NopQueue{}, nil
+
+	pathParams := map[string]string{"id": "s1", "version": "1.0.0"}
+	request, _ := common.CreateProxyRequest(map[string]interface{}{"enable": true, "tag": "dev", "path": "tenants/other/team/swagger/s9/1.0.0.yaml"}, map[string]string{}, pathParams)
+	request.RequestContext.Authorizer = map[string]interface{}{"principalId": "id", tenant.ContextKey: "acme/pets"}
+	response, err := Handler(context.Background(), request)
+	if err != nil || response.StatusCode != 400 || len(stub.updated) != 0 {
+		t.Fatalf("a path change must be rejected %d %s %#v", response.StatusCode, response.Body, stub.updated)
+	}
+
+	request, _ = common.CreateProxyRequest(map[string]interface{}{"enable": true, "tag": "dev", "path": stub.version.Path}, map[string]string{}, pathParams)
+	request.RequestContext.Authorizer = map[string]interface{}{"principalId": "id", tenant.ContextKey: "acme/pets"}
+	response, err = Handler(context.Background(), request)
+	if err != nil || response.StatusCode != 200 || len(stub.updated) != 1 || stub.updated[0].Path != stub.version.Path {
+		t.Fatalf("the path of the version must be kept %d %s %#v", response.StatusCode, response.Body, stub.updated)
+	}
+}
+
+// changedVersionDaoStub replaces the version between the read and the update
+type changedVersionDaoStub struct {
+	*versionRepositoryDaoStub
+}
+
+func (this changedVersionDaoStub) GetVersion(serviceId string, version string) (*versiondb.VersionEntity, error) {
+	v, err := this.versionRepositoryDaoStub.GetVersion(serviceId, version)
+	this.version.Lastupdated++
+	return v, err
+}
+
+func TestHandlerConflict(t *testing.T) {
+	serviceDao, serviceInitError = &serviceRepositoryDaoStub{service: servicedb.ServiceEntity{Id: "s1", Servicename: "petstore"}}, nil
+	stub := &versionRepositoryDaoStub{version: versiondb.VersionEntity{ID: "s1", Version: "1.0.0", Path: "swagger/s1/1.0.0.yaml", Lastupdated: 1000}}
+	versionDao, versionInitError = changedVersionDaoStub{stub}, nil
+	auditDao, auditInitError = auditRepositoryDaoStub{}, nil
+	webhookQueue, webhookInitError = webhook.NopQueue{}, nil
+
+	request, _ := common.CreateProxyRequest(map[string]interface{}{"enable": true, "tag": "dev"}, map[string]string{}, map[string]string{"id": "s1", "version": "1.0.0"})
+	response, err := Handler(context.Background(), request)
+	if err != nil || response.StatusCode != 409 || len(stub.updated) != 0 {
+		t.Fatalf("a concurrent change must conflict %d %s", response.StatusCode, response.Body)
+	}
+}
+
+func TestHandlerKeepsDerivedFiles(t *testing.T) {
+	serviceDao, serviceInitError = &serviceRepositoryDaoStub{service: servicedb.ServiceEntity{Id: "s1", Servicename: "petstore"}}, nil
+	stub := &versionRepositoryDaoStub{version: versiondb.VersionEntity{
+		ID:            "s1",
+		Version:       "1.0.0",
+		Path:          "swagger/s1/1.0.0.yaml",
+		Jsonpath:      "swagger/s1/1.0.0.json",
+		Inventorypath: "swagger/s1/1.0.0.operations.json",
+		Docspath:      "swagger/s1/1.0.0.docs.html",
+	}}
+	versionDao, versionInitError = stub, nil
+	auditDao, auditInitError = auditRepositoryDaoStub{}, nil
+	webhookQueue, webhookInitError = webhook.NopQueue{}, nil
+
+	request, _ := common.CreateProxyRequest(map[string]interface{}{"enable": true, "tag": "dev"}, map[string]string{}, map[string]string{"id": "s1", "version": "1.0.0"})
+	response, err := Handler(context.Background(), request)
+	if err != nil || response.StatusCode != 200 || len(stub.updated) != 1 {
+		t.Fatalf("failed test %d %s", response.StatusCode, response.Body)
+	}
+	updated := stub.updated[0]
+	if updated.Jsonpath != stub.version.Jsonpath || updated.Inventorypath != stub.version.Inventorypath || updated.Docspath != stub.version.Docspath || !updated.Enable || updated.Tag != "dev" {
+		t.Fatalf("the files derived from the swagger file must be kept %#v", updated)
+	}
+}
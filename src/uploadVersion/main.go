@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -10,11 +11,31 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
 	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	webhookdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/webhook"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/dependency"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/event"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/lint"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/review"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/search"
+	swaggerdoc "github.com/swagger-viewer/swagger-viewer-app-v2/lib/swagger"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/tenant"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/webhook"
 )
 
+var serviceDao servicedb.ServiceRepositoryDao
+var serviceInitError error
 var versionDao versiondb.VersionRepositoryDao
 var versionInitError error
+var auditDao auditdb.AuditRepositoryDao
+var auditInitError error
+var eventInitError error
+var webhookQueue webhook.Queue
+var webhookInitError error
+var searchQueue search.Queue
+var searchInitError error
 
 type requestBody struct {
 	Enable   bool   `json:"enable" validate:"required"`
@@ -22,6 +43,20 @@ type requestBody struct {
 	Format   string `json:"format" validate:"required"`
 	Contents string `json:"contents" validate:"required"`
 	// Version  string `json:"version"` //optional
+
+	// multi-file upload: a map of path to contents or a base64 zip/tar(.gz) archive.
+	// Contents and Format are replaced by the bundled document.
+	Files   map[string]string `json:"files"`
+	Archive string            `json:"archive"`
+	Entry   string            `json:"entry"` // optional. openapi.yaml, swagger.yaml etc. at the root if empty
+}
+
+// responseBody is the uploaded version and the warnings about its consumers, with the registered
+// operations affected by breaking changes. ImpactError tells that the consumers could not be checked.
+type responseBody struct {
+	versiondb.VersionEntity
+	Warnings    []dependency.Warning `json:"warnings"`
+	ImpactError string               `json:"impactError,omitempty"`
 }
 
 type swagger struct {
@@ -33,7 +68,7 @@ type swagger struct {
 
 func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 
-	if versionInitError != nil {
+	if serviceInitError != nil || versionInitError != nil || auditInitError != nil || eventInitError != nil || webhookInitError != nil || searchInitError != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
 			Error: common.ErrorElm{
 				Code:    1500,
@@ -54,6 +89,78 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	fmt.Printf("events, %+v\n", reqbody.Contents)
 
+	// the storage keys are prefixed with the namespace of the service
+	service, err := tenant.Authorize(serviceDao, request, request.PathParameters["id"])
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
+	}
+	if service == nil {
+		return common.CreateErrorResponse(404, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    10002,
+				Message: "ID does not exist",
+			},
+		})
+	}
+
+	var tree *swaggerdoc.SourceTree
+	if len(reqbody.Files) > 0 || reqbody.Archive != "" {
+		files := reqbody.Files
+		if reqbody.Archive != "" {
+			data, err := base64.StdEncoding.DecodeString(reqbody.Archive)
+			if err == nil {
+				files, err = swaggerdoc.ReadArchive(data)
+			}
+			if err != nil {
+				fmt.Println(err)
+				return common.CreateErrorResponse(400, common.ErrorBody{
+					Error: common.ErrorElm{
+						Code:    1406,
+						Message: "Archive Error",
+					},
+				})
+			}
+		}
+		entry := reqbody.Entry
+		if entry == "" {
+			entry = swaggerdoc.DefaultEntry(files)
+		}
+		tree = &swaggerdoc.SourceTree{Entry: entry, Files: files}
+
+		doc, err := tree.Bundle()
+		if err != nil {
+			fmt.Println(err)
+			message := "Bundle Error"
+			if e, ok := err.(*common.Error); ok {
+				message += ": " + e.Message
+			}
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1406,
+					Message: message,
+				},
+			})
+		}
+		if reqbody.Contents, err = doc.Marshal(swaggerdoc.FileFormat(entry)); err != nil {
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "Internal Error",
+				},
+			})
+		}
+		reqbody.Format = "yaml"
+		if swaggerdoc.FileFormat(entry) == common.Json {
+			reqbody.Format = "json"
+		}
+	}
+
 	var fileFormat common.Format
 	if reqbody.Format == "yaml" || reqbody.Format == "yml" {
 		fileFormat = common.Yml
@@ -79,22 +186,120 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
-	var ext string
+	var ext, format string
 	if fileFormat == common.Yml {
-		ext = "json"
+		ext, format = "yml", "yaml"
 	} else {
-		ext = "yml"
+		ext, format = "json", "json"
 	}
 	bucketName := os.Getenv("SWAGGER_BUCKET_NAME")
-	keyName := fmt.Sprintf("swagger/%s/%s_%d.%s", request.PathParameters["id"], swagger.Info.Version, time.Now().Unix(), ext)
+	uploadedAt := time.Now().Unix()
+	keyPrefix := fmt.Sprintf("%s%s/%s_%d", tenant.KeyPrefix(service.Namespace), request.PathParameters["id"], swagger.Info.Version, uploadedAt)
+	keyName := keyPrefix + "." + ext
 
 	requestEntity := versiondb.VersionEntity{
 		ID:          request.PathParameters["id"],
 		Version:     swagger.Info.Version,
 		Path:        keyName,
-		Lastupdated: time.Now().Unix() * 1000,
+		Lastupdated: uploadedAt * 1000,
 		Enable:      reqbody.Enable,
 		Tag:         reqbody.Tag,
+		Format:      format,
+	}
+
+	// a canonical JSON rendition is stored alongside, so either format can be served as it is
+	doc, err := swaggerdoc.Parse(fileFormat, reqbody.Contents)
+	var canonical string
+	if err == nil {
+		canonical, err = doc.Marshal(common.Json)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(400, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1402,
+				Message: "Swagger Error",
+			},
+		})
+	}
+
+	// the lint ruleset of the service rejects the upload if it is enforced
+	if service.Lintrules != "" {
+		ruleset, err := lint.ParseRuleset(service.Lintrules)
+		if err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1407,
+					Message: "Lint Rules Error",
+				},
+			})
+		}
+		if result := lint.Lint(doc, ruleset); ruleset.Enforce && result.HasErrors() {
+			return common.CreateErrorResponse(400, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1407,
+					Message: "Lint Error: " + result.Summary(5),
+				},
+			})
+		}
+	}
+
+	jsonKey := keyPrefix + ".canonical.json"
+	if err := versionDao.UploadFile(bucketName, jsonKey, canonical); err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "S3 Error",
+			},
+		})
+	}
+	requestEntity.Jsonpath = jsonKey
+
+	// the operation inventory saves clients from parsing the whole document
+	inventoryKey := keyPrefix + ".operations.json"
+	inventory, _ := json.Marshal(doc.Inventory())
+	if err := versionDao.UploadFile(bucketName, inventoryKey, string(inventory)); err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "S3 Error",
+			},
+		})
+	}
+	requestEntity.Inventorypath = inventoryKey
+
+	if tree != nil {
+		sourceKey := keyPrefix + ".src.json"
+		source, _ := json.Marshal(tree)
+		if err := versionDao.UploadFile(bucketName, sourceKey, string(source)); err != nil {
+			fmt.Println(err)
+			return common.CreateErrorResponse(500, common.ErrorBody{
+				Error: common.ErrorElm{
+					Code:    1500,
+					Message: "S3 Error",
+				},
+			})
+		}
+		requestEntity.Sourcepath = sourceKey
+	}
+
+	// under a review policy, the version is pending (disabled) until approved, even if it replaces an approved one
+	if service.Review != nil {
+		review.Start(&requestEntity, auditdb.Principal(request))
+	}
+
+	before, err := versionDao.GetVersion(requestEntity.ID, requestEntity.Version)
+	if err != nil {
+		fmt.Println(err)
+		return common.CreateErrorResponse(500, common.ErrorBody{
+			Error: common.ErrorElm{
+				Code:    1500,
+				Message: "DB Error",
+			},
+		})
 	}
 
 	if _, err := versionDao.UploadVersion(requestEntity, bucketName, keyName, reqbody.Contents); err != nil { //Todo: Error
@@ -115,8 +320,39 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		})
 	}
 
-	resp, err := common.CreateResponse(204, "no content")
-	fmt.Println(resp)
+	auditdb.Record(auditDao, request, requestEntity.ID, auditdb.ActionUploadVersion, before, requestEntity)
+
+	// the version is indexed by the worker. search shows the versions of the version table only
+	if err := searchQueue.Enqueue(search.Job{Serviceid: requestEntity.ID, Version: requestEntity.Version}); err != nil {
+		fmt.Println(err)
+	}
+
+	// the consumers affected by the version are in the response and the webhook payload
+	warnings, err := checkConsumers(tenant.Caller(request), requestEntity.ID, doc, before, bucketName)
+	response := responseBody{
+		VersionEntity: requestEntity,
+		Warnings:      warnings,
+	}
+	if err != nil {
+		fmt.Println(err)
+		response.ImpactError = "the consumers could not be checked"
+		if cerr, ok := err.(*common.Error); ok {
+			response.ImpactError += ": " + cerr.Message
+		}
+	}
+
+	if err := webhookQueue.Enqueue(requestEntity.ID, webhookdb.EventVersionUploaded, response); err != nil {
+		fmt.Println(err)
+	}
+
+	// the documentation of the replaced file is rendered again on the next request
+	if before != nil && before.Docspath != "" {
+		if err := versionDao.DeleteFile(bucketName, before.Docspath); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	resp, err := common.CreateResponse(200, response)
 	if err != nil {
 		return common.CreateErrorResponse(500, common.ErrorBody{
 			Error: common.ErrorElm{
@@ -129,7 +365,61 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	return resp, nil
 }
 
+// checkConsumers checks the uploaded version against the ranges of the services consuming the service.
+// before is the replaced version if any. Only the consumers visible to the caller are checked.
+func checkConsumers(scope tenant.Scope, serviceId string, doc swaggerdoc.Document, before *versiondb.VersionEntity, bucketName string) ([]dependency.Warning, error) {
+	services, err := serviceDao.GetServiceList()
+	if err != nil {
+		return []dependency.Warning{}, err
+	}
+	services = scope.Filter(services)
+	entities, err := versionDao.GetAllVersions(serviceId)
+	if err != nil {
+		return []dependency.Warning{}, err
+	}
+
+	// the versions before the upload
+	keys := map[string]string{}
+	for _, v := range entities {
+		if v.Version == doc.Version() && before == nil {
+			continue
+		}
+		if v.Version == doc.Version() {
+			v = *before
+		}
+		keys[v.Version] = v.Path
+		if v.Jsonpath != "" {
+			keys[v.Version] = v.Jsonpath
+		}
+	}
+	versions := make([]string, 0, len(keys))
+	for v := range keys {
+		versions = append(versions, v)
+	}
+
+	warnings, err := dependency.Check(services, serviceId, doc, versions, func(version string) (swaggerdoc.Document, error) {
+		contents, err := versionDao.DownloadVersion(bucketName, keys[version])
+		if err != nil {
+			return nil, err
+		}
+		return swaggerdoc.Parse(swaggerdoc.DetectFormat(contents), contents)
+	})
+	if err != nil {
+		return []dependency.Warning{}, err
+	}
+	return warnings, nil
+}
+
 func main() {
+	serviceDao, serviceInitError = servicedb.NewDaoDefaultConfig(os.Getenv("SERVICETABLENAME"))
 	versionDao, versionInitError = versiondb.NewDaoDefaultConfig(os.Getenv("VERSIONTABLENAME"))
+	var publisher event.Publisher
+	publisher, eventInitError = event.NewPublisherFromEnv()
+	if versionInitError == nil && eventInitError == nil {
+		versionDao.SetPublisher(publisher)
+	}
+	auditDao, auditInitError = auditdb.NewDaoDefaultConfig(os.Getenv("AUDITTABLENAME"))
+	webhookQueue, webhookInitError = webhook.NewQueueFromEnv()
+	searchQueue, searchInitError = search.NewQueueFromEnv()
 	lambda.Start(Handler)
 }
@@ -7,7 +7,11 @@ import (
 	"testing"
 
 	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/common"
+	servicedb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db"
+	auditdb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/audit"
 	versiondb "github.com/swagger-viewer/swagger-viewer-app-v2/lib/db/version"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/search"
+	"github.com/swagger-viewer/swagger-viewer-app-v2/lib/webhook"
 )
 
 var dynamoLocalEndpoint string = "http://localhost:8027"
@@ -16,9 +20,13 @@ var s3LocalEndpoint string = "http://localhost:4568"
 func TestHandlerSuccess(t *testing.T) {
 
 	os.Setenv("AWS_DEFAULT_REGION", "ap-northeast-1")
+	os.Setenv("SERVICETABLENAME", "swagger-dev-swagger-dynamo-serviceinfo")
 	os.Setenv("VERSIONTABLENAME", "swagger-dev-swagger-dynamo-versioninfo")
+	os.Setenv("AUDITTABLENAME", "swagger-dev-swagger-dynamo-audit")
+	os.Setenv("SEARCHTABLENAME", "swagger-dev-swagger-dynamo-searchpostings")
 	os.Setenv("SWAGGER_BUCKET_NAME", "swagger-repository-test")
 
+	serviceDao, serviceInitError = servicedb.NewDaoWithRegionAndEndpoint(os.Getenv("SERVICETABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
 	versionDao, versionInitError = versiondb.NewDaoWithEndpoints(
 		os.Getenv("VERSIONTABLENAME"),
 		versiondb.AwsEndpoint{
@@ -30,6 +38,9 @@ func TestHandlerSuccess(t *testing.T) {
 			Endpoint: s3LocalEndpoint,
 		},
 	)
+	auditDao, auditInitError = auditdb.NewDaoWithRegionAndEndpoint(os.Getenv("AUDITTABLENAME"), os.Getenv("AWS_DEFAULT_REGION"), dynamoLocalEndpoint)
+	webhookQueue, webhookInitError = webhook.NopQueue{}, nil
+	searchQueue, searchInitError = search.NopQueue{}, nil
 
 	yamlInput := `
 swagger: '2.0'